@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/format"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
+)
+
+// commandTimeout bounds fetching the data printed by a command.
+const commandTimeout = 30 * time.Second
+
+// sparkBars are the bars of a sparkline, from the lowest to the highest value.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// commands are the subcommands by name.
+var commands = map[string]func(args []string) error{
+	"serve":    serve,
+	"generate": generate,
+	"status":   printStatus,
+	"players":  printPlayers,
+	"news":     printNews,
+	"history":  printHistory,
+	"import":   importHistory,
+	"tui":      runTUI,
+}
+
+// commandNames returns the names of the subcommands in alphabetical order.
+func commandNames() []string {
+	return slices.Sorted(maps.Keys(commands))
+}
+
+// newCommandFlags returns the flags of a printing command, with the --json
+// flag every one of them has.
+func newCommandFlags(name string) (*flag.FlagSet, *bool) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print JSON instead of text")
+	return flags, asJSON
+}
+
+// commandContext returns the context the data of a command is fetched with.
+func commandContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), commandTimeout)
+}
+
+// writeJSON prints value as indented JSON.
+func writeJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// printStatus prints the state of every region.
+func printStatus(args []string) error {
+	flags, asJSON := newCommandFlags("status")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := commandContext()
+	defer cancel()
+	status, err := component.FetchServerStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return writeJSON(os.Stdout, status.Report)
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "REGION\tSTATE\tSINCE\tSOURCE")
+	for _, region := range status.Regions {
+		since := "-"
+		if !region.Since.IsZero() {
+			since = region.Since.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", region.Name, region.State, since, region.Source)
+	}
+	return table.Flush()
+}
+
+// gamePlayers are the player counts of a game.
+type gamePlayers struct {
+	component.Game
+	Current int `json:"current"`
+	Peak    int `json:"24-peak"`
+	AllPeak int `json:"all-time-peak"`
+}
+
+// printPlayers prints the current and peak player counts of the configured games.
+func printPlayers(args []string) error {
+	flags, asJSON := newCommandFlags("players")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := commandContext()
+	defer cancel()
+	var players []gamePlayers
+	for _, game := range component.Games() {
+		counts, err := component.FetchPlayerCount(ctx, game.AppID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", game.Name, err)
+		}
+		// Steam's own count is more recent than the one of Steam Charts.
+		if current, err := component.FetchCurrentPlayers(ctx, game.AppID); err == nil && current > 0 {
+			counts.Current = current
+		}
+		players = append(players, gamePlayers{Game: game, Current: counts.Current, Peak: counts.Peak, AllPeak: counts.AllPeak})
+	}
+	if *asJSON {
+		return writeJSON(os.Stdout, players)
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "GAME\tCURRENT\t24H PEAK\tALL-TIME PEAK\t")
+	for _, game := range players {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t\n", game.Name,
+			format.Number(game.Current, i18n.Default), format.Number(game.Peak, i18n.Default), format.Number(game.AllPeak, i18n.Default))
+	}
+	return table.Flush()
+}
+
+// printNews prints the latest headlines.
+func printNews(args []string) error {
+	flags, asJSON := newCommandFlags("news")
+	count := flags.Int("n", 5, "number of headlines")
+	lang := flags.String("lang", i18n.Default, "language of the news: "+strings.Join(component.NewsLanguages, ", "))
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if !slices.Contains(component.NewsLanguages, *lang) {
+		return fmt.Errorf("no news in %q", *lang)
+	}
+
+	ctx, cancel := commandContext()
+	defer cancel()
+	feed, err := component.FetchLocalizedRSSFeed(ctx, *lang)
+	if err != nil {
+		return err
+	}
+	items := feed.Items[:min(max(*count, 0), len(feed.Items))]
+	if *asJSON {
+		return writeJSON(os.Stdout, items)
+	}
+
+	for _, item := range items {
+		date, _, _ := strings.Cut(item.PubDate, " ")
+		fmt.Printf("%s  %s\n            %s\n", date, item.Title, item.Link)
+	}
+	return nil
+}
+
+// printHistory prints the player counts polled by the server as a sparkline.
+func printHistory(args []string) error {
+	flags, asJSON := newCommandFlags("history")
+	rangeFlag := flags.String("range", "7d", "time range to print, e.g. 24h, 7d or 30d")
+	width := flags.Int("width", 60, "width of the sparkline in characters")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	span, err := parseRange(*rangeFlag)
+	if err != nil {
+		return err
+	}
+
+	store, err := history.ReadStore(filepath.Join(getDataDir(), historyFile))
+	if err != nil {
+		return err
+	}
+	end := time.Now()
+	start := end.Add(-span)
+	samples := store.Range(start, end)
+	if *asJSON {
+		if samples == nil {
+			samples = history.Series{}
+		}
+		return writeJSON(os.Stdout, samples)
+	}
+	if len(samples) == 0 {
+		return errors.New("no player counts in this range, they are recorded while the server runs")
+	}
+
+	low, high := samples[0], samples[0]
+	for _, sample := range samples {
+		if sample.Value < low.Value {
+			low = sample
+		}
+		if sample.Value > high.Value {
+			high = sample
+		}
+	}
+	fmt.Println(sparkline(samples, start, end, *width))
+	fmt.Printf("%s – %s  low %s  high %s  now %s\n",
+		start.Local().Format("2006-01-02 15:04"), end.Local().Format("2006-01-02 15:04"),
+		format.Number(low.Value, i18n.Default), format.Number(high.Value, i18n.Default),
+		format.Number(samples[len(samples)-1].Value, i18n.Default))
+	return nil
+}
+
+// parseRange parses a time range such as "90m", "24h" or "7d".
+func parseRange(raw string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid range %q", raw)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	span, err := time.ParseDuration(raw)
+	if err != nil || span <= 0 {
+		return 0, fmt.Errorf("invalid range %q", raw)
+	}
+	return span, nil
+}
+
+// sparkline draws the samples between start and end in width characters.
+// Each character shows the average of the samples in its time slot, slots
+// without samples are left blank.
+func sparkline(samples history.Series, start, end time.Time, width int) string {
+	width = max(width, 1)
+	sums := make([]int, width)
+	counts := make([]int, width)
+	slot := end.Sub(start) / time.Duration(width)
+	for _, sample := range samples {
+		i := min(int(sample.Time.Sub(start)/max(slot, 1)), width-1)
+		if i < 0 {
+			continue
+		}
+		sums[i] += sample.Value
+		counts[i]++
+	}
+
+	low, high := -1, 0
+	for i := range sums {
+		if counts[i] == 0 {
+			continue
+		}
+		sums[i] /= counts[i]
+		if low < 0 || sums[i] < low {
+			low = sums[i]
+		}
+		high = max(high, sums[i])
+	}
+
+	var line strings.Builder
+	for i := range sums {
+		switch {
+		case counts[i] == 0:
+			line.WriteRune(' ')
+		case high == low:
+			line.WriteRune(sparkBars[len(sparkBars)/2])
+		default:
+			line.WriteRune(sparkBars[(sums[i]-low)*(len(sparkBars)-1)/(high-low)])
+		}
+	}
+	return line.String()
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{raw: "7d", want: 7 * 24 * time.Hour},
+		{raw: "24h", want: 24 * time.Hour},
+		{raw: "90m", want: 90 * time.Minute},
+		{raw: "0d", wantErr: true},
+		{raw: "-2d", wantErr: true},
+		{raw: "-1h", wantErr: true},
+		{raw: "0s", wantErr: true},
+		{raw: "d", wantErr: true},
+		{raw: "1.5d", wantErr: true},
+		{raw: "week", wantErr: true},
+		{raw: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseRange(tt.raw)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseRange(%q) = %v, %v, want %v, error %t", tt.raw, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	start := time.Date(2025, time.October, 19, 0, 0, 0, 0, time.UTC)
+	at := func(hours int, value int) history.Sample {
+		return history.Sample{Time: start.Add(time.Duration(hours) * time.Hour), Value: value}
+	}
+	tests := []struct {
+		name    string
+		samples history.Series
+		end     time.Time
+		width   int
+		want    string
+	}{
+		{
+			name:    "scaled between low and high",
+			samples: history.Series{at(0, 10), at(1, 20), at(3, 30)},
+			end:     start.Add(4 * time.Hour),
+			width:   4,
+			want:    "▁▄ █",
+		},
+		{
+			name:    "averaged per slot",
+			samples: history.Series{at(0, 10), at(1, 30), at(2, 40)},
+			end:     start.Add(4 * time.Hour),
+			width:   2,
+			want:    "▁█",
+		},
+		{
+			name:    "width of 1",
+			samples: history.Series{at(0, 100), at(3, 200)},
+			end:     start.Add(4 * time.Hour),
+			width:   1,
+			want:    "▅",
+		},
+		{
+			name:    "width below 1",
+			samples: history.Series{at(0, 100)},
+			end:     start.Add(4 * time.Hour),
+			width:   0,
+			want:    "▅",
+		},
+		{
+			name:    "samples at the end and before the start",
+			samples: history.Series{at(-1, 5), at(0, 10), at(4, 20)},
+			end:     start.Add(4 * time.Hour),
+			width:   2,
+			want:    "▁█",
+		},
+		{
+			name:    "zero-length range",
+			samples: history.Series{at(0, 100)},
+			end:     start,
+			width:   3,
+			want:    "▅  ",
+		},
+		{
+			name:  "no samples",
+			end:   start.Add(4 * time.Hour),
+			width: 3,
+			want:  "   ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sparkline(tt.samples, start, tt.end, tt.width); got != tt.want {
+				t.Errorf("sparkline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// generateTimeout bounds collecting the data of a static export.
+const generateTimeout = time.Minute
+
+// generate writes a read-only copy of the dashboard to a directory, to be
+// hosted on any static file host. The data is collected once and baked into
+// component.StaticDataFile, running generate again, e.g. from cron, refreshes
+// it.
+func generate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	out := flags.String("out", "dist", "directory the dashboard is written to")
+	web := flags.String("web", "web", "directory of the static resources, including app.wasm")
+	prefix := flags.String("prefix", "", "path the dashboard is hosted under, e.g. /go-eso-dashboard")
+	lang := flags.String("lang", i18n.Default, "language of the dashboard")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if !i18n.Supported(*lang) {
+		return fmt.Errorf("unsupported language %q", *lang)
+	}
+	*prefix = strings.TrimSuffix(*prefix, "/")
+
+	if _, err := os.Stat(filepath.Join(*web, "app.wasm")); err != nil {
+		return fmt.Errorf("missing app.wasm, build it with GOOS=js GOARCH=wasm first: %w", err)
+	}
+	if err := os.MkdirAll(*out, 0o750); err != nil {
+		return fmt.Errorf("creating %s: %w", *out, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), generateTimeout)
+	defer cancel()
+	snapshot := component.CollectStaticSnapshot(ctx)
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("encoding data: %w", err)
+	}
+	if err = os.WriteFile(filepath.Join(*out, component.StaticDataFile), data, 0o644); err != nil { //nolint:gosec // Served publicly.
+		return fmt.Errorf("writing data: %w", err)
+	}
+
+	// The pre-rendered page shows the static banner too.
+	dataPath := path.Join("/", *prefix, component.StaticDataFile)
+	if err = os.Setenv(constant.StaticDataEnv, dataPath); err != nil {
+		return err
+	}
+
+	// The version changes with the data, so installed copies pick it up.
+	handler := newAppHandler(*lang, snapshot.Taken.UTC().Format("20060102150405"), "")
+	handler.Env[constant.StaticDataEnv] = dataPath
+	if *prefix != "" {
+		handler.Resources = app.PrefixedLocation(*prefix)
+	}
+	if err = app.GenerateStaticWebsite(*out, handler); err != nil {
+		return fmt.Errorf("generating the dashboard: %w", err)
+	}
+	if err = copyDir(*web, filepath.Join(*out, "web")); err != nil {
+		return err
+	}
+
+	log.Printf("Dashboard with %d data sources written to %s", len(snapshot.Data), *out)
+	return nil
+}
+
+// copyDir copies the files below src to dst.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, name)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0o750)
+		}
+
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("copying %s: %w", name, err)
+		}
+		if err = os.WriteFile(target, data, 0o644); err != nil { //nolint:gosec // Served publicly.
+			return fmt.Errorf("copying %s: %w", name, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/steamcharts"
+)
+
+// importedRecord is a player count of an export of /api/v1/export.
+type importedRecord struct {
+	Time        time.Time           `json:"time"`
+	Players     int                 `json:"players"`
+	Peak        int                 `json:"peak"`
+	Granularity history.Granularity `json:"granularity"`
+}
+
+// importHistory seeds the player history from the monthly table of Steam
+// Charts or from a players export of another install. The server keeps the
+// history in memory, so importing fails while it runs.
+func importHistory(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	fromSteamCharts := flags.Bool("steamcharts", false, "import the monthly averages and peaks of Steam Charts")
+	appID := flags.Int("app", component.DefaultGame.AppID, "Steam app ID read from Steam Charts")
+	file := flags.String("file", "", "players export to import, .csv, .ndjson or .json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *fromSteamCharts == (*file != "") {
+		return errors.New("import needs either --steamcharts or --file")
+	}
+
+	var samples history.Series
+	var err error
+	if *fromSteamCharts {
+		samples, err = steamChartsSamples(*appID)
+	} else {
+		samples, err = exportSamples(*file)
+	}
+	if err != nil {
+		return err
+	}
+
+	dataDir := getDataDir()
+	if err = os.MkdirAll(dataDir, 0o750); err != nil {
+		return err
+	}
+	// A running server would save its own history over the imported one.
+	lock, err := history.LockDir(dataDir)
+	if errors.Is(err, history.ErrLocked) {
+		return errors.New("the server is running on the data directory, stop it before importing")
+	}
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+	store, err := history.OpenStore(filepath.Join(dataDir, historyFile))
+	if err != nil {
+		return err
+	}
+	result := store.Import(samples)
+	fmt.Printf("Imported %d of %d player counts, %d overlapped the history\n", result.Added, len(samples), result.Overlapping)
+	if result.Expired > 0 {
+		fmt.Printf("Skipped %d polled player counts older than the %d days the server keeps, only monthly averages are kept for good\n",
+			result.Expired, int(history.StoreRetention.Hours()/24))
+	}
+	return nil
+}
+
+// steamChartsSamples returns the monthly averages of a Steam app as Monthly
+// samples. The rolling "Last 30 Days" row isn't a month and is skipped.
+func steamChartsSamples(appID int) (history.Series, error) {
+	ctx, cancel := commandContext()
+	defer cancel()
+	page, err := steamcharts.Fetch(ctx, steamcharts.AppURL(appID))
+	if err != nil {
+		return nil, err
+	}
+
+	var samples history.Series
+	for _, month := range page.Months {
+		// The rolling "Last 30 Days" row isn't a month.
+		if month.Month.IsZero() {
+			continue
+		}
+		samples = append(samples, history.Sample{
+			Time:        month.Month,
+			Value:       int(math.Round(month.Avg)),
+			Peak:        month.Peak,
+			Granularity: history.Monthly,
+		})
+	}
+	if len(samples) == 0 {
+		return nil, errors.New("steam charts has no monthly history for this app")
+	}
+	return samples, nil
+}
+
+// exportSamples reads a players export. Polled samples of the export are
+// marked as Imported, monthly ones stay Monthly.
+func exportSamples(path string) (history.Series, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []importedRecord
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		records, err = readCSVExport(f)
+	case ".ndjson", ".jsonl":
+		records, err = readNDJSONExport(f)
+	case ".json":
+		err = json.NewDecoder(f).Decode(&records)
+	default:
+		return nil, fmt.Errorf("unknown export format %q, expected .csv, .ndjson or .json", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	samples := make(history.Series, 0, len(records))
+	for _, record := range records {
+		granularity := record.Granularity
+		if granularity == history.Sampled {
+			granularity = history.Imported
+		}
+		if granularity != history.Imported && granularity != history.Monthly {
+			return nil, fmt.Errorf("reading %s: unknown granularity %q", path, record.Granularity)
+		}
+		samples = append(samples, history.Sample{Time: record.Time, Value: record.Players, Peak: record.Peak, Granularity: granularity})
+	}
+	return samples, nil
+}
+
+// readCSVExport reads a CSV export by its header, so the peak and
+// granularity columns are optional.
+func readCSVExport(r io.Reader) ([]importedRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	column := func(name string) int { return slices.Index(header, name) }
+	timeColumn, playersColumn := column("time"), column("players")
+	peakColumn, granularityColumn := column("peak"), column("granularity")
+	if timeColumn < 0 || playersColumn < 0 {
+		return nil, errors.New("not a players export, the time or players column is missing")
+	}
+	cell := func(row []string, i int) string {
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+		return row[i]
+	}
+
+	var records []importedRecord
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		record := importedRecord{Granularity: history.Granularity(cell(row, granularityColumn))}
+		if record.Time, err = time.Parse(time.RFC3339, cell(row, timeColumn)); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if record.Players, err = strconv.Atoi(cell(row, playersColumn)); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if peak := cell(row, peakColumn); peak != "" {
+			if record.Peak, err = strconv.Atoi(peak); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		records = append(records, record)
+	}
+}
+
+// readNDJSONExport reads one record per line, skipping blank lines.
+func readNDJSONExport(r io.Reader) ([]importedRecord, error) {
+	var records []importedRecord
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var record importedRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
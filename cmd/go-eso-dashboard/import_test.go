@@ -0,0 +1,149 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/api"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+)
+
+// writeExport downloads the players export of the handler in format and
+// saves it like a browser would.
+func writeExport(t *testing.T, handler http.Handler, format string) string {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, component.ExportPath+"?dataset=players&format="+format, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("exporting %s: status %d: %s", format, recorder.Code, recorder.Body)
+	}
+	path := filepath.Join(t.TempDir(), "eso-players."+format)
+	if err := os.WriteFile(path, recorder.Body.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	store, err := history.OpenStore(filepath.Join(t.TempDir(), historyFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	polled := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+	stored := history.Series{
+		{Time: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), Value: 16982, Peak: 28740, Granularity: history.Monthly},
+		{Time: polled.Add(-time.Hour), Value: 11000, Granularity: history.Imported},
+		{Time: polled, Value: 12000},
+	}
+	store.Import(stored)
+	handler := api.NewHandler(api.Config{PlayerHistory: store})
+
+	// Polled samples of another install come back as imported ones.
+	want := slices.Clone(stored)
+	want[2].Granularity = history.Imported
+	for _, format := range []string{"csv", "ndjson", "json"} {
+		t.Run(format, func(t *testing.T) {
+			samples, err := exportSamples(writeExport(t, handler, format))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(samples) != len(want) {
+				t.Fatalf("read %+v, want %+v", samples, want)
+			}
+			for i := range want {
+				if !samples[i].Time.Equal(want[i].Time) || samples[i].Value != want[i].Value ||
+					samples[i].Peak != want[i].Peak || samples[i].Granularity != want[i].Granularity {
+					t.Errorf("sample %d = %+v, want %+v", i, samples[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadCSVExport(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    []importedRecord
+		wantErr string
+	}{
+		{
+			name: "full export",
+			csv:  "time,players,peak,granularity\n2024-03-01T00:00:00Z,16982,28740,monthly\n2025-10-19T12:00:00Z,12000,,\n",
+			want: []importedRecord{
+				{Time: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), Players: 16982, Peak: 28740, Granularity: history.Monthly},
+				{Time: time.Date(2025, time.October, 19, 12, 0, 0, 0, time.UTC), Players: 12000},
+			},
+		},
+		{
+			name: "reordered without the optional columns",
+			csv:  "players,time\n12000,2025-10-19T12:00:00Z\n",
+			want: []importedRecord{{Time: time.Date(2025, time.October, 19, 12, 0, 0, 0, time.UTC), Players: 12000}},
+		},
+		{name: "header only", csv: "time,players\n"},
+		{name: "empty file", csv: "", wantErr: "EOF"},
+		{name: "status export", csv: "time,region,name,state\n", wantErr: "not a players export"},
+		{name: "invalid time", csv: "time,players\n2025-10-19T12:00:00Z,1\nyesterday,2\n", wantErr: "line 3"},
+		{name: "invalid count", csv: "time,players\n2025-10-19T12:00:00Z,many\n", wantErr: "line 2"},
+		{name: "invalid peak", csv: "time,players,peak\n2025-10-19T12:00:00Z,1,high\n", wantErr: "line 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := readCSVExport(strings.NewReader(tt.csv))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readCSVExport() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.EqualFunc(records, tt.want, func(a, b importedRecord) bool {
+				return a.Time.Equal(b.Time) && a.Players == b.Players && a.Peak == b.Peak && a.Granularity == b.Granularity
+			}) {
+				t.Errorf("readCSVExport() = %+v, want %+v", records, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadNDJSONExport(t *testing.T) {
+	records, err := readNDJSONExport(strings.NewReader("{\"time\":\"2025-10-19T12:00:00Z\",\"players\":12000}\n\n{\"time\":\"2024-03-01T00:00:00Z\",\"players\":16982,\"granularity\":\"monthly\"}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Players != 12000 || records[1].Granularity != history.Monthly {
+		t.Errorf("readNDJSONExport() = %+v, want both records", records)
+	}
+
+	if _, err = readNDJSONExport(strings.NewReader("{\"time\":\"2025-10-19T12:00:00Z\",\"players\":1}\n{\"time\":")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("readNDJSONExport() error = %v, want one at line 2", err)
+	}
+}
+
+func TestExportSamplesInvalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"players.xml":  "<players/>",
+		"players.json": `[{"time":"2025-10-19T12:00:00Z","players":1,"granularity":"hourly"}]`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := exportSamples(path); err == nil {
+			t.Errorf("exportSamples(%s) succeeded", name)
+		}
+	}
+	if _, err := exportSamples(filepath.Join(dir, "missing.csv")); err == nil {
+		t.Error("exportSamples() of a missing file succeeded")
+	}
+}
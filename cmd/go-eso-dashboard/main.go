@@ -1,18 +1,43 @@
 package main
 
 import (
+	"context"
+	"crypto/sha1" //nolint:gosec // Only used to derive the app version, like go-app does.
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/api"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/imageproxy"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/notify"
 	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/page"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/poller"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/webpush"
 	"github.com/maxence-charriere/go-app/v10/pkg/app"
 )
 
 const (
-	serverReadWriteTimeout	= 15
+	serverReadWriteTimeout  = 15
 	serverIdleTimeout       = 60
 	serverReadHeaderTimeout = 10
+	// defaultPushSubject is the push contact used when constant.PushSubjectEnv is unset.
+	defaultPushSubject = "mailto:admin@localhost"
+	// historyFile is the file in the data directory keeping the polled player counts.
+	historyFile = "player-history.json"
+	// statusHistoryFile is the file in the data directory keeping the status changes.
+	statusHistoryFile = "status-history.json"
 )
 
 // The main function is the entry point where the app is configured and started.
@@ -38,18 +63,147 @@ func main() {
 	// instructions.
 	app.RunWhenOnBrowser()
 
+	// Without a command the server is started, the other commands print the
+	// dashboard data in the terminal or export it.
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+	run, ok := commands[command]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q, expected one of %s\n", command, strings.Join(commandNames(), ", "))
+		os.Exit(2)
+	}
+	if err := run(args); err != nil && !errors.Is(err, flag.ErrHelp) {
+		log.Fatal(err)
+	}
+}
+
+// serve starts the server. The server polls the dashboard data in the
+// background and tells the push subscribers about region changes.
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", ":8000", "address the server listens on")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	dataDir := getDataDir()
+	if err := os.MkdirAll(dataDir, 0o750); err != nil {
+		return err
+	}
+	// The histories are kept in memory and saved over the files, so no import
+	// may write them while the server runs.
+	lock, err := history.LockDir(dataDir)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	apiConfig, err := setupPush(dataDir)
+	if err != nil {
+		return err
+	}
+
+	statusPoller := poller.New(constant.PollInterval, fetchStatusReport, fetchDefaultPlayers)
+	push := notify.NewPush(apiConfig.PushStore, webpush.Sender{Keys: *apiConfig.PushKeys, Subject: pushSubject(), AllowLocal: apiConfig.AllowLocalPush})
+	statusPoller.Subscribe(push.OnSnapshot)
+	go push.Run(context.Background())
+
+	// The polled player counts and status changes are kept for the history
+	// command and the export.
+	if apiConfig.PlayerHistory, err = history.OpenStore(filepath.Join(dataDir, historyFile)); err != nil {
+		return err
+	}
+	if apiConfig.StatusHistory, err = history.OpenStatusLog(filepath.Join(dataDir, statusHistoryFile)); err != nil {
+		return err
+	}
+	statusPoller.Subscribe(func(_ context.Context, _, current poller.Snapshot) {
+		if current.Players > 0 {
+			apiConfig.PlayerHistory.Add(current.Time, current.Players)
+		}
+		apiConfig.StatusHistory.Record(current.Time, current.Status.Regions)
+	})
+
+	// Digests are only mailed when an SMTP server and recipients are configured.
+	digest, err := setupDigest(dataDir)
+	if err != nil {
+		return err
+	}
+	if digest != nil {
+		statusPoller.Subscribe(digest.Recorder.OnSnapshot)
+		go digest.Run(context.Background())
+	}
+
+	go statusPoller.Run(context.Background())
+
+	// The JSON API and its OpenAPI document are served below /api/, next to
+	// the app itself. The same handler serves the maintenance calendar feed.
+	apiHandler := api.NewHandler(apiConfig)
+	http.Handle("/api/", apiHandler)
+	http.Handle("/calendar/", apiHandler)
+
+	// News thumbnails are scaled down and cached by the server.
+	imageHandler, err := setupImageProxy(dataDir)
+	if err != nil {
+		return err
+	}
+	http.Handle(component.ImageProxyPath, imageHandler)
+
 	// Finally, launching the server that serves the app is done by using the Go
 	// standard HTTP package.
 	//
 	// The Handler is an HTTP handler that serves the client and all its
-	// required resources to make it work into a web browser. Here it is
-	// configured to handle requests with a path that starts with "/".
-	http.Handle("/", &app.Handler{
+	// required resources to make it work into a web browser. One is created per
+	// language and the one matching the Accept-Language header of the browser
+	// handles requests with a path that starts with "/".
+	http.Handle("/", newLanguageHandler(apiConfig))
+
+	// Create a server with proper timeout settings
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           nil,
+		ReadTimeout:       serverReadWriteTimeout * time.Second,
+		WriteTimeout:      serverReadWriteTimeout * time.Second,
+		IdleTimeout:       serverIdleTimeout * time.Second,
+		ReadHeaderTimeout: serverReadHeaderTimeout * time.Second,
+	}
+
+	// Start the server with proper timeout configurations
+	return srv.ListenAndServe()
+}
+
+// fetchStatusReport fetches the region status from the configured providers
+// for the poller.
+func fetchStatusReport(ctx context.Context) (esostatus.Report, error) {
+	status, err := component.FetchServerStatus(ctx)
+	return status.Report, err
+}
+
+// fetchDefaultPlayers fetches the current player count of the default game
+// for the poller.
+func fetchDefaultPlayers(ctx context.Context) (int, error) {
+	return component.FetchCurrentPlayers(ctx, component.DefaultGame.AppID)
+}
+
+// getDataDir returns the directory the server keeps its files in.
+func getDataDir() string {
+	if dataDir := os.Getenv(constant.DataDirEnv); dataDir != "" {
+		return dataDir
+	}
+	return constant.DefaultDataDir
+}
+
+// newAppHandler creates the handler serving the app with its texts in lang.
+// Push subscriptions are off without a VAPID public key.
+func newAppHandler(lang, version, vapidPublicKey string) *app.Handler {
+	return &app.Handler{
 		Name:         "ESO Dashboard",
 		Title:        "ESO Dashboard",
 		ShortName:    "ESO Dashboard",
 		LoadingLabel: "ESO Dashboard data is loading ... {progress}%",
-		Lang:         "en",
+		Lang:         lang,
+		Version:      version,
 		Author:       "DanielTheDeveloper",
 		Description:  "Simple Go ESO dashboard with caching support for local deployment",
 		Icon: app.Icon{
@@ -62,23 +216,136 @@ func main() {
 			"https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css",
 			"/web/eso-dashboard.css",
 		},
-		CacheableResources: []string{
-			"/web/background-video.mp4",
+		// Forward the tracked games, regions, population and status sources,
+		// the push key and the widgets to the client.
+		Env: map[string]string{
+			constant.RegionsEnv:         os.Getenv(constant.RegionsEnv),
+			constant.GamesEnv:           os.Getenv(constant.GamesEnv),
+			constant.PopulationEnv:      os.Getenv(constant.PopulationEnv),
+			constant.StatusProvidersEnv: os.Getenv(constant.StatusProvidersEnv),
+			constant.VAPIDPublicKeyEnv:  vapidPublicKey,
+			constant.WidgetsEnv:         os.Getenv(constant.WidgetsEnv),
 		},
+	}
+}
+
+// newLanguageHandler serves the app in the supported language the browser
+// prefers, and in lite mode when it asks to save data, so pre-rendered pages
+// and the first paint are already translated and light.
+// The handlers share a version, otherwise the service worker would see an
+// update whenever the browser language changes.
+func newLanguageHandler(apiConfig api.Config) http.Handler {
+	version := fmt.Sprintf("%x", sha1.Sum([]byte(time.Now().UTC().String())))
+	handlers := make(map[string]*app.Handler, len(i18n.Languages))
+	for _, language := range i18n.Languages {
+		handlers[language.Code] = newAppHandler(language.Code, version, apiConfig.PushKeys.PublicKey())
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Language, Save-Data")
+		handlers[i18n.Negotiate(r.Header.Get("Accept-Language"))].ServeHTTP(w, component.WithSaveData(r))
 	})
-	
-	// Create a server with proper timeout settings
-	srv := &http.Server{
-		Addr:              ":8000",
-		Handler:           nil,
-		ReadTimeout:       serverReadWriteTimeout * time.Second,
-		WriteTimeout:      serverReadWriteTimeout * time.Second,
-		IdleTimeout:       serverIdleTimeout * time.Second,
-		ReadHeaderTimeout: serverReadHeaderTimeout * time.Second,
+}
+
+// setupPush loads the VAPID keys and the push subscriptions from dataDir,
+// creating the keys on the first start.
+func setupPush(dataDir string) (api.Config, error) {
+	keys, err := webpush.LoadOrCreateVAPIDKeys(filepath.Join(dataDir, "vapid.json"))
+	if err != nil {
+		return api.Config{}, err
 	}
 
-	// Start the server with proper timeout configurations
-	if err := srv.ListenAndServe(); err != nil {
-		log.Fatal(err)
+	store, err := webpush.OpenStore(filepath.Join(dataDir, "push-subscriptions.json"))
+	if err != nil {
+		return api.Config{}, err
+	}
+
+	allowLocal := false
+	if raw := os.Getenv(constant.PushAllowLocalEnv); raw != "" {
+		if allowLocal, err = strconv.ParseBool(raw); err != nil {
+			return api.Config{}, fmt.Errorf("invalid %s %q", constant.PushAllowLocalEnv, raw)
+		}
 	}
+
+	return api.Config{PushKeys: &keys, PushStore: store, AllowLocalPush: allowLocal}, nil
+}
+
+// setupImageProxy configures the image proxy from the environment, caching
+// the images below dataDir.
+func setupImageProxy(dataDir string) (imageproxy.Handler, error) {
+	cacheSize := int64(0)
+	if raw := os.Getenv(constant.ImageCacheSizeEnv); raw != "" {
+		megabytes, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || megabytes <= 0 {
+			return imageproxy.Handler{}, fmt.Errorf("invalid %s %q", constant.ImageCacheSizeEnv, raw)
+		}
+		cacheSize = megabytes << 20
+	}
+	cache, err := imageproxy.OpenCache(filepath.Join(dataDir, "images"), cacheSize)
+	if err != nil {
+		return imageproxy.Handler{}, err
+	}
+
+	var hosts []string
+	for _, host := range strings.Split(os.Getenv(constant.ImageHostsEnv), ",") {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return imageproxy.Handler{Hosts: hosts, Cache: cache}, nil
+}
+
+// pushSubject returns the contact sent to push services.
+func pushSubject() string {
+	if subject := os.Getenv(constant.PushSubjectEnv); subject != "" {
+		return subject
+	}
+	return defaultPushSubject
+}
+
+// setupDigest configures the mail digest from the environment. It returns nil
+// if no SMTP server or recipients are set.
+func setupDigest(dataDir string) (*notify.Digest, error) {
+	addr := os.Getenv(constant.SMTPAddrEnv)
+	var to []string
+	for _, recipient := range strings.Split(os.Getenv(constant.DigestToEnv), ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			to = append(to, recipient)
+		}
+	}
+	if addr == "" || len(to) == 0 {
+		return nil, nil //nolint:nilnil // Digests are optional.
+	}
+
+	schedule, err := notify.ParseSchedule(os.Getenv(constant.DigestScheduleEnv))
+	if err != nil {
+		return nil, err
+	}
+	templates, err := notify.LoadTemplates(os.Getenv(constant.DigestTextTemplateEnv), os.Getenv(constant.DigestHTMLTemplateEnv))
+	if err != nil {
+		return nil, err
+	}
+	recorder, err := notify.OpenRecorder(filepath.Join(dataDir, "digest-activity.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	from := os.Getenv(constant.DigestFromEnv)
+	if from == "" {
+		from = "eso-dashboard@localhost"
+	}
+
+	return &notify.Digest{
+		Recorder: recorder,
+		Mailer: notify.Mailer{
+			Addr:     addr,
+			Username: os.Getenv(constant.SMTPUsernameEnv),
+			Password: os.Getenv(constant.SMTPPasswordEnv),
+			From:     from,
+			To:       to,
+		},
+		Schedule:  schedule,
+		Templates: templates,
+	}, nil
 }
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/format"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/poller"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/tui"
+)
+
+const (
+	// frameInterval is how often the terminal view is redrawn, which also
+	// moves the headline ticker.
+	frameInterval = time.Second
+	// newsInterval is how often the headlines are fetched again.
+	newsInterval = 15 * time.Minute
+	// defaultTerminalWidth and defaultTerminalHeight are used when the
+	// terminal size can't be read.
+	defaultTerminalWidth  = 80
+	defaultTerminalHeight = 24
+)
+
+// stateStyles are the colour and icon of the region states, so they can be
+// told apart without colours too.
+var stateStyles = map[esostatus.State]struct {
+	colour tui.Colour
+	icon   string
+}{
+	esostatus.StateOnline:      {tui.OnGreen, "✔"},
+	esostatus.StateOffline:     {tui.OnRed, "✖"},
+	esostatus.StateMaintenance: {tui.OnAmber, "⚠"},
+	esostatus.StateUnknown:     {tui.OnGrey, "?"},
+}
+
+// liveView is the data shown by the tui command, updated by the poller.
+type liveView struct {
+	mu        sync.Mutex
+	snapshot  poller.Snapshot
+	players   history.Series
+	span      time.Duration
+	news      []component.RSSItem
+	newsAt    time.Time
+	lastError string
+}
+
+// runTUI shows a live dashboard in the terminal until it is interrupted.
+func runTUI(args []string) error {
+	flags := flag.NewFlagSet("tui", flag.ContinueOnError)
+	interval := flags.Duration("interval", constant.PollInterval, "how often the data is polled")
+	rangeFlag := flags.String("range", "24h", "time range of the player sparkline, e.g. 6h or 7d")
+	lang := flags.String("lang", i18n.Default, "language of the news: "+strings.Join(component.NewsLanguages, ", "))
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	span, err := parseRange(*rangeFlag)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(component.NewsLanguages, *lang) {
+		return fmt.Errorf("no news in %q", *lang)
+	}
+
+	view := &liveView{span: span}
+	// The player counts recorded by a server on this machine fill the
+	// sparkline until enough have been polled.
+	if store, err := history.ReadStore(filepath.Join(getDataDir(), historyFile)); err == nil {
+		view.players = store.Range(time.Now().Add(-span), time.Now())
+	}
+
+	// Logging would scroll the view, the last message is shown in its footer.
+	log.SetOutput(view)
+	log.SetFlags(log.Ltime)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	statusPoller := poller.New(*interval, fetchStatusReport, fetchDefaultPlayers)
+	statusPoller.Subscribe(func(ctx context.Context, _, current poller.Snapshot) {
+		view.update(ctx, current, *lang)
+	})
+	go statusPoller.Run(ctx)
+
+	screen, err := tui.Open(os.Stdout)
+	if err != nil {
+		return err
+	}
+	defer screen.Close()
+
+	ticker := time.NewTicker(frameInterval)
+	defer ticker.Stop()
+	for frame := 0; ; frame++ {
+		width, height := tui.Size(os.Stdout)
+		if width <= 0 || height <= 0 {
+			width, height = defaultTerminalWidth, defaultTerminalHeight
+		}
+		if err = screen.Draw(view.render(width, height, frame), width, height); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Write keeps the last logged line for the footer.
+func (v *liveView) Write(p []byte) (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	lines := strings.Split(strings.TrimSpace(string(p)), "\n")
+	v.lastError = lines[len(lines)-1]
+	return len(p), nil
+}
+
+// update records a poll and fetches the headlines when they are due.
+func (v *liveView) update(ctx context.Context, snapshot poller.Snapshot, lang string) {
+	v.mu.Lock()
+	v.snapshot = snapshot
+	if snapshot.Players > 0 {
+		v.players = v.players.Prune(snapshot.Time.Add(-v.span))
+		v.players = append(v.players, history.Sample{Time: snapshot.Time, Value: snapshot.Players})
+	}
+	newsDue := time.Since(v.newsAt) >= newsInterval
+	v.mu.Unlock()
+
+	if !newsDue {
+		return
+	}
+	feed, err := component.FetchLocalizedRSSFeed(ctx, lang)
+	if err != nil {
+		log.Println("Error fetching news:", err)
+		return
+	}
+	v.mu.Lock()
+	v.news = feed.Items
+	v.newsAt = time.Now()
+	v.mu.Unlock()
+}
+
+// render returns the lines of one frame.
+func (v *liveView) render(width, height, frame int) []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	updated := "waiting for the first poll"
+	if !v.snapshot.Time.IsZero() {
+		updated = "updated " + v.snapshot.Time.Local().Format("15:04:05")
+	}
+	lines := []string{
+		tui.Paint(tui.Pad("ESO Dashboard", width-len(updated)), tui.Bold) + tui.Paint(updated, tui.Dim),
+		"",
+		tui.Paint("Server status", tui.Bold),
+	}
+	lines = append(lines, v.renderRegions(width)...)
+
+	lines = append(lines, "", tui.Paint("Players", tui.Bold)+"  "+v.renderPlayers())
+	lines = append(lines, tui.Paint(sparkline(v.players, time.Now().Add(-v.span), time.Now(), width), tui.Cyan))
+
+	lines = append(lines, "", tui.Paint("News", tui.Bold))
+	if len(v.news) > 0 {
+		lines = append(lines, tui.Paint(ticker(v.news, width, frame), tui.Yellow))
+	}
+	// The headline list takes the lines left above the footer.
+	for _, item := range v.news[:min(len(v.news), max(height-len(lines)-2, 0))] {
+		date, _, _ := strings.Cut(item.PubDate, " ")
+		lines = append(lines, tui.Paint(date, tui.Dim)+"  "+item.Title)
+	}
+
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	footer := tui.Pad("Ctrl+C to quit", 16)
+	if v.lastError != "" {
+		footer += tui.Paint(v.lastError, tui.Red)
+	}
+	return append(lines[:height-1], tui.Paint(footer, tui.Dim))
+}
+
+// renderRegions lays the regions out in a grid of coloured cells.
+func (v *liveView) renderRegions(width int) []string {
+	regions := v.snapshot.Status.Regions
+	if len(regions) == 0 {
+		return []string{tui.Paint("no status yet", tui.Dim)}
+	}
+
+	cellWidth := 0
+	for _, region := range regions {
+		cellWidth = max(cellWidth, tui.Width(region.Name+region.State.String())+5)
+	}
+	columns := max(width/(cellWidth+1), 1)
+
+	var lines []string
+	var line strings.Builder
+	for i, region := range regions {
+		style, ok := stateStyles[region.State]
+		if !ok {
+			style = stateStyles[esostatus.StateUnknown]
+		}
+		cell := " " + style.icon + " " + region.Name + " " + region.State.String() + " "
+		line.WriteString(tui.Paint(tui.Pad(cell, cellWidth), style.colour) + " ")
+		if (i+1)%columns == 0 || i == len(regions)-1 {
+			lines = append(lines, line.String())
+			line.Reset()
+		}
+	}
+	return lines
+}
+
+// renderPlayers returns the current count with the low and high of the range.
+func (v *liveView) renderPlayers() string {
+	if len(v.players) == 0 {
+		return tui.Paint("no count yet", tui.Dim)
+	}
+	low, high := v.players[0].Value, v.players[0].Value
+	for _, sample := range v.players {
+		low, high = min(low, sample.Value), max(high, sample.Value)
+	}
+	return tui.Paint(format.Number(v.players[len(v.players)-1].Value, i18n.Default), tui.Bold) +
+		tui.Paint(fmt.Sprintf("  low %s  high %s", format.Number(low, i18n.Default), format.Number(high, i18n.Default)), tui.Dim)
+}
+
+// ticker returns the headlines as one line scrolled by frame characters.
+func ticker(items []component.RSSItem, width, frame int) string {
+	var text strings.Builder
+	for _, item := range items {
+		text.WriteString(item.Title + "  •  ")
+	}
+	runes := []rune(text.String())
+	start := frame % len(runes)
+	// Repeat the text so the line is full at any offset.
+	for len(runes)-start < width {
+		runes = append(runes, runes...)
+	}
+	return string(runes[start : start+width])
+}
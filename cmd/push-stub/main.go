@@ -0,0 +1,74 @@
+// Command push-stub is a local stand-in for a browser push service. It creates
+// a subscription, prints it in the JSON form expected by
+// POST /api/v1/push/subscriptions, and decrypts and logs every message the
+// dashboard sends to it. Run the dashboard with ESO_DASHBOARD_PUSH_ALLOW_LOCAL=true
+// so it accepts the local http endpoint.
+package main
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/webpush"
+)
+
+const readHeaderTimeout = 10 * time.Second
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8090", "address to listen on")
+	regions := flag.String("regions", "", "comma separated region IDs to watch, all when empty")
+	flag.Parse()
+
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	authSecret := make([]byte, 16)
+	if _, err = rand.Read(authSecret); err != nil {
+		log.Fatal(err)
+	}
+
+	registration := webpush.Registration{}
+	registration.Subscription.Endpoint = "http://" + *addr + "/push"
+	registration.Subscription.Keys.P256dh = base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes())
+	registration.Subscription.Keys.Auth = base64.RawURLEncoding.EncodeToString(authSecret)
+	if *regions != "" {
+		registration.Regions = strings.Split(*regions, ",")
+	}
+
+	subscription, err := json.Marshal(registration)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Register the stub with:\n  curl -X POST -d '%s' http://127.0.0.1:8000/api/v1/push/subscriptions\n", subscription)
+
+	http.HandleFunc("POST /push", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		message, err := webpush.Decrypt(key, authSecret, body)
+		if err != nil {
+			log.Println("Error decrypting push message:", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		log.Printf("Push message (TTL %s, %s): %s", r.Header.Get("TTL"), r.Header.Get("Authorization"), message)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	srv := &http.Server{Addr: *addr, ReadHeaderTimeout: readHeaderTimeout}
+	log.Fatal(srv.ListenAndServe())
+}
@@ -0,0 +1,91 @@
+// Command smtp-stub is a local stand-in for an SMTP server. It accepts every
+// mail without authentication and prints it, so digests can be tried out with
+// ESO_DASHBOARD_SMTP_ADDR=127.0.0.1:2525.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:2525", "address to listen on")
+	flag.Parse()
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println("SMTP stub listening on", *addr)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			log.Fatal(err)
+		}
+		go serve(conn)
+	}
+}
+
+// serve runs one SMTP session, speaking just enough of RFC 5321 for net/smtp.
+func serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		fmt.Fprint(conn, line+"\r\n")
+	}
+
+	reply("220 smtp-stub ready")
+	var from string
+	var to []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch command {
+		case "EHLO", "HELO":
+			reply("250 smtp-stub")
+		case "MAIL":
+			from, to = strings.TrimPrefix(line[len("MAIL"):], " FROM:"), nil
+			reply("250 OK")
+		case "RCPT":
+			to = append(to, strings.TrimPrefix(line[len("RCPT"):], " TO:"))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err = r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" || line == ".\n" {
+					break
+				}
+				// Undo dot-stuffing.
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			log.Printf("Mail from %s to %s:\n%s", from, strings.Join(to, ", "), data.String())
+			reply("250 OK")
+		case "RSET":
+			from, to = "", nil
+			reply("250 OK")
+		case "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
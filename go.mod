@@ -2,10 +2,12 @@ module github.com/DanieltheDeveloper/go-eso-dashboard.git
 
 go 1.24.2
 
-require github.com/maxence-charriere/go-app/v10 v10.1.3
+require (
+	github.com/PuerkitoBio/goquery v1.10.3
+	github.com/maxence-charriere/go-app/v10 v10.1.3
+)
 
 require (
-	github.com/PuerkitoBio/goquery v1.10.3 // indirect
 	github.com/andybalholm/cascadia v1.3.3 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	golang.org/x/net v0.39.0 // indirect
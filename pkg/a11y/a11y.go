@@ -0,0 +1,218 @@
+// Package a11y checks rendered HTML for accessibility problems that can be
+// found without a browser: missing names, languages and text alternatives,
+// broken ARIA references and duplicate IDs.
+package a11y
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Violation is a rule broken by one element.
+type Violation struct {
+	Rule    string
+	Element string
+	Message string
+}
+
+// String formats the violation for a report, e.g.
+// "button-name: <button class="btn"> has no accessible name".
+func (v Violation) String() string {
+	return v.Rule + ": " + v.Element + " " + v.Message
+}
+
+// rule checks a document and reports the elements breaking it.
+type rule struct {
+	name  string
+	check func(doc *goquery.Document, report func(s *goquery.Selection, message string))
+}
+
+// rules are the checks run by Check, in report order.
+var rules = []rule{
+	{name: "html-lang", check: checkLang},
+	{name: "image-alt", check: checkImageAlt},
+	{name: "link-name", check: checkNames("a[href]")},
+	{name: "button-name", check: checkNames("button")},
+	{name: "control-label", check: checkLabels},
+	{name: "duplicate-id", check: checkDuplicateIDs},
+	{name: "aria-reference", check: checkReferences},
+	{name: "aria-live", check: checkLive},
+	{name: "heading-order", check: checkHeadingOrder},
+	{name: "no-autoplay", check: checkAutoplay},
+}
+
+// Check parses the HTML document and returns the violations of all rules.
+func Check(r io.Reader) ([]Violation, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("parsing HTML: %w", err)
+	}
+
+	var violations []Violation
+	for _, rule := range rules {
+		rule.check(doc, func(s *goquery.Selection, message string) {
+			violations = append(violations, Violation{Rule: rule.name, Element: describe(s), Message: message})
+		})
+	}
+	return violations, nil
+}
+
+// checkLang requires the language of the page.
+func checkLang(doc *goquery.Document, report func(*goquery.Selection, string)) {
+	html := doc.Find("html")
+	if strings.TrimSpace(html.AttrOr("lang", "")) == "" {
+		report(html, "has no lang attribute")
+	}
+}
+
+// checkImageAlt requires a text alternative on every image, empty for
+// decorative ones.
+func checkImageAlt(doc *goquery.Document, report func(*goquery.Selection, string)) {
+	doc.Find("img").Each(func(_ int, img *goquery.Selection) {
+		if _, ok := img.Attr("alt"); !ok && img.AttrOr("role", "") != "presentation" {
+			report(img, "has no alt attribute")
+		}
+	})
+}
+
+// checkNames returns a check requiring an accessible name on the elements
+// matching selector.
+func checkNames(selector string) func(*goquery.Document, func(*goquery.Selection, string)) {
+	return func(doc *goquery.Document, report func(*goquery.Selection, string)) {
+		doc.Find(selector).Each(func(_ int, s *goquery.Selection) {
+			if isHidden(s) {
+				return
+			}
+			if accessibleName(s) == "" {
+				report(s, "has no accessible name")
+			}
+		})
+	}
+}
+
+// checkLabels requires a label on every form control.
+func checkLabels(doc *goquery.Document, report func(*goquery.Selection, string)) {
+	doc.Find("input, select, textarea").Each(func(_ int, s *goquery.Selection) {
+		if s.AttrOr("type", "") == "hidden" || isHidden(s) {
+			return
+		}
+		if ariaName(s) != "" || s.ParentsFiltered("label").Length() > 0 {
+			return
+		}
+		if id := s.AttrOr("id", ""); id != "" && doc.Find(`label[for="`+id+`"]`).Length() > 0 {
+			return
+		}
+		report(s, "has no label")
+	})
+}
+
+// checkDuplicateIDs requires IDs to be unique, as labels and ARIA references
+// point to the first element only.
+func checkDuplicateIDs(doc *goquery.Document, report func(*goquery.Selection, string)) {
+	seen := map[string]bool{}
+	doc.Find("[id]").Each(func(_ int, s *goquery.Selection) {
+		id := s.AttrOr("id", "")
+		if seen[id] {
+			report(s, "reuses the ID of an earlier element")
+		}
+		seen[id] = true
+	})
+}
+
+// checkReferences requires ARIA attributes that point to other elements to
+// name existing IDs.
+func checkReferences(doc *goquery.Document, report func(*goquery.Selection, string)) {
+	for _, attr := range []string{"aria-labelledby", "aria-describedby", "aria-controls"} {
+		doc.Find("[" + attr + "]").Each(func(_ int, s *goquery.Selection) {
+			for _, id := range strings.Fields(s.AttrOr(attr, "")) {
+				if doc.Find(`[id="`+id+`"]`).Length() == 0 {
+					report(s, attr+" points to the missing ID "+strconv.Quote(id))
+				}
+			}
+		})
+	}
+}
+
+// checkLive requires valid politeness values on live regions.
+func checkLive(doc *goquery.Document, report func(*goquery.Selection, string)) {
+	doc.Find("[aria-live]").Each(func(_ int, s *goquery.Selection) {
+		switch s.AttrOr("aria-live", "") {
+		case "polite", "assertive", "off":
+		default:
+			report(s, "has an invalid aria-live value")
+		}
+	})
+}
+
+// checkHeadingOrder requires one h1 and headings that don't skip levels.
+func checkHeadingOrder(doc *goquery.Document, report func(*goquery.Selection, string)) {
+	headings := doc.Find("h1, h2, h3, h4, h5, h6")
+	if doc.Find("h1").Length() != 1 {
+		report(doc.Find("body"), "must contain exactly one h1")
+	}
+
+	previous := 0
+	headings.Each(func(_ int, s *goquery.Selection) {
+		level := int(goquery.NodeName(s)[1] - '0')
+		if previous > 0 && level > previous+1 {
+			report(s, fmt.Sprintf("skips from h%d to h%d", previous, level))
+		}
+		previous = level
+	})
+}
+
+// checkAutoplay forbids media that start playing before the user can stop
+// them; pages start them from code once the user's choice is known.
+func checkAutoplay(doc *goquery.Document, report func(*goquery.Selection, string)) {
+	doc.Find("video[autoplay], audio[autoplay]").Each(func(_ int, s *goquery.Selection) {
+		report(s, "plays automatically")
+	})
+}
+
+// accessibleName returns the name assistive technology announces for an
+// element: its ARIA name or title, or else its text and image alternatives.
+func accessibleName(s *goquery.Selection) string {
+	if name := ariaName(s); name != "" {
+		return name
+	}
+
+	var text strings.Builder
+	text.WriteString(s.Text())
+	s.Find("img[alt]").Each(func(_ int, img *goquery.Selection) {
+		text.WriteString(img.AttrOr("alt", ""))
+	})
+	return strings.TrimSpace(text.String())
+}
+
+// ariaName returns the aria-label, aria-labelledby or title of an element.
+func ariaName(s *goquery.Selection) string {
+	for _, attr := range []string{"aria-label", "aria-labelledby", "title"} {
+		if value := strings.TrimSpace(s.AttrOr(attr, "")); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// isHidden reports whether the element is hidden from assistive technology.
+func isHidden(s *goquery.Selection) bool {
+	hidden := s.AttrOr("aria-hidden", "") == "true"
+	s.ParentsFiltered(`[aria-hidden="true"]`).Each(func(int, *goquery.Selection) { hidden = true })
+	return hidden
+}
+
+// describe returns the start tag of an element with its ID and classes, to
+// find it in the markup.
+func describe(s *goquery.Selection) string {
+	tag := "<" + goquery.NodeName(s)
+	for _, attr := range []string{"id", "class"} {
+		if value, ok := s.Attr(attr); ok {
+			tag += " " + attr + "=" + strconv.Quote(value)
+		}
+	}
+	return tag + ">"
+}
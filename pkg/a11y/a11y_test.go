@@ -0,0 +1,106 @@
+package a11y_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/a11y"
+)
+
+// page wraps body in a document that passes every rule by itself.
+func page(body string) string {
+	return `<!DOCTYPE html><html lang="en"><head><title>Test</title></head><body><h1>Dashboard</h1>` + body + `</body></html>`
+}
+
+// rules returns the rules broken by the document, in report order.
+func rules(t *testing.T, html string) []string {
+	t.Helper()
+	violations, err := a11y.Check(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		broken = append(broken, violation.Rule)
+	}
+	return broken
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want []string
+	}{
+		{name: "valid page", html: page("")},
+		{name: "no lang", html: `<html><body><h1>Dashboard</h1></body></html>`, want: []string{"html-lang"}},
+		{name: "blank lang", html: `<html lang=" "><body><h1>Dashboard</h1></body></html>`, want: []string{"html-lang"}},
+
+		{name: "image without alt", html: page(`<img src="a.png">`), want: []string{"image-alt"}},
+		{name: "decorative image", html: page(`<img src="a.png" alt="">`)},
+		{name: "presentational image", html: page(`<img src="a.png" role="presentation">`)},
+
+		{name: "link without name", html: page(`<a href="/news"><span></span></a>`), want: []string{"link-name"}},
+		{name: "link named by image", html: page(`<a href="/news"><img src="a.png" alt="News"></a>`)},
+		{name: "anchor without href", html: page(`<a id="top"></a>`)},
+		{name: "button without name", html: page(`<button class="btn"><i class="bi bi-x"></i></button>`), want: []string{"button-name"}},
+		{name: "button with label", html: page(`<button aria-label="Close"><i class="bi bi-x"></i></button>`)},
+		{name: "button with title", html: page(`<button title="Close"></button>`)},
+		{name: "hidden button", html: page(`<div aria-hidden="true"><button></button></div>`)},
+
+		{name: "input without label", html: page(`<input type="text">`), want: []string{"control-label"}},
+		{name: "input with label for", html: page(`<label for="q">Search</label><input id="q">`)},
+		{name: "input in label", html: page(`<label>Search <select><option>A</option></select></label>`)},
+		{name: "textarea with aria-label", html: page(`<textarea aria-label="Message"></textarea>`)},
+		{name: "hidden input", html: page(`<input type="hidden" name="token">`)},
+
+		{name: "duplicate ID", html: page(`<div id="a"></div><div id="a"></div>`), want: []string{"duplicate-id"}},
+
+		{name: "missing reference", html: page(`<div aria-describedby="help missing" id="help"></div>`), want: []string{"aria-reference"}},
+		{name: "existing references", html: page(`<span id="l">Players</span><div aria-labelledby="l" aria-controls="l"></div>`)},
+
+		{name: "invalid live value", html: page(`<div aria-live="loud"></div>`), want: []string{"aria-live"}},
+		{name: "polite live region", html: page(`<div aria-live="polite"></div><div aria-live="off"></div>`)},
+
+		{
+			name: "no h1",
+			html: `<html lang="en"><body><h2>Cards</h2></body></html>`,
+			want: []string{"heading-order"},
+		},
+		{
+			name: "two h1",
+			html: page(`<h1>Again</h1>`),
+			want: []string{"heading-order"},
+		},
+		{name: "skipped level", html: page(`<h2>Cards</h2><h4>News</h4>`), want: []string{"heading-order"}},
+		{name: "heading levels back up", html: page(`<h2>Cards</h2><h3>News</h3><h2>More</h2>`)},
+
+		{name: "autoplay video", html: page(`<video autoplay muted></video>`), want: []string{"no-autoplay"}},
+		{name: "paused video", html: page(`<video muted loop></video>`)},
+
+		{
+			name: "several rules in report order",
+			html: `<html><body><h1>A</h1><button></button><img src="a.png"></body></html>`,
+			want: []string{"html-lang", "image-alt", "button-name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules(t, tt.html); strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("broken rules = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestViolationString(t *testing.T) {
+	violations, err := a11y.Check(strings.NewReader(page(`<button id="close" class="btn btn-sm"></button>`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `button-name: <button id="close" class="btn btn-sm"> has no accessible name`
+	if len(violations) != 1 || violations[0].String() != want {
+		t.Errorf("violations = %v, want %s", violations, want)
+	}
+}
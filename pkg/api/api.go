@@ -0,0 +1,338 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/maintenance"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/population"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/webpush"
+)
+
+// maxRequestSize limits the size of JSON request bodies.
+const maxRequestSize = 64 << 10
+
+// endpoint describes an endpoint served by the API. The handler only serves
+// the listed endpoints and the OpenAPI document is generated from the same
+// list, so the spec can't drift away from the handlers.
+type endpoint struct {
+	// Method is the HTTP method, GET if empty.
+	Method  string
+	Path    string
+	Summary string
+	// Params are the query parameters.
+	Params []parameter
+	// Request is the type of the JSON request body, nil if there is none.
+	Request  any
+	Response any
+	// ContentTypes are the types the response is written in, JSON if empty.
+	// Response is the schema of the JSON one, the others are plain text.
+	ContentTypes []string
+	// Fetch serves GET endpoints that only need a context.
+	Fetch func(ctx context.Context) (any, error)
+	// Handle serves endpoints that need the request, used when Fetch is nil.
+	Handle func(r *http.Request) (any, error)
+	// Write serves endpoints writing their response themselves, used when
+	// Fetch and Handle are nil. Errors are still written as errorResponse.
+	Write func(w http.ResponseWriter, r *http.Request)
+	// Errors describes further error statuses the endpoint answers with.
+	Errors map[int]string
+}
+
+// parameter is a query parameter of an endpoint.
+type parameter struct {
+	Name        string
+	Description string
+	Required    bool
+	// Enum lists the accepted values, any string if empty.
+	Enum []string
+}
+
+// Config holds the server side services exposed by the API. Endpoints of
+// services left nil aren't served.
+type Config struct {
+	// PushKeys and PushStore enable the push subscription endpoints.
+	PushKeys  *webpush.VAPIDKeys
+	PushStore *webpush.Store
+	// AllowLocalPush accepts http and local push endpoints, for development
+	// against cmd/push-stub.
+	AllowLocalPush bool
+	// PlayerHistory and StatusHistory enable the players and status
+	// datasets of the export.
+	PlayerHistory *history.Store
+	StatusHistory *history.StatusLog
+	// Sources fetch the served data, DefaultSources if nil.
+	Sources *Sources
+}
+
+// Sources fetch the dashboard data from the upstream sites. Tests replace
+// them with stubs.
+type Sources struct {
+	PlayerCount    func(ctx context.Context, appID int) (component.PlayerCountResponse, error)
+	CurrentPlayers func(ctx context.Context, appID int) (int, error)
+	Population     func(ctx context.Context) []population.Estimate
+	Maintenance    func(ctx context.Context) ([]maintenance.Window, error)
+	ServerStatus   func(ctx context.Context) (component.ServerStatusResponse, error)
+	News           func(ctx context.Context) (component.RSSFeedResponse, error)
+}
+
+// DefaultSources fetch the data from the same sites as the app.
+func DefaultSources() Sources {
+	return Sources{
+		PlayerCount:    component.FetchPlayerCount,
+		CurrentPlayers: component.FetchCurrentPlayers,
+		Population: func(ctx context.Context) []population.Estimate {
+			return component.FetchPopulation(ctx, component.PopulationProviders())
+		},
+		Maintenance:  component.FetchMaintenance,
+		ServerStatus: component.FetchServerStatus,
+		News:         component.FetchRSSFeed,
+	}
+}
+
+// sources returns the configured sources or the default ones.
+func (c Config) sources() Sources {
+	if c.Sources != nil {
+		return *c.Sources
+	}
+	return DefaultSources()
+}
+
+// statusError is an error answered with a specific HTTP status.
+type statusError struct {
+	status int
+	err    error
+}
+
+// Error returns the message of the wrapped error.
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+// badRequest marks err as caused by an invalid request.
+func badRequest(err error) error {
+	return &statusError{status: http.StatusBadRequest, err: err}
+}
+
+// method returns the HTTP method of the endpoint.
+func (e endpoint) method() string {
+	if e.Method == "" {
+		return http.MethodGet
+	}
+	return e.Method
+}
+
+// endpoints lists every endpoint of the handler.
+func (c Config) endpoints() []endpoint {
+	endpoints := append(c.dataEndpoints(), c.pushEndpoints()...)
+	return append(endpoints, c.openAPIEndpoint(), c.calendarEndpoint(), c.exportEndpoint())
+}
+
+// dataEndpoints lists the read-only endpoints serving dashboard data.
+func (c Config) dataEndpoints() []endpoint {
+	sources := c.sources()
+	endpoints := []endpoint{
+		{
+			Path:     "/api/v1/players",
+			Summary:  "Current, 24 hour peak and all-time peak player count",
+			Response: component.PlayerCountResponse{},
+			Fetch: func(ctx context.Context) (any, error) {
+				players, err := sources.fetchPlayers(ctx, component.DefaultGame)
+				return players.PlayerCountResponse, err
+			},
+		},
+		{
+			Path:     "/api/v1/games",
+			Summary:  "Player counts of every configured Steam game",
+			Response: []component.GamePlayerCount{},
+			Fetch: func(ctx context.Context) (any, error) {
+				return sources.fetchAllPlayers(ctx, component.Games())
+			},
+		},
+		{
+			Path:     "/api/v1/population",
+			Summary:  "Player population estimates per platform",
+			Response: []population.Estimate{},
+			Fetch: func(ctx context.Context) (any, error) {
+				estimates := sources.Population(ctx)
+				if len(estimates) == 0 {
+					return nil, errors.New("no population estimates available")
+				}
+				return estimates, nil
+			},
+		},
+		{
+			Path:     "/api/v1/maintenance",
+			Summary:  "Announced maintenance windows that haven't ended yet",
+			Response: []maintenance.Window{},
+			Fetch: func(ctx context.Context) (any, error) {
+				windows, err := sources.Maintenance(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return maintenance.Upcoming(windows, time.Now()), nil
+			},
+		},
+		{
+			Path:     "/api/v1/status",
+			Summary:  "Server status per region",
+			Response: component.ServerStatusResponse{},
+			Fetch: func(ctx context.Context) (any, error) {
+				return sources.ServerStatus(ctx)
+			},
+		},
+	}
+	if c.PlayerHistory != nil {
+		endpoints = append(endpoints, endpoint{
+			Path:     component.PlayerHistoryPath,
+			Summary:  "Player counts of the default game recorded by the server in the last 25 hours",
+			Response: history.Series{},
+			Fetch: func(context.Context) (any, error) {
+				return c.recentPlayers(time.Now()), nil
+			},
+		})
+	}
+	return endpoints
+}
+
+// recentPlayers returns the polled player counts of the last history.Retention
+// before now, the ones the dashboard compares the current count with.
+func (c Config) recentPlayers(now time.Time) history.Series {
+	samples := history.Series{}
+	for _, sample := range c.PlayerHistory.Range(now.Add(-history.Retention), now) {
+		// Monthly averages aren't counts at their time.
+		if sample.Granularity != history.Monthly {
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+// fetchPlayers fetches the player counts of a game.
+func (s Sources) fetchPlayers(ctx context.Context, game component.Game) (component.GamePlayerCount, error) {
+	players, err := s.PlayerCount(ctx, game.AppID)
+	if err != nil {
+		return component.GamePlayerCount{Game: game}, err
+	}
+	// Steam API is more up to date than the Steam Charts heading.
+	if current, currentErr := s.CurrentPlayers(ctx, game.AppID); currentErr == nil {
+		players.Current = current
+	}
+	return component.GamePlayerCount{Game: game, PlayerCountResponse: players}, nil
+}
+
+// fetchAllPlayers fetches the player counts of every game. Games that fail are
+// left out, an error is only returned when all of them failed.
+func (s Sources) fetchAllPlayers(ctx context.Context, games []component.Game) ([]component.GamePlayerCount, error) {
+	counts := make([]component.GamePlayerCount, 0, len(games))
+	var errs []error
+	for _, game := range games {
+		players, err := s.fetchPlayers(ctx, game)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", game.Name, err))
+			continue
+		}
+		counts = append(counts, players)
+	}
+	if len(counts) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return counts, nil
+}
+
+// NewHandler returns the HTTP handler serving the JSON API, its OpenAPI
+// document and the downloads.
+func NewHandler(cfg Config) http.Handler {
+	mux := http.NewServeMux()
+	for _, e := range cfg.endpoints() {
+		mux.HandleFunc(e.method()+" "+e.Path, e.serve)
+	}
+	return mux
+}
+
+// serve runs the endpoint and writes its result as JSON.
+func (e endpoint) serve(w http.ResponseWriter, r *http.Request) {
+	if e.Fetch == nil && e.Handle == nil {
+		e.Write(w, r)
+		return
+	}
+
+	var data any
+	var err error
+	if e.Fetch != nil {
+		data, err = e.Fetch(r.Context())
+	} else {
+		data, err = e.Handle(r)
+	}
+	if err != nil {
+		status := http.StatusBadGateway
+		var statusErr *statusError
+		if errors.As(err, &statusErr) {
+			status = statusErr.status
+		}
+		log.Printf("Error serving %s %s: %v", e.method(), e.Path, err)
+		writeJSON(w, status, errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, data)
+}
+
+// decodeJSON decodes the JSON request body into v.
+func decodeJSON(r *http.Request, v any) error {
+	if err := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxRequestSize)).Decode(v); err != nil {
+		return badRequest(fmt.Errorf("decoding request body: %w", err))
+	}
+	return nil
+}
+
+// calendarEndpoint serves the iCalendar feed of the maintenance windows.
+func (c Config) calendarEndpoint() endpoint {
+	return endpoint{
+		Path:         component.MaintenanceCalendarPath,
+		Summary:      "Announced maintenance windows that haven't ended yet as an iCalendar feed",
+		ContentTypes: []string{maintenance.ContentType},
+		Write:        c.serveMaintenanceCalendar,
+	}
+}
+
+// serveMaintenanceCalendar writes the upcoming maintenance windows as an iCalendar feed.
+func (c Config) serveMaintenanceCalendar(w http.ResponseWriter, r *http.Request) {
+	windows, err := c.sources().Maintenance(r.Context())
+	if err != nil {
+		log.Printf("Error serving %s: %v", component.MaintenanceCalendarPath, err)
+		writeJSON(w, http.StatusBadGateway, errorResponse{Error: "loading maintenance windows: " + err.Error()})
+		return
+	}
+
+	now := time.Now()
+	w.Header().Set("Content-Type", maintenance.ContentType)
+	if err = maintenance.WriteICS(w, maintenance.Upcoming(windows, now), now); err != nil {
+		log.Println("Error writing calendar:", err)
+	}
+}
+
+// errorResponse is the body returned when an endpoint fails.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeJSON encodes v as the JSON response body.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println("Error encoding response:", err)
+	}
+}
@@ -0,0 +1,469 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/api"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/maintenance"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/population"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/webpush"
+)
+
+// pushEndpoint is the subscription endpoint registered by the tests.
+const pushEndpoint = "https://203.0.113.10/push/abc"
+
+// errUpstream is returned by the failing sources.
+var errUpstream = errors.New("upstream unavailable")
+
+// request is a call of an endpoint of the OpenAPI document.
+type request struct {
+	method string
+	path   string
+	query  string
+	body   string
+	// contentType is set for endpoints not answering with JSON.
+	contentType string
+	// upstream is set for endpoints serving data of the sources.
+	upstream bool
+}
+
+// target returns the path and query of the request.
+func (r request) target() string {
+	if r.query == "" {
+		return r.path
+	}
+	return r.path + "?" + r.query
+}
+
+// requests are the calls made for every operation of the document.
+var requests = []request{
+	{method: http.MethodGet, path: api.OpenAPIPath},
+	{method: http.MethodGet, path: "/api/v1/players", upstream: true},
+	{method: http.MethodGet, path: "/api/v1/games", upstream: true},
+	{method: http.MethodGet, path: "/api/v1/population", upstream: true},
+	{method: http.MethodGet, path: "/api/v1/maintenance", upstream: true},
+	{method: http.MethodGet, path: "/api/v1/status", upstream: true},
+	{method: http.MethodGet, path: component.PlayerHistoryPath},
+	{method: http.MethodGet, path: "/api/v1/push/key"},
+	{
+		method: http.MethodPost,
+		path:   component.PushSubscriptionsPath,
+		body:   `{"subscription":{"endpoint":"` + pushEndpoint + `","keys":{"p256dh":"key","auth":"secret"}},"regions":["pc-eu"]}`,
+	},
+	{method: http.MethodDelete, path: component.PushSubscriptionsPath, body: `{"endpoint":"` + pushEndpoint + `"}`},
+	{method: http.MethodGet, path: component.ExportPath, query: "dataset=players"},
+	{method: http.MethodGet, path: component.ExportPath, query: "dataset=news", upstream: true},
+	{method: http.MethodGet, path: component.MaintenanceCalendarPath, contentType: maintenance.ContentType, upstream: true},
+}
+
+// stubSources return fixed data, with every optional field set so the
+// schema of each field is checked.
+func stubSources() *api.Sources {
+	now := time.Now().UTC().Truncate(time.Second)
+	return &api.Sources{
+		PlayerCount: func(context.Context, int) (component.PlayerCountResponse, error) {
+			return component.PlayerCountResponse{Current: 12000, Peak: 18000, AllPeak: 40000}, nil
+		},
+		CurrentPlayers: func(context.Context, int) (int, error) {
+			return 12345, nil
+		},
+		Population: func(context.Context) []population.Estimate {
+			return []population.Estimate{
+				{Platform: "PC", Players: 12345, Source: "Steam", Confidence: population.ConfidenceHigh},
+			}
+		},
+		Maintenance: func(context.Context) ([]maintenance.Window, error) {
+			return []maintenance.Window{
+				{Title: "PC maintenance", Description: "Patch", Source: "Server status", Start: now.Add(time.Hour), End: now.Add(3 * time.Hour)},
+			}, nil
+		},
+		ServerStatus: func(context.Context) (component.ServerStatusResponse, error) {
+			return component.ServerStatusResponse{Report: esostatus.Report{
+				Regions: []esostatus.RegionStatus{{
+					Region:      "pc-eu",
+					Name:        "PC EU",
+					Platform:    "PC",
+					Environment: esostatus.EnvironmentLive,
+					State:       esostatus.StateMaintenance,
+					Message:     "Maintenance in progress",
+					Since:       now,
+					Source:      esostatus.Source,
+					Conflicts:   []esostatus.Conflict{{Source: "live-services", State: esostatus.StateOnline}},
+				}},
+				Announcements: []esostatus.Announcement{{Message: "Maintenance", Start: now, End: now.Add(time.Hour)}},
+				UpdatedAt:     now,
+			}}, nil
+		},
+		News: func(context.Context) (component.RSSFeedResponse, error) {
+			return component.RSSFeedResponse{Items: []component.RSSItem{
+				{Title: "Patch notes", Link: "https://example.com/news/2", PubDate: "2025-10-18 09:30:00", Categories: []string{"Patch Notes"}},
+				{Title: "Undated", Link: "https://example.com/news/0"},
+				{Title: "+Maintenance", Link: "https://example.com/news/1", PubDate: "2025-10-17 08:00:00", Categories: []string{"Maintenance", "PC"}},
+			}}, nil
+		},
+	}
+}
+
+// failingSources fail like unreachable upstream sites.
+func failingSources() *api.Sources {
+	return &api.Sources{
+		PlayerCount: func(context.Context, int) (component.PlayerCountResponse, error) {
+			return component.PlayerCountResponse{}, errUpstream
+		},
+		CurrentPlayers: func(context.Context, int) (int, error) { return 0, errUpstream },
+		Population:     func(context.Context) []population.Estimate { return nil },
+		Maintenance:    func(context.Context) ([]maintenance.Window, error) { return nil, errUpstream },
+		ServerStatus: func(context.Context) (component.ServerStatusResponse, error) {
+			return component.ServerStatusResponse{}, errUpstream
+		},
+		News: func(context.Context) (component.RSSFeedResponse, error) {
+			return component.RSSFeedResponse{}, errUpstream
+		},
+	}
+}
+
+// newConfig returns a configuration with push and a player history holding
+// a recent sample, reading the sources.
+func newConfig(t *testing.T, sources *api.Sources) api.Config {
+	t.Helper()
+	dir := t.TempDir()
+	keys, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := webpush.OpenStore(filepath.Join(dir, "push-subscriptions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	players, err := history.OpenStore(filepath.Join(dir, "player-history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	players.Add(time.Now().Add(-time.Hour), 11000)
+	return api.Config{PushKeys: &keys, PushStore: store, PlayerHistory: players, Sources: sources}
+}
+
+// serve calls the handler and decodes the JSON response body.
+func serve(t *testing.T, handler http.Handler, method, path, body string) (int, any) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Fatalf("%s %s: Content-Type %q, want application/json", method, path, contentType)
+	}
+	var decoded any
+	if err := json.Unmarshal(recorder.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("%s %s: decoding response: %v", method, path, err)
+	}
+	return recorder.Code, decoded
+}
+
+// openAPI fetches the document the handler serves, decoded like a client
+// would see it.
+func openAPI(t *testing.T, handler http.Handler) map[string]any {
+	t.Helper()
+	status, doc := serve(t, handler, http.MethodGet, api.OpenAPIPath, "")
+	if status != http.StatusOK {
+		t.Fatalf("GET %s: status %d", api.OpenAPIPath, status)
+	}
+	return doc.(map[string]any)
+}
+
+// responseSchema returns the schema the document declares for a response.
+func responseSchema(t *testing.T, doc map[string]any, method, path string, status int) map[string]any {
+	t.Helper()
+	operation, ok := lookup(doc, "paths", path, strings.ToLower(method)).(map[string]any)
+	if !ok {
+		t.Fatalf("%s %s isn't in the OpenAPI document", method, path)
+	}
+	schema, ok := lookup(operation, "responses", strconv.Itoa(status), "content", "application/json", "schema").(map[string]any)
+	if !ok {
+		t.Fatalf("%s %s: no schema for status %d", method, path, status)
+	}
+	return schema
+}
+
+// lookup follows object keys through a decoded JSON document.
+func lookup(value any, keys ...string) any {
+	for _, key := range keys {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+
+// checkSchema reports every way value doesn't match the schema. Objects may
+// only hold declared properties, so fields missing from the document are
+// caught too.
+func checkSchema(t *testing.T, where string, schema map[string]any, value any) {
+	t.Helper()
+	if value == nil {
+		if schema["nullable"] != true {
+			t.Errorf("%s: null isn't allowed", where)
+		}
+		return
+	}
+
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			t.Errorf("%s: %T, want an object", where, value)
+			return
+		}
+		checkObject(t, where, schema, object)
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			t.Errorf("%s: %T, want an array", where, value)
+			return
+		}
+		itemSchema, _ := schema["items"].(map[string]any)
+		for i, item := range items {
+			checkSchema(t, where+"["+strconv.Itoa(i)+"]", itemSchema, item)
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			t.Errorf("%s: %T, want a string", where, value)
+			return
+		}
+		if _, err := time.Parse(time.RFC3339, text); schema["format"] == "date-time" && err != nil {
+			t.Errorf("%s: %q isn't a date-time", where, text)
+		}
+	case "integer":
+		if number, ok := value.(float64); !ok || number != math.Trunc(number) {
+			t.Errorf("%s: %v, want an integer", where, value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			t.Errorf("%s: %T, want a number", where, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			t.Errorf("%s: %T, want a boolean", where, value)
+		}
+	case nil:
+		// An empty schema allows any value.
+	default:
+		t.Errorf("%s: unknown schema type %v", where, schema["type"])
+	}
+}
+
+// checkObject checks the required and declared properties of an object.
+func checkObject(t *testing.T, where string, schema, object map[string]any) {
+	t.Helper()
+	if additional, ok := schema["additionalProperties"].(map[string]any); ok {
+		for key, value := range object {
+			checkSchema(t, where+"."+key, additional, value)
+		}
+		return
+	}
+
+	required, _ := schema["required"].([]any)
+	for _, name := range required {
+		if _, ok := object[name.(string)]; !ok {
+			t.Errorf("%s: required property %q is missing", where, name)
+		}
+	}
+	properties, _ := schema["properties"].(map[string]any)
+	for key, value := range object {
+		property, ok := properties[key].(map[string]any)
+		if !ok {
+			t.Errorf("%s: property %q isn't in the schema", where, key)
+			continue
+		}
+		checkSchema(t, where+"."+key, property, value)
+	}
+}
+
+func TestEveryOperationIsTested(t *testing.T) {
+	handler := api.NewHandler(newConfig(t, stubSources()))
+	paths, _ := openAPI(t, handler)["paths"].(map[string]any)
+	for path, operations := range paths {
+		for method := range operations.(map[string]any) {
+			if !slices.ContainsFunc(requests, func(r request) bool {
+				return r.path == path && strings.EqualFold(r.method, method)
+			}) {
+				t.Errorf("%s %s has no request in the test", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func TestResponsesMatchSpec(t *testing.T) {
+	handler := api.NewHandler(newConfig(t, stubSources()))
+	doc := openAPI(t, handler)
+
+	for _, r := range requests {
+		t.Run(r.method+" "+r.target(), func(t *testing.T) {
+			if r.contentType != "" {
+				checkContentType(t, handler, doc, r)
+				return
+			}
+			status, body := serve(t, handler, r.method, r.target(), r.body)
+			if status != http.StatusOK {
+				t.Fatalf("status %d: %v", status, body)
+			}
+			if items, ok := body.([]any); ok && len(items) == 0 {
+				t.Fatal("the stubbed response is empty, the item schema isn't checked")
+			}
+			checkSchema(t, "response", responseSchema(t, doc, r.method, r.path, status), body)
+		})
+	}
+}
+
+// checkContentType checks that a request answered in another type than JSON
+// succeeds with a type of the document.
+func checkContentType(t *testing.T, handler http.Handler, doc map[string]any, r request) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(r.method, r.target(), strings.NewReader(r.body)))
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != r.contentType {
+		t.Fatalf("status %d, Content-Type %q, want %d and %q", recorder.Code, recorder.Header().Get("Content-Type"), http.StatusOK, r.contentType)
+	}
+	if lookup(doc, "paths", r.path, strings.ToLower(r.method), "responses", "200", "content", r.contentType) == nil {
+		t.Errorf("%s isn't a response type of the document", r.contentType)
+	}
+}
+
+func TestEveryRouteIsInSpec(t *testing.T) {
+	handler := api.NewHandler(newConfig(t, stubSources()))
+	mux, ok := handler.(*http.ServeMux)
+	if !ok {
+		t.Fatalf("NewHandler() = %T, want a *http.ServeMux", handler)
+	}
+	paths, _ := openAPI(t, handler)["paths"].(map[string]any)
+
+	// Every path the site links to or the document lists, with every method.
+	targets := []string{component.ExportPath, component.MaintenanceCalendarPath, component.PlayerHistoryPath, component.PushSubscriptionsPath, api.OpenAPIPath}
+	for path := range paths {
+		targets = append(targets, path)
+	}
+	slices.Sort(targets)
+	for _, path := range slices.Compact(targets) {
+		for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+			_, pattern := mux.Handler(httptest.NewRequest(method, path, nil))
+			if pattern == "" {
+				continue
+			}
+			patternMethod, patternPath, _ := strings.Cut(pattern, " ")
+			if lookup(paths, patternPath, strings.ToLower(patternMethod)) == nil {
+				t.Errorf("route %q isn't in the OpenAPI document", pattern)
+			}
+		}
+	}
+}
+
+func TestUpstreamErrorsMatchSpec(t *testing.T) {
+	handler := api.NewHandler(newConfig(t, failingSources()))
+	doc := openAPI(t, handler)
+
+	for _, r := range requests {
+		if !r.upstream {
+			continue
+		}
+		t.Run(r.target(), func(t *testing.T) {
+			status, body := serve(t, handler, r.method, r.target(), r.body)
+			if status != http.StatusBadGateway {
+				t.Fatalf("status %d, want %d", status, http.StatusBadGateway)
+			}
+			checkSchema(t, "response", responseSchema(t, doc, r.method, r.path, status), body)
+		})
+	}
+}
+
+func TestInvalidRequestsMatchSpec(t *testing.T) {
+	handler := api.NewHandler(newConfig(t, stubSources()))
+	doc := openAPI(t, handler)
+
+	for _, r := range requests {
+		if r.body == "" {
+			continue
+		}
+		t.Run(r.method+" "+r.path, func(t *testing.T) {
+			status, body := serve(t, handler, r.method, r.target(), "{")
+			if status != http.StatusBadRequest {
+				t.Fatalf("status %d, want %d", status, http.StatusBadRequest)
+			}
+			checkSchema(t, "response", responseSchema(t, doc, r.method, r.path, status), body)
+		})
+	}
+}
+
+// subscription returns a subscribe request body for the endpoint.
+func subscription(endpoint string) string {
+	return `{"subscription":{"endpoint":"` + endpoint + `","keys":{"p256dh":"key","auth":"secret"}}}`
+}
+
+func TestSubscribeRejectsLocalEndpoints(t *testing.T) {
+	config := newConfig(t, stubSources())
+	handler := api.NewHandler(config)
+
+	for _, endpoint := range []string{
+		"http://203.0.113.10/push/abc",
+		"https://127.0.0.1/push",
+		"https://localhost/push",
+		"https://10.0.0.8/push",
+		"https://192.168.1.1/push",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[::1]/push",
+		"https://[fe80::1]/push",
+		"https://[::ffff:127.0.0.1]/push",
+		"ftp://203.0.113.10/push",
+	} {
+		t.Run(endpoint, func(t *testing.T) {
+			if status, body := serve(t, handler, http.MethodPost, component.PushSubscriptionsPath, subscription(endpoint)); status != http.StatusBadRequest {
+				t.Errorf("status %d, want %d: %v", status, http.StatusBadRequest, body)
+			}
+		})
+	}
+	if registrations := config.PushStore.List(); len(registrations) > 0 {
+		t.Errorf("stored %+v, want nothing", registrations)
+	}
+
+	config.AllowLocalPush = true
+	handler = api.NewHandler(config)
+	if status, body := serve(t, handler, http.MethodPost, component.PushSubscriptionsPath, subscription("http://127.0.0.1:8090/push")); status != http.StatusOK {
+		t.Errorf("status %d with local push allowed: %v", status, body)
+	}
+}
+
+func TestSubscribeLimit(t *testing.T) {
+	config := newConfig(t, stubSources())
+	config.PushStore.Limit = 1
+	handler := api.NewHandler(config)
+	doc := openAPI(t, handler)
+
+	if status, body := serve(t, handler, http.MethodPost, component.PushSubscriptionsPath, subscription(pushEndpoint)); status != http.StatusOK {
+		t.Fatalf("status %d: %v", status, body)
+	}
+	// Updating the known subscription is still possible.
+	if status, body := serve(t, handler, http.MethodPost, component.PushSubscriptionsPath, subscription(pushEndpoint)); status != http.StatusOK {
+		t.Fatalf("status %d updating: %v", status, body)
+	}
+
+	status, body := serve(t, handler, http.MethodPost, component.PushSubscriptionsPath, subscription("https://203.0.113.11/push"))
+	if status != http.StatusServiceUnavailable {
+		t.Fatalf("status %d, want %d", status, http.StatusServiceUnavailable)
+	}
+	checkSchema(t, "response", responseSchema(t, doc, http.MethodPost, component.PushSubscriptionsPath, status), body)
+}
@@ -0,0 +1,298 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+)
+
+// rssTimeLayout is the layout of component.RSSItem.PubDate.
+const rssTimeLayout = "2006-01-02 15:04:05"
+
+// dateLayout is the layout of from and to when they are days.
+const dateLayout = "2006-01-02"
+
+// exportFormats are the content types of the export formats.
+var exportFormats = map[string]string{
+	"csv":    "text/csv; charset=utf-8",
+	"json":   "application/json",
+	"ndjson": "application/x-ndjson",
+}
+
+// exportRecord is a row of an export.
+type exportRecord interface {
+	csvRow() []string
+}
+
+// exportDataset reads the records of a dataset between two times. An error
+// means the records can't be read at all, before anything is written.
+type exportDataset struct {
+	header  []string
+	records func(ctx context.Context, from, to time.Time) (iter.Seq[exportRecord], error)
+}
+
+// playerRecord is a player count. Imported monthly averages have the peak of
+// their month, the import command reads the records back.
+type playerRecord struct {
+	Time        time.Time           `json:"time"`
+	Players     int                 `json:"players"`
+	Peak        int                 `json:"peak,omitempty"`
+	Granularity history.Granularity `json:"granularity,omitempty"`
+}
+
+// csvRow returns the time, count, peak and granularity.
+func (p playerRecord) csvRow() []string {
+	peak := ""
+	if p.Peak > 0 {
+		peak = strconv.Itoa(p.Peak)
+	}
+	return []string{p.Time.UTC().Format(time.RFC3339), strconv.Itoa(p.Players), peak, string(p.Granularity)}
+}
+
+// statusRecord is a region entering a state.
+type statusRecord history.StatusChange
+
+// csvRow returns the time, region and state.
+func (s statusRecord) csvRow() []string {
+	return []string{s.Time.UTC().Format(time.RFC3339), s.Region, s.Name, string(s.State)}
+}
+
+// newsRecord is a published news article.
+type newsRecord struct {
+	Time       time.Time `json:"time"`
+	Title      string    `json:"title"`
+	Link       string    `json:"link"`
+	Categories []string  `json:"categories"`
+}
+
+// csvRow returns the time, title, link and categories.
+func (n newsRecord) csvRow() []string {
+	return []string{n.Time.UTC().Format(time.RFC3339), n.Title, n.Link, strings.Join(n.Categories, ";")}
+}
+
+// datasets lists the exportable datasets: the histories recorded by the
+// server and the news of the feed. Datasets without their history are left
+// out.
+func (c Config) datasets() map[string]exportDataset {
+	datasets := map[string]exportDataset{
+		"news": {header: []string{"time", "title", "link", "categories"}, records: c.exportNews},
+	}
+	if c.PlayerHistory != nil {
+		datasets["players"] = exportDataset{
+			header: []string{"time", "players", "peak", "granularity"},
+			records: func(_ context.Context, from, to time.Time) (iter.Seq[exportRecord], error) {
+				return func(yield func(exportRecord) bool) {
+					for sample := range c.PlayerHistory.Samples(from, to) {
+						if !yield(playerRecord{Time: sample.Time, Players: sample.Value, Peak: sample.Peak, Granularity: sample.Granularity}) {
+							return
+						}
+					}
+				}, nil
+			},
+		}
+	}
+	if c.StatusHistory != nil {
+		datasets["status"] = exportDataset{
+			header: []string{"time", "region", "name", "state"},
+			records: func(_ context.Context, from, to time.Time) (iter.Seq[exportRecord], error) {
+				return func(yield func(exportRecord) bool) {
+					for change := range c.StatusHistory.Changes(from, to) {
+						if !yield(statusRecord(change)) {
+							return
+						}
+					}
+				}, nil
+			},
+		}
+	}
+	return datasets
+}
+
+// exportNews returns the articles of the feed published in the range, oldest
+// first. The server doesn't record the news, so only the articles still in
+// the feed are exported, however far back from reaches.
+func (c Config) exportNews(ctx context.Context, from, to time.Time) (iter.Seq[exportRecord], error) {
+	feed, err := c.sources().News(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type published struct {
+		time time.Time
+		item component.RSSItem
+	}
+	var items []published
+	for _, item := range feed.Items {
+		t, err := time.Parse(rssTimeLayout, item.PubDate)
+		if err != nil || t.Before(from) || t.After(to) {
+			continue
+		}
+		items = append(items, published{time: t, item: item})
+	}
+	slices.SortStableFunc(items, func(a, b published) int { return a.time.Compare(b.time) })
+
+	return func(yield func(exportRecord) bool) {
+		for _, p := range items {
+			if !yield(newsRecord{Time: p.time, Title: p.item.Title, Link: p.item.Link, Categories: p.item.Categories}) {
+				return
+			}
+		}
+	}, nil
+}
+
+// exportEndpoint serves the downloads of the datasets.
+func (c Config) exportEndpoint() endpoint {
+	contentTypes := make([]string, 0, len(exportFormats))
+	for _, format := range slices.Sorted(maps.Keys(exportFormats)) {
+		contentTypes = append(contentTypes, exportFormats[format])
+	}
+	return endpoint{
+		Path:    component.ExportPath,
+		Summary: "Records of a dataset between two times as CSV, a JSON array or newline delimited JSON",
+		Params: []parameter{
+			{Name: "dataset", Description: "Dataset to export", Required: true, Enum: slices.Sorted(maps.Keys(c.datasets()))},
+			{Name: "from", Description: "Start as an RFC 3339 time or a day, the whole history if empty"},
+			{Name: "to", Description: "End as an RFC 3339 time or a day including the whole day, now if empty"},
+			{Name: "format", Description: "Format of the download, json if empty", Enum: slices.Sorted(maps.Keys(exportFormats))},
+		},
+		Response:     []exportRecord{},
+		ContentTypes: contentTypes,
+		Write:        c.serveExport,
+		Errors:       map[int]string{http.StatusBadRequest: "Unknown dataset or format, or invalid time"},
+	}
+}
+
+// serveExport writes the records of a dataset between from and to, the whole
+// history by default, as CSV, a JSON array or newline delimited JSON.
+func (c Config) serveExport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	name := query.Get("dataset")
+	dataset, ok := c.datasets()[name]
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("unknown dataset %q", name)})
+		return
+	}
+	format := query.Get("format")
+	if format == "" {
+		format = "json"
+	}
+	contentType, ok := exportFormats[format]
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("unknown format %q", format)})
+		return
+	}
+	from, err := parseExportTime(query.Get("from"), time.Time{})
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	to, err := parseExportTime(query.Get("to"), time.Now())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	// A day as the end includes the whole day.
+	if len(query.Get("to")) == len(dateLayout) {
+		to = to.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	records, err := dataset.records(r.Context(), from, to)
+	if err != nil {
+		log.Printf("Error serving %s: %v", component.ExportPath, err)
+		writeJSON(w, http.StatusBadGateway, errorResponse{Error: err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="eso-`+name+`.`+format+`"`)
+	if err = writeExport(w, format, dataset.header, records); err != nil {
+		log.Println("Error writing export:", err)
+	}
+}
+
+// parseExportTime parses an RFC 3339 time or a day, fallback if raw is empty.
+func parseExportTime(raw string, fallback time.Time) (time.Time, error) {
+	if raw == "" {
+		return fallback, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(dateLayout, raw); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, expected RFC 3339 or YYYY-MM-DD", raw)
+}
+
+// writeExport streams the records in format, without building the whole
+// body in memory.
+func writeExport(w io.Writer, format string, header []string, records iter.Seq[exportRecord]) error {
+	switch format {
+	case "csv":
+		writer := csv.NewWriter(w)
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		for record := range records {
+			if err := writer.Write(csvCells(record.csvRow())); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+
+	case "ndjson":
+		encoder := json.NewEncoder(w)
+		for record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "json":
+		encoder := json.NewEncoder(w)
+		separator := "["
+		for record := range records {
+			if _, err := fmt.Fprint(w, separator); err != nil {
+				return err
+			}
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+			separator = ","
+		}
+		if separator == "[" {
+			_, err := fmt.Fprint(w, "[]\n")
+			return err
+		}
+		_, err := fmt.Fprint(w, "]\n")
+		return err
+	}
+	return errors.New("unknown format " + format)
+}
+
+// csvCells escapes the cells a spreadsheet would run as a formula, those
+// starting with =, +, -, @, a tab or a carriage return, with a leading quote.
+func csvCells(row []string) []string {
+	for i, cell := range row {
+		if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			row[i] = "'" + cell
+		}
+	}
+	return row
+}
@@ -0,0 +1,171 @@
+package api_test
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/api"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+)
+
+// exportConfig returns a configuration with two days of recorded history.
+func exportConfig(t *testing.T) api.Config {
+	t.Helper()
+	config := newConfig(t, stubSources())
+	day := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+
+	statusLog, err := history.OpenStatusLog(filepath.Join(t.TempDir(), "status-history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	statusLog.Record(day.Add(-12*time.Hour), []esostatus.RegionStatus{{Region: "PC-EU", Name: "PC Europe", State: esostatus.StateOnline}})
+	// A region name from the configuration that a spreadsheet would run.
+	statusLog.Record(day.Add(time.Hour), []esostatus.RegionStatus{
+		{Region: "PC-EU", Name: "PC Europe", State: esostatus.StateMaintenance},
+		{Region: "-1+1", Name: `=HYPERLINK("https://example.com")`, State: esostatus.StateOnline},
+	})
+	config.StatusHistory = statusLog
+	return config
+}
+
+// export downloads the export of the query.
+func export(t *testing.T, config api.Config, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	api.NewHandler(config).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, component.ExportPath+"?"+query, nil))
+	return recorder
+}
+
+func TestExportCSV(t *testing.T) {
+	day := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	resp := export(t, exportConfig(t), "dataset=status&format=csv&from="+day.Format("2006-01-02"))
+	if resp.Code != http.StatusOK || resp.Header().Get("Content-Type") != "text/csv; charset=utf-8" {
+		t.Fatalf("status %d, Content-Type %q", resp.Code, resp.Header().Get("Content-Type"))
+	}
+	if disposition := resp.Header().Get("Content-Disposition"); disposition != `attachment; filename="eso-status.csv"` {
+		t.Errorf("Content-Disposition = %q", disposition)
+	}
+
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := day.Add(time.Hour).Format(time.RFC3339)
+	want := [][]string{
+		{"time", "region", "name", "state"},
+		{at, "PC-EU", "PC Europe", string(esostatus.StateMaintenance)},
+		{at, "'-1+1", `'=HYPERLINK("https://example.com")`, string(esostatus.StateOnline)},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %q, want %q", rows, want)
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	config := exportConfig(t)
+
+	resp := export(t, config, "dataset=players")
+	var players []map[string]any
+	if err := json.Unmarshal(resp.Body.Bytes(), &players); err != nil {
+		t.Fatalf("decoding %q: %v", resp.Body, err)
+	}
+	if len(players) != 1 || players[0]["players"] != float64(11000) {
+		t.Errorf("players = %v, want the recorded sample", players)
+	}
+
+	resp = export(t, config, "dataset=status&format=ndjson")
+	lines := strings.Split(strings.TrimSpace(resp.Body.String()), "\n")
+	if resp.Header().Get("Content-Type") != "application/x-ndjson" || len(lines) != 3 {
+		t.Fatalf("ndjson = %q, want a line per change", resp.Body)
+	}
+	var change map[string]any
+	if err := json.Unmarshal([]byte(lines[2]), &change); err != nil || change["name"] != `=HYPERLINK("https://example.com")` {
+		t.Errorf("last change = %v, %v, want the name as it is in JSON", change, err)
+	}
+
+	resp = export(t, config, "dataset=status&to=2000-01-01")
+	if body := strings.TrimSpace(resp.Body.String()); body != "[]" {
+		t.Errorf("empty export = %q, want []", body)
+	}
+}
+
+func TestExportNews(t *testing.T) {
+	resp := export(t, exportConfig(t), "dataset=news&format=csv&from=2025-10-17&to=2025-10-18")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", resp.Code, resp.Body)
+	}
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Oldest first, without the article that has no date.
+	want := [][]string{
+		{"time", "title", "link", "categories"},
+		{"2025-10-17T08:00:00Z", "'+Maintenance", "https://example.com/news/1", "Maintenance;PC"},
+		{"2025-10-18T09:30:00Z", "Patch notes", "https://example.com/news/2", "Patch Notes"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %q, want %q", rows, want)
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+
+	resp = export(t, exportConfig(t), "dataset=news&from=2025-10-18T00:00:00Z")
+	var news []map[string]any
+	if err = json.Unmarshal(resp.Body.Bytes(), &news); err != nil {
+		t.Fatalf("decoding %q: %v", resp.Body, err)
+	}
+	if len(news) != 1 || news[0]["title"] != "Patch notes" {
+		t.Errorf("news = %v, want the article of the range", news)
+	}
+}
+
+// exportError checks that the export answers the query with status and a
+// JSON error.
+func exportError(t *testing.T, config api.Config, query string, status int) {
+	t.Helper()
+	resp := export(t, config, query)
+	var body struct {
+		Error string `json:"error"`
+	}
+	if resp.Code != status || resp.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("%s: status %d, Content-Type %q, want %d and JSON", query, resp.Code, resp.Header().Get("Content-Type"), status)
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil || body.Error == "" {
+		t.Errorf("%s: body %q isn't a JSON error: %v", query, resp.Body, err)
+	}
+}
+
+func TestExportInvalid(t *testing.T) {
+	config := exportConfig(t)
+	for _, query := range []string{
+		"dataset=unknown",
+		"dataset=players&format=xml",
+		"dataset=players&from=yesterday",
+		"dataset=players&to=2025-13-01",
+	} {
+		exportError(t, config, query, http.StatusBadRequest)
+	}
+
+	config.StatusHistory = nil
+	exportError(t, config, "dataset=status", http.StatusBadRequest)
+
+	config.Sources = failingSources()
+	exportError(t, config, "dataset=news", http.StatusBadGateway)
+}
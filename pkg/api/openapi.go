@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAPIPath is the path the generated OpenAPI document is served at.
+const OpenAPIPath = "/api/openapi.json"
+
+// openAPIVersion is the OpenAPI specification version the document follows.
+const openAPIVersion = "3.0.3"
+
+// apiVersion is the version of the API described by the document.
+const apiVersion = "1.0.0"
+
+// Spec builds the OpenAPI document from the registered endpoints and their
+// request and response types.
+func Spec(cfg Config) map[string]any {
+	paths := map[string]any{}
+	for _, e := range cfg.endpoints() {
+		operation := map[string]any{
+			"summary": e.Summary,
+			"responses": map[string]any{
+				"200": okResponse(e),
+				"502": jsonResponse("Upstream source unavailable", reflect.TypeOf(errorResponse{})),
+			},
+		}
+		if len(e.Params) > 0 {
+			operation["parameters"] = parameters(e.Params)
+		}
+		if e.Request != nil {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content": map[string]any{
+					"application/json": map[string]any{"schema": schemaOf(reflect.TypeOf(e.Request))},
+				},
+			}
+			operation["responses"].(map[string]any)["400"] = jsonResponse("Invalid request", reflect.TypeOf(errorResponse{}))
+		}
+		for status, description := range e.Errors {
+			operation["responses"].(map[string]any)[strconv.Itoa(status)] = jsonResponse(description, reflect.TypeOf(errorResponse{}))
+		}
+
+		path, ok := paths[e.Path].(map[string]any)
+		if !ok {
+			path = map[string]any{}
+			paths[e.Path] = path
+		}
+		path[strings.ToLower(e.method())] = operation
+	}
+
+	return map[string]any{
+		"openapi": openAPIVersion,
+		"info": map[string]any{
+			"title":   "ESO Dashboard API",
+			"version": apiVersion,
+		},
+		"paths": paths,
+	}
+}
+
+// openAPIEndpoint serves the OpenAPI document, which describes itself too.
+func (c Config) openAPIEndpoint() endpoint {
+	return endpoint{
+		Path:     OpenAPIPath,
+		Summary:  "OpenAPI document of the API",
+		Response: map[string]any{},
+		Fetch: func(context.Context) (any, error) {
+			return Spec(c), nil
+		},
+	}
+}
+
+// okResponse describes the successful response of e in each of its content
+// types.
+func okResponse(e endpoint) map[string]any {
+	if len(e.ContentTypes) == 0 {
+		return jsonResponse("OK", reflect.TypeOf(e.Response))
+	}
+	content := map[string]any{}
+	for _, contentType := range e.ContentTypes {
+		schema := map[string]any{"type": "string"}
+		if contentType == "application/json" {
+			schema = schemaOf(reflect.TypeOf(e.Response))
+		}
+		content[contentType] = map[string]any{"schema": schema}
+	}
+	return map[string]any{"description": "OK", "content": content}
+}
+
+// parameters describes the query parameters of an endpoint.
+func parameters(params []parameter) []map[string]any {
+	described := make([]map[string]any, 0, len(params))
+	for _, param := range params {
+		schema := map[string]any{"type": "string"}
+		if len(param.Enum) > 0 {
+			schema["enum"] = param.Enum
+		}
+		described = append(described, map[string]any{
+			"name":        param.Name,
+			"in":          "query",
+			"description": param.Description,
+			"required":    param.Required,
+			"schema":      schema,
+		})
+	}
+	return described
+}
+
+// jsonResponse describes an application/json response with the schema of t.
+func jsonResponse(description string, t reflect.Type) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schemaOf(t)},
+		},
+	}
+}
+
+// timeType is matched separately because time.Time is encoded as a string.
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOf returns the JSON schema for t following encoding/json rules.
+func schemaOf(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		schema := schemaOf(t.Elem())
+		schema["nullable"] = true
+		return schema
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	//nolint:exhaustive // Every other kind isn't encoded by the API.
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaOf(t.Elem())}
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema returns the object schema for the exported fields of t.
+func structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		// Embedded structs without a name are flattened by encoding/json.
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := structSchema(field.Type)
+			if embeddedProperties, ok := embedded["properties"].(map[string]any); ok {
+				for key, value := range embeddedProperties {
+					properties[key] = value
+				}
+			}
+			if embeddedRequired, ok := embedded["required"].([]string); ok {
+				required = append(required, embeddedRequired...)
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = schemaOf(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/webpush"
+)
+
+// publicKeyResponse carries the VAPID public key clients subscribe with.
+type publicKeyResponse struct {
+	PublicKey string `json:"publicKey"`
+}
+
+// unsubscribeRequest identifies the subscription to remove.
+type unsubscribeRequest struct {
+	Endpoint string `json:"endpoint"`
+}
+
+// pushEndpoints lists the push subscription endpoints, none if push isn't configured.
+func (c Config) pushEndpoints() []endpoint {
+	if c.PushKeys == nil || c.PushStore == nil {
+		return nil
+	}
+
+	return []endpoint{
+		{
+			Path:     "/api/v1/push/key",
+			Summary:  "VAPID public key to subscribe to push messages with",
+			Response: publicKeyResponse{},
+			Fetch: func(context.Context) (any, error) {
+				return publicKeyResponse{PublicKey: c.PushKeys.PublicKey()}, nil
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     component.PushSubscriptionsPath,
+			Summary:  "Register or update a push subscription and the regions it watches",
+			Request:  webpush.Registration{},
+			Response: webpush.Registration{},
+			Handle:   c.subscribe,
+			Errors:   map[int]string{http.StatusServiceUnavailable: "Subscription limit reached"},
+		},
+		{
+			Method:   http.MethodDelete,
+			Path:     component.PushSubscriptionsPath,
+			Summary:  "Remove a push subscription",
+			Request:  unsubscribeRequest{},
+			Response: unsubscribeRequest{},
+			Handle:   c.unsubscribe,
+		},
+	}
+}
+
+// subscribe stores the registration sent by the client.
+func (c Config) subscribe(r *http.Request) (any, error) {
+	var registration webpush.Registration
+	if err := decodeJSON(r, &registration); err != nil {
+		return nil, err
+	}
+	if registration.Subscription.Keys.P256dh == "" || registration.Subscription.Keys.Auth == "" {
+		return nil, badRequest(errors.New("subscription keys are missing"))
+	}
+	if err := webpush.CheckEndpoint(r.Context(), registration.Subscription.Endpoint, c.AllowLocalPush); err != nil {
+		return nil, badRequest(err)
+	}
+
+	err := c.PushStore.Put(registration)
+	if errors.Is(err, webpush.ErrStoreFull) {
+		return nil, &statusError{status: http.StatusServiceUnavailable, err: err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return registration, nil
+}
+
+// unsubscribe removes the registration of the endpoint sent by the client.
+func (c Config) unsubscribe(r *http.Request) (any, error) {
+	var request unsubscribeRequest
+	if err := decodeJSON(r, &request); err != nil {
+		return nil, err
+	}
+	if request.Endpoint == "" {
+		return nil, badRequest(errors.New("endpoint is missing"))
+	}
+
+	if err := c.PushStore.Remove(request.Endpoint); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
@@ -1,63 +1,169 @@
 package component
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
 	"github.com/maxence-charriere/go-app/v10/pkg/app"
 )
 
+// PlayerHistoryPath is the path of the player counts the server recorded.
+const PlayerHistoryPath = "/api/v1/players/history"
+
+// activeUsersWidget shows the current player count with its trend and the history downloads.
+var activeUsersWidget = Widget{
+	ID:          "activeUsers",
+	Title:       "Active Users",
+	HeaderClass: "bg-info",
+	CardClass:   "text-center",
+	Width:       4,
+	Live:        true,
+	New:         func() app.Composer { return &CurrentPlayers{} },
+	Wrap: func(body app.UI) app.UI {
+		return app.Div().Body(
+			app.Div().Class("card-title h5").ID("activeUsers").Body(body),
+			&HistoryDownload{},
+		)
+	},
+}
+
 // CurrentPlayers is a component that displays the current player count.
 type CurrentPlayers struct {
 	app.Compo
+	// Game is the game to display, The Elder Scrolls Online if left empty.
+	Game Game
+	// Lang is the language the count is rendered in.
+	Lang           string
 	CurrentPlayers app.UI
 }
 
 // OnMount Check if the app is installable and set the state according.
 func (c *CurrentPlayers) OnMount(ctx app.Context) {
-	c.CurrentPlayers = app.Span().Text(getCurrentPlayers(ctx))
+	ObserveLanguage(ctx, &c.Lang, func() { c.load(ctx) })
+	ObserveRefresh(ctx, func() { c.load(ctx) })
+	c.load(ctx)
+}
+
+// OnPreRender renders the CurrentPlayers in the language of the page on the server.
+func (c *CurrentPlayers) OnPreRender(ctx app.Context) {
+	c.Lang = DefaultLanguage(ctx)
 }
 
 // OnNav is called when the component is navigated to.
 func (c *CurrentPlayers) OnNav(ctx app.Context) {
-	c.CurrentPlayers = app.Span().Text(getCurrentPlayers(ctx))
+	c.load(ctx)
+}
+
+// load reads the count and renders it in the current language.
+func (c *CurrentPlayers) load(ctx app.Context) {
+	game := c.Game.orDefault()
+	count, err := getCurrentPlayers(ctx, game)
+	c.CurrentPlayers = playerStat(ctx, c.Lang, game.stateKey("currentPlayersHistory"), getRecordedPlayers(ctx, game), count, err)
 }
 
 // Render is the main function that renders the current player count component.
 func (c *CurrentPlayers) Render() app.UI {
 	if c.CurrentPlayers == nil {
-		return app.Span().Text("Loading...")
+		return app.Span().Text(i18n.T(c.Lang, "Loading..."))
 	}
 	return c.CurrentPlayers
 }
 
-// getCurrentPlayers fetches the current player count from Steam API.
-func getCurrentPlayers(ctx app.Context) (string) {
+// getCurrentPlayers returns the cached current player count or fetches it from Steam API.
+func getCurrentPlayers(ctx app.Context, game Game) (int, error) {
 	// Check if state value is set and return
-	currentPlayers := constant.Unreachable
-	ctx.GetState("currentPlayersResponse", &currentPlayers)
+	currentPlayers := 0
+	ctx.GetState(game.stateKey("currentPlayerCount"), &currentPlayers)
 
-	if currentPlayers != constant.Unreachable && currentPlayers != "" {
-		return currentPlayers
+	if currentPlayers > 0 {
+		return currentPlayers, nil
 	}
 
+	currentPlayers, err := fetchOrSnapshot(ctx, game.stateKey("currentPlayerCount"), func() (int, error) {
+		return FetchCurrentPlayers(ctx, game.AppID)
+	})
+	if err != nil {
+		log.Println("Error fetching current player count:", err)
+		return 0, err
+	}
+
+	ctx.SetState(game.stateKey("currentPlayerCount"), currentPlayers).
+		Persist().ExpiresIn(cacheDuration(ctx, constant.PlayerCountCacheDuration)) // TODO - ExpiresAt is always 0001-01-01T00:00:00Z inside local storage!?
+
+	return currentPlayers, nil
+}
+
+// getRecordedPlayers returns the player counts the server recorded, so the
+// changes show on the first visit too. The server only records the default
+// game, and neither the pre-rendering nor static exports can ask it.
+func getRecordedPlayers(ctx app.Context, game Game) history.Series {
+	if !app.IsClient || Static() || !Online() || game.AppID != DefaultGame.AppID {
+		return nil
+	}
+
+	var samples history.Series
+	ctx.GetState("recordedPlayers", &samples)
+	if samples != nil {
+		return samples
+	}
+
+	samples, err := FetchRecordedPlayers(ctx, app.Window().URL())
+	if err != nil {
+		log.Println("Error fetching recorded player counts:", err)
+		return nil
+	}
+	ctx.SetState("recordedPlayers", samples).ExpiresIn(constant.PlayerCountCacheDuration)
+	return samples
+}
+
+// FetchRecordedPlayers fetches the player counts recorded by the dashboard
+// server at base.
+func FetchRecordedPlayers(ctx context.Context, base *url.URL) (history.Series, error) {
+	endpoint := base.ResolveReference(&url.URL{Path: PlayerHistoryPath})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	client := &http.Client{Timeout: constant.FetchTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching recorded player counts: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching recorded player counts: unexpected status %s", resp.Status)
+	}
+	samples := history.Series{}
+	if err = json.NewDecoder(resp.Body).Decode(&samples); err != nil {
+		return nil, fmt.Errorf("decoding recorded player counts: %w", err)
+	}
+	return samples, nil
+}
+
+// FetchCurrentPlayers fetches the current player count of a Steam app from
+// Steam API without touching any component state.
+func FetchCurrentPlayers(ctx context.Context, appID int) (int, error) {
 	// TODO - Add origins API to avoid CORS issues
 	url := "https://api.allorigins.win/raw?url=" +
-		"https://api.steampowered.com/ISteamUserStats/GetNumberOfCurrentPlayers/v1/?appid=306130"
+		"https://api.steampowered.com/ISteamUserStats/GetNumberOfCurrentPlayers/v1/?appid=" + strconv.Itoa(appID)
 	client := &http.Client{Timeout: constant.FetchTimeout}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		log.Println("Error creating request:", err)
-		return constant.Unreachable
+		return 0, fmt.Errorf("creating request: %w", err)
 	}
 	resp, err := client.Do(req)
 	if err != nil {
-		log.Println("Error fetching current player count:", err)
-		return constant.Unreachable
+		return 0, fmt.Errorf("fetching current player count: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -69,12 +175,8 @@ func getCurrentPlayers(ctx app.Context) (string) {
 
 	if err = json.NewDecoder(resp.Body).Decode(&data); err != nil {
 		// TODO - Sometimes the return is broken with error <
-		log.Println("Error decoding response:", err)
-		return constant.Unreachable
+		return 0, fmt.Errorf("decoding current player count: %w", err)
 	}
 
-	ctx.SetState("currentPlayersResponse", strconv.Itoa(data.Response.PlayerCount)).
-	Persist().ExpiresIn(constant.PlayerCountCacheDuration) // TODO - ExpiresAt is always 0001-01-01T00:00:00Z inside local storage!?
-
-	return strconv.Itoa(data.Response.PlayerCount)
-}
\ No newline at end of file
+	return data.Response.PlayerCount, nil
+}
@@ -0,0 +1,13 @@
+package component
+
+import (
+	"maps"
+	"testing"
+)
+
+// RestoreWidgets puts the widget registry back as it is now when the test
+// ends, so widgets registered by the test don't leak into others.
+func RestoreWidgets(t testing.TB) {
+	registered := maps.Clone(widgets)
+	t.Cleanup(func() { widgets = registered })
+}
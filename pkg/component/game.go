@@ -0,0 +1,68 @@
+package component
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// Game is a Steam game whose player count is tracked.
+type Game struct {
+	AppID int    `json:"appId"`
+	Name  string `json:"name"`
+}
+
+// DefaultGame is The Elder Scrolls Online, used when no game is configured.
+var DefaultGame = Game{AppID: esoAppID, Name: "The Elder Scrolls Online"}
+
+// Games returns the configured games. The list is read from the
+// constant.GamesEnv variable, e.g. "306130:The Elder Scrolls Online;212500:The Lord of the Rings Online".
+func Games() []Game {
+	return ParseGames(app.Getenv(constant.GamesEnv))
+}
+
+// ParseGames parses a list of "appid:Name" entries separated by semicolons.
+// Invalid entries are skipped and an empty list yields DefaultGame.
+func ParseGames(config string) []Game {
+	var games []Game
+	for _, entry := range strings.Split(config, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, name, _ := strings.Cut(entry, ":")
+		appID, err := strconv.Atoi(strings.TrimSpace(id))
+		if err != nil || appID <= 0 {
+			log.Printf("Skipping invalid game %q: %v", entry, err)
+			continue
+		}
+
+		name = strings.TrimSpace(name)
+		if name == "" {
+			name = "App " + id
+		}
+		games = append(games, Game{AppID: appID, Name: name})
+	}
+
+	if len(games) == 0 {
+		return []Game{DefaultGame}
+	}
+	return games
+}
+
+// orDefault returns g, or DefaultGame when g is the zero value.
+func (g Game) orDefault() Game {
+	if g.AppID == 0 {
+		return DefaultGame
+	}
+	return g
+}
+
+// stateKey scopes a state key to the game, so games don't share cached counts.
+func (g Game) stateKey(key string) string {
+	return key + "-" + strconv.Itoa(g.AppID)
+}
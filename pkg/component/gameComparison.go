@@ -0,0 +1,81 @@
+package component
+
+import (
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// gameComparisonWidget compares the player counts of the configured games.
+var gameComparisonWidget = Widget{
+	ID:          "gameComparison",
+	Title:       "Game Comparison",
+	HeaderClass: "bg-secondary",
+	Width:       12,
+	New:         func() app.Composer { return &GameComparison{} },
+	// Only available when more than one game is configured
+	Available: func() bool { return len(Games()) > 1 },
+}
+
+// GameComparison is a component that displays the player counts of all
+// configured games side by side.
+type GameComparison struct {
+	app.Compo
+	Games []Game
+	// Lang is the language of the table headers.
+	Lang string
+	// rows are created once, so re-rendering the table keeps their counts.
+	rows []gameComparisonRow
+}
+
+// gameComparisonRow holds the stat components of one game.
+type gameComparisonRow struct {
+	current *CurrentPlayers
+	peak    *PeakPlayerCount
+	allPeak *AllPeakPlayerCount
+}
+
+// OnMount loads the configured games.
+func (g *GameComparison) OnMount(ctx app.Context) {
+	ObserveLanguage(ctx, &g.Lang, nil)
+	g.Games = Games()
+	g.rows = make([]gameComparisonRow, 0, len(g.Games))
+	for _, game := range g.Games {
+		g.rows = append(g.rows, gameComparisonRow{
+			current: &CurrentPlayers{Game: game},
+			peak:    &PeakPlayerCount{Game: game},
+			allPeak: &AllPeakPlayerCount{Game: game},
+		})
+	}
+}
+
+// OnPreRender renders the GameComparison in the language of the page on the server.
+func (g *GameComparison) OnPreRender(ctx app.Context) {
+	g.Lang = DefaultLanguage(ctx)
+}
+
+// Render is the main function that renders the game comparison component.
+func (g *GameComparison) Render() app.UI {
+	return app.Div().Class("table-responsive").Body(
+		app.Table().Class("table table-dark table-sm align-middle mb-0").Body(
+			app.THead().Body(
+				app.Tr().Body(
+					app.Th().Scope("col").Text(i18n.T(g.Lang, "Game")),
+					app.Th().Scope("col").Class("text-end").Text(i18n.T(g.Lang, "Active Users")),
+					app.Th().Scope("col").Class("text-end").Text(i18n.T(g.Lang, "24 Hour Peak")),
+					app.Th().Scope("col").Class("text-end").Text(i18n.T(g.Lang, "All-Time Peak")),
+				),
+			),
+			app.TBody().Body(
+				app.Range(g.rows).Slice(func(i int) app.UI {
+					row := g.rows[i]
+					return app.Tr().Body(
+						app.Th().Scope("row").Text(row.current.Game.Name),
+						app.Td().Class("text-end").Body(row.current),
+						app.Td().Class("text-end").Body(row.peak),
+						app.Td().Class("text-end").Body(row.allPeak),
+					)
+				}),
+			),
+		),
+	)
+}
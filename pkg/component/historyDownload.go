@@ -0,0 +1,57 @@
+package component
+
+import (
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// ExportPath is the API endpoint exporting the recorded history.
+const ExportPath = "/api/v1/export"
+
+// exportDownloads are the formats offered for download, by their label.
+var exportDownloads = []struct {
+	format string
+	label  string
+}{
+	{format: "csv", label: "CSV"},
+	{format: "json", label: "JSON"},
+}
+
+// HistoryDownload is a component that offers the player counts recorded by
+// the server for download, e.g. to correlate them with patches in a
+// spreadsheet.
+type HistoryDownload struct {
+	app.Compo
+	// lang isn't exported, so the card re-creating the component on every
+	// render doesn't reset it.
+	lang string
+}
+
+// OnMount follows the language.
+func (h *HistoryDownload) OnMount(ctx app.Context) {
+	ObserveLanguage(ctx, &h.lang, nil)
+}
+
+// OnPreRender renders the HistoryDownload in the language of the page on the server.
+func (h *HistoryDownload) OnPreRender(ctx app.Context) {
+	h.lang = DefaultLanguage(ctx)
+}
+
+// Render renders a download link per format. Static exports have no server
+// recording the history and render nothing.
+func (h *HistoryDownload) Render() app.UI {
+	if Static() {
+		return app.Div()
+	}
+
+	links := []app.UI{app.Span().Text(i18n.T(h.lang, "Download history:"))}
+	for _, download := range exportDownloads {
+		label := i18n.T(h.lang, download.label)
+		links = append(links, app.A().Class("ms-2").
+			Href(ExportPath+"?dataset=players&format="+download.format).
+			Download("eso-players."+download.format).
+			Aria("label", i18n.T(h.lang, "Download the player history as %s", label)).
+			Text(label))
+	}
+	return app.Div().Class("small mt-2").Body(links...)
+}
@@ -0,0 +1,70 @@
+package component
+
+import (
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// LanguageState is the state holding the language picked by the user. It is
+// kept in local storage; without it the language negotiated by the server is
+// used.
+const LanguageState = "language"
+
+// DefaultLanguage returns the language of the page, which the server
+// negotiated from the Accept-Language header, falling back to the browser
+// language.
+func DefaultLanguage(ctx app.Context) string {
+	if lang := i18n.Match(ctx.Page().Lang()); lang != "" {
+		return lang
+	}
+	if app.IsClient {
+		if lang := i18n.Match(app.Window().Get("navigator").Get("language").String()); lang != "" {
+			return lang
+		}
+	}
+	return i18n.Default
+}
+
+// ObserveLanguage keeps *lang in sync with the language picked by the user,
+// or sets it to DefaultLanguage when none was picked. onChange, if set, is
+// called after the language changed so texts built ahead of Render can be
+// rebuilt.
+func ObserveLanguage(ctx app.Context, lang *string, onChange func()) {
+	observer := ctx.ObserveState(LanguageState, lang)
+	if onChange != nil {
+		observer.OnChange(onChange)
+	}
+	if !i18n.Supported(*lang) {
+		*lang = DefaultLanguage(ctx)
+	}
+}
+
+// LanguageSwitcher is a component that lets users pick the language.
+type LanguageSwitcher struct {
+	app.Compo
+	Lang string
+}
+
+// OnMount reads the current language.
+func (l *LanguageSwitcher) OnMount(ctx app.Context) {
+	ObserveLanguage(ctx, &l.Lang, nil)
+}
+
+// OnPreRender renders the LanguageSwitcher in the language of the page on the server.
+func (l *LanguageSwitcher) OnPreRender(ctx app.Context) {
+	l.Lang = DefaultLanguage(ctx)
+}
+
+// Render is the main function that renders the LanguageSwitcher component.
+func (l *LanguageSwitcher) Render() app.UI {
+	return app.Select().Class("form-select form-select-sm w-auto").Title(i18n.T(l.Lang, "Language")).
+		OnChange(func(ctx app.Context, _ app.Event) {
+			ctx.SetState(LanguageState, ctx.JSSrc().Get("value").String()).Persist().Broadcast()
+		}).
+		Body(
+			app.Range(i18n.Languages).Slice(func(i int) app.UI {
+				language := i18n.Languages[i]
+				return app.Option().Value(language.Code).Text(language.Name).Selected(language.Code == l.Lang)
+			}),
+		)
+}
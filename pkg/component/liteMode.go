@@ -0,0 +1,106 @@
+package component
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// LiteModeState is the state holding the lite mode picked by the user: "on",
+// "off", or empty to follow the connection.
+const LiteModeState = "liteMode"
+
+// liteRefreshFactor is how much longer fetched data is cached in lite mode.
+const liteRefreshFactor = 4
+
+// saveDataParam marks the requests WithSaveData pre-renders in lite mode, as
+// components only see the URL of the request.
+const saveDataParam = "save-data"
+
+// WithSaveData returns r marked to be pre-rendered in lite mode when the
+// browser sends "Save-Data: on", so the first paint already saves data.
+func WithSaveData(r *http.Request) *http.Request {
+	if !strings.EqualFold(strings.TrimSpace(r.Header.Get("Save-Data")), "on") {
+		return r
+	}
+	r = r.Clone(r.Context())
+	query := r.URL.Query()
+	query.Set(saveDataParam, "on")
+	r.URL.RawQuery = query.Encode()
+	return r
+}
+
+// SavesData reports whether the browser asks to save data, as it does with
+// the Save-Data header, or is on a cellular or slow connection. On the server
+// it follows the header of the request being pre-rendered, see WithSaveData.
+func SavesData(ctx app.Context) bool {
+	if !app.IsClient {
+		return ctx.Page().URL().Query().Get(saveDataParam) == "on"
+	}
+
+	connection := app.Window().Get("navigator").Get("connection")
+	if !connection.Truthy() {
+		return false
+	}
+	if connection.Get("saveData").Truthy() {
+		return true
+	}
+	if connection.Get("type").Truthy() && connection.Get("type").String() == "cellular" {
+		return true
+	}
+	if effectiveType := connection.Get("effectiveType"); effectiveType.Truthy() {
+		switch effectiveType.String() {
+		case "slow-2g", "2g":
+			return true
+		}
+	}
+	return false
+}
+
+// LiteMode reports whether lite mode is on for the choice stored in
+// LiteModeState, following SavesData until the user picks.
+func LiteMode(ctx app.Context, choice string) bool {
+	switch choice {
+	case "on":
+		return true
+	case "off":
+		return false
+	default:
+		return SavesData(ctx)
+	}
+}
+
+// ObserveLiteMode keeps *lite in sync with the lite mode. onChange, if set, is
+// called after it changed.
+func ObserveLiteMode(ctx app.Context, lite *bool, onChange func()) {
+	choice := new(string)
+	ctx.ObserveState(LiteModeState, choice).OnChange(func() {
+		*lite = LiteMode(ctx, *choice)
+		if onChange != nil {
+			onChange()
+		}
+	})
+	*lite = LiteMode(ctx, *choice)
+}
+
+// SetLiteMode stores the lite mode picked by the user.
+func SetLiteMode(ctx app.Context, lite bool) {
+	choice := "off"
+	if lite {
+		choice = "on"
+	}
+	ctx.SetState(LiteModeState, choice).Persist().Broadcast()
+}
+
+// cacheDuration returns how long fetched data is cached, longer in lite mode
+// so it is fetched less often.
+func cacheDuration(ctx app.Context, d time.Duration) time.Duration {
+	choice := ""
+	ctx.GetState(LiteModeState, &choice)
+	if LiteMode(ctx, choice) {
+		return d * liteRefreshFactor
+	}
+	return d
+}
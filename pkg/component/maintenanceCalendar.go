@@ -0,0 +1,176 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/maintenance"
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// MaintenanceCalendarPath is the path of the iCalendar feed of maintenance windows.
+const MaintenanceCalendarPath = "/calendar/maintenance.ics"
+
+// maintenanceCacheDuration is the duration for which maintenance windows are cached.
+const maintenanceCacheDuration = 15 * time.Minute
+
+// countdownInterval is how often the countdowns are refreshed.
+const countdownInterval = time.Minute
+
+// maintenanceWidget lists the upcoming maintenance windows.
+var maintenanceWidget = Widget{
+	ID:          "maintenance",
+	Title:       "Upcoming Maintenance",
+	HeaderClass: "bg-warning",
+	Width:       12,
+	New:         func() app.Composer { return &MaintenanceCalendar{} },
+}
+
+// MaintenanceCalendar is a component that displays upcoming maintenance windows
+// with a countdown in the viewer's time zone.
+type MaintenanceCalendar struct {
+	app.Compo
+	Windows []maintenance.Window
+	Now     time.Time
+	// Lang is the language the windows are rendered in.
+	Lang   string
+	loaded bool
+}
+
+// OnMount loads the windows and starts refreshing the countdowns.
+func (m *MaintenanceCalendar) OnMount(ctx app.Context) {
+	ObserveLanguage(ctx, &m.Lang, nil)
+	ObserveRefresh(ctx, func() { m.Windows = getMaintenanceWindows(ctx) })
+	m.Windows = getMaintenanceWindows(ctx)
+	m.Now = time.Now()
+	m.loaded = true
+
+	ctx.Async(func() {
+		ticker := time.NewTicker(countdownInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				ctx.Dispatch(func(app.Context) {
+					m.Now = now
+				})
+			}
+		}
+	})
+}
+
+// OnPreRender renders the MaintenanceCalendar in the language of the page on the server.
+func (m *MaintenanceCalendar) OnPreRender(ctx app.Context) {
+	m.Lang = DefaultLanguage(ctx)
+}
+
+// Render is the main function that renders the maintenance calendar component.
+func (m *MaintenanceCalendar) Render() app.UI {
+	if !m.loaded {
+		return app.Span().Text(i18n.T(m.Lang, "Loading..."))
+	}
+
+	upcoming := maintenance.Upcoming(m.Windows, m.Now)
+	return app.Div().Body(
+		app.If(len(upcoming) == 0, func() app.UI {
+			return app.P().Class("mb-2").Text(i18n.T(m.Lang, "No maintenance announced"))
+		}).Else(func() app.UI {
+			return app.Ul().Class("list-group mb-2").Body(
+				app.Range(upcoming).Slice(func(i int) app.UI {
+					window := upcoming[i]
+					return app.Li().Class("list-group-item").Body(
+						app.Div().Class("d-flex justify-content-between").Body(
+							app.Span().Class("fw-bold").Text(window.Title),
+							app.Span().Class("badge bg-warning text-dark").Text(countdown(m.Lang, window, m.Now)),
+						),
+						app.Small().Class("d-block").Text(formatWindow(m.Lang, window)),
+					)
+				}),
+			)
+		}),
+		app.A().Href(MaintenanceCalendarPath).Download("eso-maintenance.ics").Class("small").Text(i18n.T(m.Lang, "Subscribe to the maintenance calendar (.ics)")),
+	)
+}
+
+// countdown describes how long until the window starts in the language.
+func countdown(lang string, window maintenance.Window, now time.Time) string {
+	remaining, started := maintenance.Remaining(window, now)
+	if started {
+		return i18n.T(lang, "ongoing")
+	}
+	return i18n.T(lang, "in %s", remaining)
+}
+
+// formatWindow formats the start and end of a window in the viewer's time zone.
+func formatWindow(lang string, window maintenance.Window) string {
+	format := func(t time.Time) string {
+		t = t.Local()
+		return i18n.Weekday(lang, t) + " " + i18n.FormatDateTime(lang, t) + " " + t.Format("MST")
+	}
+
+	text := format(window.Start)
+	if !window.End.IsZero() {
+		text += " – " + format(window.End)
+	}
+	return text
+}
+
+// getMaintenanceWindows Cache windows in state, reusing the cached status and news when available.
+func getMaintenanceWindows(ctx app.Context) []maintenance.Window {
+	var windows []maintenance.Window
+	ctx.GetState("maintenanceWindows", &windows)
+	if windows != nil {
+		return windows
+	}
+
+	serverStatus := ServerStatusResponse{}
+	ctx.GetState("serverStatusReport", &serverStatus)
+	rssFeed := RSSFeedResponse{}
+	ctx.GetState("rssFeedResponse", &rssFeed)
+
+	var err error
+	if !serverStatus.complete() || len(rssFeed.Items) == 0 {
+		windows, err = fetchOrSnapshot(ctx, "maintenanceWindows", func() ([]maintenance.Window, error) {
+			return FetchMaintenance(ctx)
+		})
+		if err != nil {
+			log.Println("Error fetching maintenance windows:", err)
+			return nil
+		}
+	} else {
+		windows = collectMaintenance(serverStatus, rssFeed)
+	}
+
+	ctx.SetState("maintenanceWindows", windows).Persist().ExpiresIn(cacheDuration(ctx, maintenanceCacheDuration)) // TODO - ExpiresAt is always 0001-01-01T00:00:00Z inside local storage!?
+	return windows
+}
+
+// FetchMaintenance collects the maintenance windows announced by the status
+// providers and the news feed. An error is only returned when both fail.
+func FetchMaintenance(ctx context.Context) ([]maintenance.Window, error) {
+	serverStatus, statusErr := FetchServerStatus(ctx)
+	rssFeed, feedErr := FetchRSSFeed(ctx)
+	if statusErr != nil && feedErr != nil {
+		return nil, errors.Join(statusErr, feedErr)
+	}
+	return collectMaintenance(serverStatus, rssFeed), nil
+}
+
+// collectMaintenance extracts the windows from the status announcements and news.
+func collectMaintenance(serverStatus ServerStatusResponse, rssFeed RSSFeedResponse) []maintenance.Window {
+	windows := maintenance.FromAnnouncements("Server status", serverStatus.Announcements)
+	for _, item := range rssFeed.Items {
+		if window, ok := maintenance.FromText(item.Title, item.Description, item.Link, item.Categories); ok {
+			windows = append(windows, window)
+		}
+	}
+	if windows == nil {
+		windows = []maintenance.Window{}
+	}
+	return windows
+}
@@ -0,0 +1,91 @@
+package component
+
+import (
+	"errors"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// StaleDataState is the state holding when the oldest snapshot shown instead
+// of fresh data was taken, zero while all data is fresh.
+const StaleDataState = "staleDataSince"
+
+// RefreshState is the state set to ask the components to load their data
+// again, e.g. when the connection comes back.
+const RefreshState = "refresh"
+
+// errOffline is returned when the browser is offline and nothing was saved.
+var errOffline = errors.New("offline and no saved data")
+
+// snapshot is the last value fetched for a state, kept without expiry so the
+// dashboard can be shown offline.
+type snapshot[T any] struct {
+	Value T         `json:"value"`
+	Taken time.Time `json:"taken"`
+}
+
+// Online reports whether the browser has a network connection.
+func Online() bool {
+	return !app.IsClient || app.Window().Get("navigator").Get("onLine").Bool()
+}
+
+// fetchOrSnapshot fetches a value while online and keeps it as the snapshot
+// of key. Offline, the fetch is skipped until the connection comes back;
+// offline or when fetching fails, the snapshot is returned instead and marked
+// as stale. A static export reads the value from its snapshot file instead.
+func fetchOrSnapshot[T any](ctx app.Context, key string, fetch func() (T, error)) (T, error) {
+	if app.IsClient && Static() {
+		value, taken, err := staticValue[T](ctx, key)
+		if err != nil {
+			return lastSnapshot[T](ctx, key, err)
+		}
+		ctx.SetState(key+"Snapshot", snapshot[T]{Value: value, Taken: taken}).Persist()
+		markStale(ctx, taken)
+		return value, nil
+	}
+
+	err := errOffline
+	if Online() {
+		var value T
+		if value, err = fetch(); err == nil {
+			ctx.SetState(key+"Snapshot", snapshot[T]{Value: value, Taken: time.Now()}).Persist()
+			return value, nil
+		}
+	}
+	return lastSnapshot[T](ctx, key, err)
+}
+
+// lastSnapshot returns the snapshot of key marked as stale, or err if there
+// is none.
+func lastSnapshot[T any](ctx app.Context, key string, err error) (T, error) {
+	var last snapshot[T]
+	ctx.GetState(key+"Snapshot", &last)
+	if last.Taken.IsZero() {
+		return last.Value, err
+	}
+	markStale(ctx, last.Taken)
+	return last.Value, nil
+}
+
+// markStale records that data taken at taken is shown, keeping the oldest time.
+func markStale(ctx app.Context, taken time.Time) {
+	var since time.Time
+	ctx.GetState(StaleDataState, &since)
+	if since.IsZero() || taken.Before(since) {
+		ctx.SetState(StaleDataState, taken)
+	}
+}
+
+// Refresh clears the stale data mark and asks the components to load their
+// data again. Cached data that is still fresh isn't fetched again.
+func Refresh(ctx app.Context) {
+	ctx.SetState(StaleDataState, time.Time{})
+	ctx.SetState(RefreshState, time.Now())
+}
+
+// ObserveRefresh calls load when Refresh is called.
+func ObserveRefresh(ctx app.Context, load func()) {
+	var requested time.Time
+	ctx.ObserveState(RefreshState, &requested).OnChange(load)
+}
@@ -0,0 +1,162 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/format"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/population"
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// populationWidget shows the player estimates per platform.
+var populationWidget = Widget{
+	ID:          "population",
+	Title:       "Players per Platform",
+	HeaderClass: "bg-secondary",
+	Width:       12,
+	New:         func() app.Composer { return &PlatformPopulation{} },
+	// Only available when non-Steam sources are configured
+	Available: func() bool { return len(ConfiguredPopulationProviders()) > 0 },
+}
+
+// PlatformPopulation is a component that displays the player population per platform.
+type PlatformPopulation struct {
+	app.Compo
+	// Lang is the language the estimates are rendered in.
+	Lang               string
+	PlatformPopulation app.UI
+}
+
+// steamPopulation provides the Steam player count of a game as a population estimate.
+type steamPopulation struct {
+	game Game
+}
+
+// Estimate returns the current Steam player count.
+func (s steamPopulation) Estimate(ctx context.Context) (population.Estimate, error) {
+	players, err := FetchCurrentPlayers(ctx, s.game.AppID)
+	if err != nil {
+		return population.Estimate{}, err
+	}
+	return population.Estimate{
+		Platform:   "PC (Steam)",
+		Players:    players,
+		Source:     "Steam API",
+		Confidence: population.ConfidenceHigh,
+	}, nil
+}
+
+// OnMount Check if the app is installable and set the state according.
+func (p *PlatformPopulation) OnMount(ctx app.Context) {
+	ObserveLanguage(ctx, &p.Lang, func() { p.PlatformPopulation = fetchPlatformPopulation(ctx, p.Lang) })
+	ObserveRefresh(ctx, func() { p.PlatformPopulation = fetchPlatformPopulation(ctx, p.Lang) })
+	p.PlatformPopulation = fetchPlatformPopulation(ctx, p.Lang)
+}
+
+// OnPreRender renders the PlatformPopulation in the language of the page on the server.
+func (p *PlatformPopulation) OnPreRender(ctx app.Context) {
+	p.Lang = DefaultLanguage(ctx)
+}
+
+// OnNav is called when the component is navigated to.
+func (p *PlatformPopulation) OnNav(ctx app.Context) {
+	p.PlatformPopulation = fetchPlatformPopulation(ctx, p.Lang)
+}
+
+// Render is the main function that renders the platform population component.
+func (p *PlatformPopulation) Render() app.UI {
+	if p.PlatformPopulation == nil {
+		return app.Span().Text(i18n.T(p.Lang, "Loading..."))
+	}
+	return p.PlatformPopulation
+}
+
+// ConfiguredPopulationProviders returns the non-Steam providers configured with
+// constant.PopulationEnv.
+func ConfiguredPopulationProviders() []population.JSONProvider {
+	providers, err := population.ParseProviders(app.Getenv(constant.PopulationEnv))
+	if err != nil {
+		log.Println("Error reading population providers:", err)
+		return nil
+	}
+	return providers
+}
+
+// PopulationProviders returns the Steam provider followed by the configured ones.
+func PopulationProviders() []population.Provider {
+	providers := []population.Provider{steamPopulation{game: DefaultGame}}
+	for _, provider := range ConfiguredPopulationProviders() {
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
+// FetchPopulation collects the estimates of every provider. Providers that fail
+// are logged and left out.
+func FetchPopulation(ctx context.Context, providers []population.Provider) []population.Estimate {
+	estimates := make([]population.Estimate, 0, len(providers))
+	for _, provider := range providers {
+		estimate, err := provider.Estimate(ctx)
+		if err != nil {
+			log.Println("Error fetching population estimate:", err)
+			continue
+		}
+		estimates = append(estimates, estimate)
+	}
+	return estimates
+}
+
+// confidenceClass returns the badge class for a confidence level.
+func confidenceClass(confidence population.Confidence) string {
+	switch confidence {
+	case population.ConfidenceHigh:
+		return "badge bg-success"
+	case population.ConfidenceMedium:
+		return "badge bg-warning text-dark"
+	case population.ConfidenceLow:
+		return "badge bg-secondary"
+	default:
+		return "badge bg-secondary"
+	}
+}
+
+// fetchPlatformPopulation Cache estimates in state, so they don't need to be fetched every time the page is loaded.
+func fetchPlatformPopulation(ctx app.Context, lang string) app.UI {
+	// Check if state value is set and return
+	var estimates []population.Estimate
+	ctx.GetState("platformPopulation", &estimates)
+
+	if len(estimates) == 0 {
+		estimates, _ = fetchOrSnapshot(ctx, "platformPopulation", func() ([]population.Estimate, error) {
+			if estimates := FetchPopulation(ctx, PopulationProviders()); len(estimates) > 0 {
+				return estimates, nil
+			}
+			return nil, errors.New("no population estimates")
+		})
+		if len(estimates) == 0 {
+			return app.Span().Text(i18n.T(lang, "Error loading population estimates"))
+		}
+		ctx.SetState("platformPopulation", estimates).Persist().ExpiresIn(cacheDuration(ctx, constant.PlayerCountCacheDuration)) // TODO - ExpiresAt is always 0001-01-01T00:00:00Z inside local storage!?
+	}
+
+	items := make([]app.UI, 0, len(estimates))
+	for _, estimate := range estimates {
+		items = append(items, app.Li().Class("list-group-item d-flex justify-content-between align-items-center").Body(
+			app.Div().Body(
+				app.Div().Class("fw-bold").Text(estimate.Platform),
+				app.Small().Class("text-muted").Text(i18n.T(lang, "Source: %s", estimate.Source)),
+			),
+			app.Div().Class("text-end").Body(
+				app.Div().Text(format.Number(estimate.Players, lang)),
+				app.Span().Class(confidenceClass(estimate.Confidence)).
+					Text(i18n.T(lang, string(estimate.Confidence)+" confidence")),
+			),
+		))
+	}
+
+	return app.Ul().Class("list-group").Body(items...)
+}
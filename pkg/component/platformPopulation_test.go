@@ -0,0 +1,32 @@
+package component_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+)
+
+func TestConfiguredPopulationProviders(t *testing.T) {
+	t.Setenv(constant.WidgetsEnv, "population")
+	t.Setenv(constant.PopulationEnv, `[{"platform": "Xbox", "url": "https://example.com/xbox.json"}]`)
+	if providers := component.ConfiguredPopulationProviders(); len(providers) != 1 || providers[0].Platform != "Xbox" {
+		t.Errorf("ConfiguredPopulationProviders() = %+v, want the Xbox provider", providers)
+	}
+	if len(component.ConfiguredWidgets()) != 1 {
+		t.Error("population widget unavailable with a provider configured")
+	}
+
+	// A malformed configuration is logged and leaves the widget out instead
+	// of failing the page.
+	for _, config := range []string{`[{"platform": "Xbox"`, `[{"platform": "Xbox"}]`, "xbox"} {
+		t.Setenv(constant.PopulationEnv, config)
+		if providers := component.ConfiguredPopulationProviders(); providers != nil {
+			t.Errorf("ConfiguredPopulationProviders() of %s = %+v, want none", config, providers)
+		}
+		if slices.ContainsFunc(component.ConfiguredWidgets(), func(widget component.Widget) bool { return widget.ID == "population" }) {
+			t.Errorf("population widget available with %s", config)
+		}
+	}
+}
@@ -1,47 +1,110 @@
 package component
 
 import (
+	"context"
 	"log"
-	"net/http"
+	"slices"
+	"time"
 
 	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
-	"github.com/PuerkitoBio/goquery"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/format"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/steamcharts"
 	"github.com/maxence-charriere/go-app/v10/pkg/app"
 )
 
 // PlayerCountResponse is struct that represents the player count data.
 type PlayerCountResponse struct {
-	Current string `json:"current"`
-	Peak    string `json:"24-peak"`
-	AllPeak	string `json:"all-time-peak"`
+	Current int `json:"current"`
+	Peak    int `json:"24-peak"`
+	AllPeak int `json:"all-time-peak"`
 }
 
-const (
-	Current = iota
-	Peak
-	AllPeak
-)
+// esoAppID is the Steam app ID of The Elder Scrolls Online.
+const esoAppID = 306130
+
+// GamePlayerCount is the player count of one tracked game.
+type GamePlayerCount struct {
+	Game
+	PlayerCountResponse
+}
+
+// statComparisons are the points in time each stat card compares against.
+var statComparisons = []struct {
+	ago   time.Duration
+	label string
+}{
+	{ago: time.Hour, label: "vs 1h ago"},
+	{ago: 24 * time.Hour, label: "vs yesterday"},
+}
+
+// peakWidget shows the 24 hour peak player count.
+var peakWidget = Widget{
+	ID:          "peak",
+	Title:       "24 Hour Peak",
+	HeaderClass: "bg-warning",
+	CardClass:   "text-center",
+	Width:       4,
+	Live:        true,
+	New:         func() app.Composer { return &PeakPlayerCount{} },
+	Wrap: func(body app.UI) app.UI {
+		return app.Div().Class("card-title h5").ID("24Peak").Body(body)
+	},
+}
+
+// allTimePeakWidget shows the all-time peak player count.
+var allTimePeakWidget = Widget{
+	ID:          "allTimePeak",
+	Title:       "All-Time Peak",
+	HeaderClass: "bg-danger",
+	CardClass:   "text-center",
+	Width:       4,
+	Live:        true,
+	New:         func() app.Composer { return &AllPeakPlayerCount{} },
+	Wrap: func(body app.UI) app.UI {
+		return app.Div().Class("card-title h5").ID("allPeak").Body(body)
+	},
+}
 
 // PeakPlayerCount is a component that displays the current player count.
 type PeakPlayerCount struct {
 	app.Compo
+	// Game is the game to display, The Elder Scrolls Online if left empty.
+	Game Game
+	// Lang is the language the count is rendered in.
+	Lang            string
 	PeakPlayerCount app.UI
 }
 
 // OnMount Check if the app is installable and set the state according.
 func (p *PeakPlayerCount) OnMount(ctx app.Context) {
-	p.PeakPlayerCount = app.Span().Text(getPeakPlayerCount(ctx))
+	ObserveLanguage(ctx, &p.Lang, func() { p.load(ctx) })
+	ObserveRefresh(ctx, func() { p.load(ctx) })
+	p.load(ctx)
+}
+
+// OnPreRender renders the PeakPlayerCount in the language of the page on the server.
+func (p *PeakPlayerCount) OnPreRender(ctx app.Context) {
+	p.Lang = DefaultLanguage(ctx)
 }
 
 // OnNav is called when the component is navigated to.
 func (p *PeakPlayerCount) OnNav(ctx app.Context) {
-	p.PeakPlayerCount = app.Span().Text(getPeakPlayerCount(ctx))
+	p.load(ctx)
+}
+
+// load reads the count and renders it in the current language.
+func (p *PeakPlayerCount) load(ctx app.Context) {
+	game := p.Game.orDefault()
+	count, err := getPeakPlayerCount(ctx, game)
+	p.PeakPlayerCount = playerStat(ctx, p.Lang, game.stateKey("peakPlayersHistory"), nil, count, err)
 }
 
 // Render is the main function that renders the current player count component.
 func (p *PeakPlayerCount) Render() app.UI {
 	if p.PeakPlayerCount == nil {
-		return app.Span().Text("Loading...")
+		return app.Span().Text(i18n.T(p.Lang, "Loading..."))
 	}
 	return p.PeakPlayerCount
 }
@@ -49,98 +112,142 @@ func (p *PeakPlayerCount) Render() app.UI {
 // AllPeakPlayerCount is a component that displays the current player count.
 type AllPeakPlayerCount struct {
 	app.Compo
+	// Game is the game to display, The Elder Scrolls Online if left empty.
+	Game Game
+	// Lang is the language the count is rendered in.
+	Lang               string
 	AllPeakPlayerCount app.UI
 }
 
 // OnMount Check if the app is installable and set the state according.
 func (a *AllPeakPlayerCount) OnMount(ctx app.Context) {
-	a.AllPeakPlayerCount = app.Span().Text(getAllPeakPlayerCount(ctx))
+	ObserveLanguage(ctx, &a.Lang, func() { a.load(ctx) })
+	ObserveRefresh(ctx, func() { a.load(ctx) })
+	a.load(ctx)
+}
+
+// OnPreRender renders the AllPeakPlayerCount in the language of the page on the server.
+func (a *AllPeakPlayerCount) OnPreRender(ctx app.Context) {
+	a.Lang = DefaultLanguage(ctx)
 }
 
 // OnNav is called when the component is navigated to.
 func (a *AllPeakPlayerCount) OnNav(ctx app.Context) {
-	a.AllPeakPlayerCount = app.Span().Text(getAllPeakPlayerCount(ctx))
+	a.load(ctx)
+}
+
+// load reads the count and renders it in the current language.
+func (a *AllPeakPlayerCount) load(ctx app.Context) {
+	game := a.Game.orDefault()
+	count, err := getAllPeakPlayerCount(ctx, game)
+	a.AllPeakPlayerCount = playerStat(ctx, a.Lang, game.stateKey("allPeakPlayersHistory"), nil, count, err)
 }
 
 // Render is the main function that renders the current player count component.
 func (a *AllPeakPlayerCount) Render() app.UI {
 	if a.AllPeakPlayerCount == nil {
-		return app.Span().Text("Loading...")
+		return app.Span().Text(i18n.T(a.Lang, "Loading..."))
 	}
 	return a.AllPeakPlayerCount
 }
 
-// getPeakPlayerCount fetches the peak player count from Steam API.
-func getPeakPlayerCount(ctx app.Context) (string) {
-	// Check if state value is set and return
-	peakPlayers := constant.Unreachable
-	ctx.GetState("peakPlayersResponse", &peakPlayers)
-
-	if peakPlayers != constant.Unreachable && peakPlayers != "" {
-		return peakPlayers
-	}
-
-	peakPlayers = getSteamChartsPlayerCount(ctx).Peak
-	ctx.SetState("peakPlayersResponse", peakPlayers).Persist().ExpiresIn(constant.PlayerCountCacheDuration) // TODO - ExpiresAt is always 0001-01-01T00:00:00Z inside local storage!?
+// getPeakPlayerCount returns the 24 hour peak of the game.
+func getPeakPlayerCount(ctx app.Context, game Game) (int, error) {
+	playerCount, err := getPlayerCount(ctx, game)
+	return playerCount.Peak, err
+}
 
-	return peakPlayers
+// getAllPeakPlayerCount returns the all-time peak of the game.
+func getAllPeakPlayerCount(ctx app.Context, game Game) (int, error) {
+	playerCount, err := getPlayerCount(ctx, game)
+	return playerCount.AllPeak, err
 }
 
-// getAllPeakPlayerCount fetches the peak player count from Steam API.
-func getAllPeakPlayerCount(ctx app.Context) (string) {
+// getPlayerCount returns the cached player counts of the game or fetches them
+// from Steam Charts. The peak cards of a game share the cache, so the page is
+// scraped once per game and refresh.
+func getPlayerCount(ctx app.Context, game Game) (PlayerCountResponse, error) {
 	// Check if state value is set and return
-	allPeakPlayers := constant.Unreachable
-	ctx.GetState("allPeakPlayersResponse", &allPeakPlayers)
+	playerCount := PlayerCountResponse{}
+	ctx.GetState(game.stateKey("playerCount"), &playerCount)
 
-	if allPeakPlayers != constant.Unreachable && allPeakPlayers != "" {
-		return allPeakPlayers
+	if playerCount.AllPeak > 0 {
+		return playerCount, nil
+	}
+
+	playerCount, err := fetchPlayerCount(ctx, game)
+	if err != nil {
+		log.Println("Error fetching player count:", err)
+		return PlayerCountResponse{}, err
 	}
+	ctx.SetState(game.stateKey("playerCount"), playerCount).Persist().ExpiresIn(cacheDuration(ctx, constant.PlayerCountCacheDuration)) // TODO - ExpiresAt is always 0001-01-01T00:00:00Z inside local storage!?
 
-	allPeakPlayers = getSteamChartsPlayerCount(ctx).AllPeak
-	ctx.SetState("allPeakPlayersResponse", allPeakPlayers).Persist().ExpiresIn(constant.PlayerCountCacheDuration) // TODO - ExpiresAt is always 0001-01-01T00:00:00Z inside local storage!?
+	return playerCount, nil
+}
 
-	return allPeakPlayers
+// fetchPlayerCount fetches the player count of a game, or returns its
+// snapshot when offline.
+func fetchPlayerCount(ctx app.Context, game Game) (PlayerCountResponse, error) {
+	return fetchOrSnapshot(ctx, game.stateKey("playerCount"), func() (PlayerCountResponse, error) {
+		return FetchPlayerCount(ctx, game.AppID)
+	})
 }
 
-// getSteamChartsPlayerCount fetches the player count from Steam Charts.
-func getSteamChartsPlayerCount(ctx app.Context) (PlayerCountResponse) {
+// FetchPlayerCount fetches the player count of a Steam app from Steam Charts. It
+// only needs a plain context, so it can be used by the client components and
+// the server API.
+func FetchPlayerCount(ctx context.Context, appID int) (PlayerCountResponse, error) {
 	// TODO - Add origins API to avoid CORS issues
-	url := "https://api.allorigins.win/raw?url=https://steamcharts.com/app/306130"
-	client := &http.Client{Timeout: constant.FetchTimeout}
+	url := "https://api.allorigins.win/raw?url=" + steamcharts.AppURL(appID)
 
-	playerCountReturn := PlayerCountResponse{constant.Unreachable, constant.Unreachable, constant.Unreachable}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	page, err := steamcharts.Fetch(ctx, url)
 	if err != nil {
-		log.Println("Error creating request:", err)
-		return playerCountReturn
+		return PlayerCountResponse{}, err
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Println("Error creating request:", err)
-		return playerCountReturn
-	}
-	defer resp.Body.Close()
+	return PlayerCountResponse{Current: page.Current, Peak: page.Peak, AllPeak: page.AllTimePeak}, nil
+}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+// playerStat renders a player count with its change compared to one hour ago
+// and the same time yesterday, and records the count as a new sample. The
+// changes are computed from the samples of this browser and the ones recorded
+// by the server, if any.
+func playerStat(ctx app.Context, lang, historyState string, recorded history.Series, count int, err error) app.UI {
 	if err != nil {
-		log.Println("Error fetching player count:", err)
-		return playerCountReturn
+		return app.Span().Text(i18n.T(lang, constant.Unreachable))
 	}
 
-	doc.Find("#app-heading .num").Each(func(i int, s *goquery.Selection) {
-		switch i {
-		case Current:	// Current player count
-			playerCountReturn.Current = s.Text()
-		case Peak:	// 24h peak player count
-			playerCountReturn.Peak = s.Text()
-		case AllPeak:	// All time peak player count
-			playerCountReturn.AllPeak = s.Text()
-		default:
-			return
+	samples := history.Series{}
+	ctx.GetState(historyState, &samples)
+	known := append(slices.Clone(recorded), samples...)
+
+	now := time.Now()
+	deltas := make([]app.UI, 0, len(statComparisons))
+	for _, comparison := range statComparisons {
+		delta, ok := known.Delta(now, count, comparison.ago)
+		if !ok {
+			continue
 		}
-	})
+		deltas = append(deltas, app.Span().Class("d-block small "+deltaClass(delta)).
+			Text(format.Delta(delta, lang)+" "+i18n.T(lang, comparison.label)))
+	}
+
+	ctx.SetState(historyState, samples.Add(now, count)).Persist()
 
-	return playerCountReturn
+	return app.Div().Body(
+		app.Span().Title(format.Compact(count, lang)).Text(format.Number(count, lang)),
+		app.Div().Class("mt-1").Body(deltas...),
+	)
+}
+
+// deltaClass returns the text colour class for a change.
+func deltaClass(delta int) string {
+	switch {
+	case delta > 0:
+		return "text-success"
+	case delta < 0:
+		return "text-danger"
+	default:
+		return "text-muted"
+	}
 }
@@ -0,0 +1,208 @@
+package component
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// PushSubscriptionsPath is the API endpoint push subscriptions are registered at.
+const PushSubscriptionsPath = "/api/v1/push/subscriptions"
+
+// pushRegistration is the body sent to PushSubscriptionsPath. Regions are the
+// IDs of the watched regions, all regions when empty.
+type pushRegistration struct {
+	Subscription app.NotificationSubscription `json:"subscription"`
+	Regions      []string                     `json:"regions"`
+}
+
+// notificationsWidget lets the browser subscribe to status changes.
+var notificationsWidget = Widget{
+	ID:          "notifications",
+	Title:       "Status Notifications",
+	HeaderClass: "bg-info",
+	CardClass:   "text-center",
+	Width:       12,
+	New:         func() app.Composer { return &PushNotifications{} },
+	// Static exports have no server to send the messages
+	Available: func() bool { return !Static() },
+}
+
+// PushNotifications is a component that lets users subscribe to push messages
+// about region status changes.
+type PushNotifications struct {
+	app.Compo
+	// Registration is the active subscription, zero when not subscribed.
+	Registration pushRegistration
+	// Regions are the regions ticked in the form.
+	Regions []string
+	// Lang is the language of the form.
+	Lang    string
+	Message string
+	busy    bool
+}
+
+// OnMount reads the active subscription from local storage.
+func (p *PushNotifications) OnMount(ctx app.Context) {
+	ObserveLanguage(ctx, &p.Lang, nil)
+	ctx.GetState("pushRegistration", &p.Registration)
+	p.Regions = slices.Clone(p.Registration.Regions)
+}
+
+// OnPreRender renders the PushNotifications in the language of the page on the server.
+func (p *PushNotifications) OnPreRender(ctx app.Context) {
+	p.Lang = DefaultLanguage(ctx)
+}
+
+// Render is the main function that renders the PushNotifications component.
+func (p *PushNotifications) Render() app.UI {
+	if app.Getenv(constant.VAPIDPublicKeyEnv) == "" {
+		return app.Div().Text(i18n.T(p.Lang, "Push notifications aren't available on this server"))
+	}
+
+	subscribed := p.Registration.Subscription.Endpoint != ""
+	subscribeLabel := i18n.T(p.Lang, "Enable notifications")
+	if subscribed {
+		subscribeLabel = i18n.T(p.Lang, "Update regions")
+	}
+	regions := Regions()
+
+	return app.Div().Body(
+		app.P().Class("small").Text(i18n.T(p.Lang, "Get notified when a region goes down or comes back. Leave every region unticked to watch all of them.")),
+		app.Div().Class("d-flex flex-wrap justify-content-center").Body(
+			app.Range(regions).Slice(func(i int) app.UI {
+				region := regions[i]
+				id := "push-region-" + region.ID
+				return app.Div().Class("form-check form-check-inline").Body(
+					app.Input().Type("checkbox").Class("form-check-input").ID(id).
+						Checked(slices.Contains(p.Regions, region.ID)).
+						OnChange(func(app.Context, app.Event) { p.toggleRegion(region.ID) }),
+					app.Label().Class("form-check-label").For(id).Text(region.Name),
+				)
+			}),
+		),
+		app.Div().Class("mt-2").Body(
+			app.Button().Class("btn btn-sm btn-outline-warning me-2").Disabled(p.busy).
+				Text(subscribeLabel).OnClick(p.onSubscribe),
+			app.If(subscribed, func() app.UI {
+				return app.Button().Class("btn btn-sm btn-outline-secondary").Disabled(p.busy).
+					Text(i18n.T(p.Lang, "Disable notifications")).OnClick(p.onUnsubscribe)
+			}),
+		),
+		app.If(p.Message != "", func() app.UI {
+			return app.Small().Class("d-block mt-2").Role("status").Text(p.Message)
+		}),
+	)
+}
+
+// toggleRegion ticks or unticks a region in the form.
+func (p *PushNotifications) toggleRegion(id string) {
+	if slices.Contains(p.Regions, id) {
+		p.Regions = slices.DeleteFunc(slices.Clone(p.Regions), func(s string) bool { return s == id })
+		return
+	}
+	p.Regions = append(slices.Clone(p.Regions), id)
+}
+
+// onSubscribe asks for the notification permission, subscribes to the push
+// service and registers the subscription with the server.
+func (p *PushNotifications) onSubscribe(ctx app.Context, _ app.Event) {
+	p.busy = true
+	regions := slices.Clone(p.Regions)
+
+	ctx.Async(func() {
+		registration, err := subscribePush(ctx, regions)
+
+		ctx.Dispatch(func(ctx app.Context) {
+			p.busy = false
+			if err != nil {
+				p.Message = i18n.T(p.Lang, "Error enabling notifications: %v", err)
+				return
+			}
+			p.Registration = registration
+			p.Message = i18n.T(p.Lang, "Notifications enabled")
+			ctx.SetState("pushRegistration", registration).Persist()
+		})
+	})
+}
+
+// onUnsubscribe removes the subscription from the server.
+func (p *PushNotifications) onUnsubscribe(ctx app.Context, _ app.Event) {
+	p.busy = true
+	endpoint := p.Registration.Subscription.Endpoint
+
+	ctx.Async(func() {
+		err := sendPushRequest(ctx, http.MethodDelete, map[string]string{"endpoint": endpoint})
+
+		ctx.Dispatch(func(ctx app.Context) {
+			p.busy = false
+			if err != nil {
+				p.Message = i18n.T(p.Lang, "Error disabling notifications: %v", err)
+				return
+			}
+			p.Registration = pushRegistration{}
+			p.Message = i18n.T(p.Lang, "Notifications disabled")
+			ctx.SetState("pushRegistration", p.Registration).Persist()
+		})
+	})
+}
+
+// subscribePush runs the browser side of the subscription. It blocks, so it
+// must be called from ctx.Async.
+func subscribePush(ctx app.Context, regions []string) (pushRegistration, error) {
+	switch ctx.Notifications().RequestPermission() {
+	case app.NotificationGranted:
+	case app.NotificationNotSupported:
+		return pushRegistration{}, errors.New("notifications aren't supported by this browser")
+	default:
+		return pushRegistration{}, errors.New("permission denied")
+	}
+
+	subscription, err := ctx.Notifications().Subscribe(app.Getenv(constant.VAPIDPublicKeyEnv))
+	if err != nil {
+		return pushRegistration{}, err
+	}
+
+	registration := pushRegistration{Subscription: subscription, Regions: regions}
+	if err := sendPushRequest(ctx, http.MethodPost, registration); err != nil {
+		return pushRegistration{}, err
+	}
+	return registration, nil
+}
+
+// sendPushRequest sends body as JSON to PushSubscriptionsPath of the server
+// the app was loaded from.
+func sendPushRequest(ctx context.Context, method string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+
+	endpoint := app.Window().URL().ResolveReference(&url.URL{Path: PushSubscriptionsPath})
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: constant.FetchTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
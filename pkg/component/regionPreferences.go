@@ -0,0 +1,58 @@
+package component
+
+import (
+	"slices"
+
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// regionPreferences are the regions a user starred or hid in the server status
+// card. They are kept in local storage.
+type regionPreferences struct {
+	Starred []string `json:"starred"`
+	Hidden  []string `json:"hidden"`
+}
+
+// getRegionPreferences reads the preferences from local storage.
+func getRegionPreferences(ctx app.Context) regionPreferences {
+	preferences := regionPreferences{}
+	ctx.GetState("regionPreferences", &preferences)
+	return preferences
+}
+
+// setRegionPreferences stores the preferences in local storage.
+func setRegionPreferences(ctx app.Context, preferences regionPreferences) {
+	ctx.SetState("regionPreferences", preferences).Persist()
+}
+
+// isStarred reports whether the region is starred.
+func (p regionPreferences) isStarred(id string) bool {
+	return slices.Contains(p.Starred, id)
+}
+
+// isHidden reports whether the region is hidden.
+func (p regionPreferences) isHidden(id string) bool {
+	return slices.Contains(p.Hidden, id)
+}
+
+// toggleStarred stars or unstars a region. Starring a hidden region shows it again.
+func (p regionPreferences) toggleStarred(id string) regionPreferences {
+	if p.isStarred(id) {
+		p.Starred = slices.DeleteFunc(slices.Clone(p.Starred), func(s string) bool { return s == id })
+		return p
+	}
+	p.Starred = append(slices.Clone(p.Starred), id)
+	p.Hidden = slices.DeleteFunc(slices.Clone(p.Hidden), func(s string) bool { return s == id })
+	return p
+}
+
+// toggleHidden hides or shows a region. Hiding a starred region unstars it.
+func (p regionPreferences) toggleHidden(id string) regionPreferences {
+	if p.isHidden(id) {
+		p.Hidden = slices.DeleteFunc(slices.Clone(p.Hidden), func(s string) bool { return s == id })
+		return p
+	}
+	p.Hidden = append(slices.Clone(p.Hidden), id)
+	p.Starred = slices.DeleteFunc(slices.Clone(p.Starred), func(s string) bool { return s == id })
+	return p
+}
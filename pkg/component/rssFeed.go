@@ -1,19 +1,53 @@
 package component
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"time"
 
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
 	"github.com/maxence-charriere/go-app/v10/pkg/app"
 )
 
+// newsWidget shows the latest news of the eso-hub feed.
+var newsWidget = Widget{
+	ID:          "news",
+	Title:       "Latest ESO News",
+	HeaderClass: "bg-success",
+	CardClass:   "d-flex flex-column",
+	BodyID:      "rss-feed",
+	BodyClass:   "flex-grow-1 m-4",
+	Width:       8,
+	New:         func() app.Composer { return &RSSFeed{} },
+}
+
 // RSSFeed is a component that displays an RSS feed.
 type RSSFeed struct {
 	app.Compo
-	RSSFeed app.UI
+	// Lang is the language of the texts around the news.
+	Lang string
+	// NewsLang is the language of the feed picked by the user, Lang's feed
+	// or the English one when empty.
+	NewsLang string
+	RSSFeed  app.UI
+	// lite shows smaller thumbnails.
+	lite bool
+}
+
+// NewsLanguages are the languages eso-hub publishes a news feed in.
+var NewsLanguages = []string{"en", "de", "fr"}
+
+// newsFeedURL returns the eso-hub news feed of a language, read through rss2json.
+func newsFeedURL(lang string) string {
+	return "https://api.rss2json.com/v1/api.json?rss_url=https://eso-hub.com/" + lang + "/news/feed.rss"
 }
 
 // maxRSSItems is the maximum number of RSS items to display.
@@ -25,67 +59,124 @@ const rssFeedCacheDuration = 24 * time.Hour
 // errorFetchingRSSFeed is the error message displayed when fetching the RSS feed fails.
 const errorFetchingRSSFeed = "Error fetching RSS feed"
 
+// newsLanguageState is the state holding the news feed language picked by the user.
+const newsLanguageState = "newsLanguage"
+
+// ImageProxyPath is where the server serves scaled down images of other hosts.
+const ImageProxyPath = "/img"
+
+// thumbnailURL returns the URL of an image scaled down to width by the
+// server. Static exports have no server and load the original image.
+func thumbnailURL(src string, width int) string {
+	if Static() {
+		return src
+	}
+	return ImageProxyPath + "?url=" + url.QueryEscape(src) + "&w=" + strconv.Itoa(width)
+}
+
 // RSSFeedResponse is struct that represents the RSS feed data.
-type rssFeedResponse struct {
-	Items []struct {
-		Title       string `json:"title"`
-		Link        string `json:"link"`
-		Description string `json:"description"`
-		Thumbnail   string `json:"thumbnail"`
-		PubDate     string `json:"pubDate"`
-	} `json:"items"`
+type RSSFeedResponse struct {
+	Items []RSSItem `json:"items"`
+}
+
+// RSSItem is a single news article of the RSS feed.
+type RSSItem struct {
+	Title       string   `json:"title"`
+	Link        string   `json:"link"`
+	Description string   `json:"description"`
+	Thumbnail   string   `json:"thumbnail"`
+	PubDate     string   `json:"pubDate"`
+	Categories  []string `json:"categories"`
 }
 
 // OnMount Check if the app is installable and set the state according.
 func (r *RSSFeed) OnMount(ctx app.Context) {
-	r.RSSFeed = fetchRSSFeed(ctx)
+	ObserveLanguage(ctx, &r.Lang, func() { r.load(ctx) })
+	ObserveLiteMode(ctx, &r.lite, func() { r.load(ctx) })
+	ObserveRefresh(ctx, func() { r.load(ctx) })
+	ctx.GetState(newsLanguageState, &r.NewsLang)
+	r.load(ctx)
+}
+
+// OnPreRender renders the RSSFeed in the language of the page on the server,
+// with the small thumbnails when the request asks to save data.
+func (r *RSSFeed) OnPreRender(ctx app.Context) {
+	r.Lang = DefaultLanguage(ctx)
+	r.lite = SavesData(ctx)
 }
 
 // OnNav is called when the component is navigated to.
 func (r *RSSFeed) OnNav(ctx app.Context) {
-    r.RSSFeed = fetchRSSFeed(ctx)
+	r.load(ctx)
+}
+
+// load renders the feed of the news language.
+func (r *RSSFeed) load(ctx app.Context) {
+	r.RSSFeed = fetchRSSFeed(ctx, r.Lang, r.newsLanguage(), r.lite)
+}
+
+// newsLanguage returns the language of the feed to show.
+func (r *RSSFeed) newsLanguage() string {
+	for _, lang := range []string{r.NewsLang, r.Lang} {
+		if slices.Contains(NewsLanguages, lang) {
+			return lang
+		}
+	}
+	return i18n.Default
 }
 
 // Render is the main function that renders the RSS feed component.
 func (r *RSSFeed) Render() app.UI {
-	return app.Div().Body(r.RSSFeed)
+	newsLang := r.newsLanguage()
+	return app.Div().Body(
+		app.Div().Class("d-flex justify-content-end mb-2").Body(
+			app.Select().Class("form-select form-select-sm w-auto").Title(i18n.T(r.Lang, "News language")).
+				OnChange(func(ctx app.Context, _ app.Event) {
+					r.NewsLang = ctx.JSSrc().Get("value").String()
+					ctx.SetState(newsLanguageState, r.NewsLang).Persist()
+					r.load(ctx)
+				}).
+				Body(
+					app.Range(i18n.Languages).Slice(func(i int) app.UI {
+						language := i18n.Languages[i]
+						if !slices.Contains(NewsLanguages, language.Code) {
+							return nil
+						}
+						return app.Option().Value(language.Code).Text(language.Name).Selected(language.Code == newsLang)
+					}),
+				),
+		),
+		r.RSSFeed,
+	)
+}
+
+// rssFeedState returns the state the feed of a language is cached in. The
+// English one keeps its original name as the maintenance calendar reads it.
+func rssFeedState(newsLang string) string {
+	if newsLang == i18n.Default {
+		return "rssFeedResponse"
+	}
+	return "rssFeedResponse-" + newsLang
 }
 
 // fetchRSSFeed Cache RSS feed data in state, so it doesn't need to be fetched every time the page is loaded.
-func fetchRSSFeed(ctx app.Context) app.UI {
+func fetchRSSFeed(ctx app.Context, lang, newsLang string, lite bool) app.UI {
 	// Check if state value is set and return
-	rssFeed := rssFeedResponse{}
-	ctx.GetState("rssFeedResponse", &rssFeed)
+	rssFeed := RSSFeedResponse{}
+	ctx.GetState(rssFeedState(newsLang), &rssFeed)
 	if len(rssFeed.Items) == 0 {
-		// Make API request
-		url := "https://api.rss2json.com/v1/api.json?rss_url=https://eso-hub.com/en/news/feed.rss"
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		var err error
+		rssFeed, err = fetchOrSnapshot(ctx, rssFeedState(newsLang), func() (RSSFeedResponse, error) {
+			return FetchLocalizedRSSFeed(ctx, newsLang)
+		})
 		if err != nil {
-			log.Println("Error creating request:", err)
-			return app.Span().Text(errorFetchingRSSFeed)
-		}
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			log.Println("Error making request:", err)
-			return app.Span().Text(errorFetchingRSSFeed)
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			log.Println("Error reading response:", err)
-			return app.Span().Text(errorFetchingRSSFeed)
-		}
-
-		err = json.Unmarshal(body, &rssFeed)
-		if err != nil {
-			log.Println("Error parsing JSON:", err)
-			return app.Span().Text(errorFetchingRSSFeed)
+			log.Println("Error fetching RSS feed:", err)
+			return app.Span().Text(i18n.T(lang, errorFetchingRSSFeed))
 		}
 	}
 
 	// Set state value to expire in 24 hours
-	ctx.SetState("rssFeedResponse", rssFeed).Persist().ExpiresIn(rssFeedCacheDuration) // TODO - ExpiresAt is always 0001-01-01T00:00:00Z inside local storage!?
+	ctx.SetState(rssFeedState(newsLang), rssFeed).Persist().ExpiresIn(cacheDuration(ctx, rssFeedCacheDuration)) // TODO - ExpiresAt is always 0001-01-01T00:00:00Z inside local storage!?
 
 	// Wrap the items in a div
 	div := app.Div().Class("rss-feed").Class("d-flex flex-column gap-3")
@@ -99,17 +190,25 @@ func fetchRSSFeed(ctx app.Context) app.UI {
 			continue
 		}
 
+		// Thumbnails are scaled down and cached by the server.
+		thumbnail := thumbnailURL(item.Thumbnail, constant.ThumbnailWidth)
+		if lite {
+			thumbnail = thumbnailURL(item.Thumbnail, constant.LiteThumbnailWidth)
+		}
+
 		itemsDiv[i] = app.Div().Class("relative block bg-gray-900 border border-gray-900 shadow-lg rounded overflow-hidden").
 			Body(
-				app.A().Href(item.Link).Target("_blank").Style("text-decoration", "none").
+				app.A().Href(item.Link).Target("_blank").Rel("noopener").Style("text-decoration", "none").
 					Body(
-						app.Img().Style("width", "100%").Src(item.Thumbnail).Alt(item.Title),
+						// The title below names the link, so the thumbnail is decorative.
+						app.Img().Style("width", "100%").Src(thumbnail).Alt("").Attr("loading", "lazy"),
 						app.Div().Class("absolute bottom-0 left-0 right-0 p-4 bg-gradient-to-t from-black via-black/60 to-transparent").
 							Body(
 								app.H3().Class("text-white text-2xl font-semibold shadow-black line-clamp-2").
-								Text(item.Title).Style("text-shadow", "black 2px 2px 1px"),
+									Text(item.Title).Style("text-shadow", "black 2px 2px 1px"),
 								app.P().Class("text-white mt-2 line-clamp-2 text-sm").Text(item.Description),
-								app.P().Class("text-white text-sm mt-2 line-clamp-1").Text("Published on "+pubDate.Format("2006-01-02")),
+								app.P().Class("text-white text-sm mt-2 line-clamp-1").Text(i18n.T(lang, "Published on %s", i18n.FormatDate(lang, pubDate))),
+								app.Span().Class("visually-hidden").Text(i18n.T(lang, "(opens in a new tab)")),
 							),
 					),
 			)
@@ -117,9 +216,47 @@ func fetchRSSFeed(ctx app.Context) app.UI {
 
 	// Check if itemsDiv is empty
 	if len(itemsDiv) == 0 {
-		return app.Div().Text("Error parsing RSS feed")
+		return app.Div().Text(i18n.T(lang, "Error parsing RSS feed"))
 	}
 
 	div.Body(itemsDiv...)
 	return div
 }
+
+// FetchRSSFeed fetches the English ESO news feed without touching any component state.
+func FetchRSSFeed(ctx context.Context) (RSSFeedResponse, error) {
+	return FetchLocalizedRSSFeed(ctx, i18n.Default)
+}
+
+// FetchLocalizedRSSFeed fetches the ESO news feed of one of NewsLanguages
+// without touching any component state.
+func FetchLocalizedRSSFeed(ctx context.Context, newsLang string) (RSSFeedResponse, error) {
+	rssFeed := RSSFeedResponse{}
+	if !slices.Contains(NewsLanguages, newsLang) {
+		return rssFeed, fmt.Errorf("no news feed in %q", newsLang)
+	}
+
+	// Make API request
+	url := newsFeedURL(newsLang)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return rssFeed, fmt.Errorf("creating request: %w", err)
+	}
+	client := &http.Client{Timeout: constant.FetchTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return rssFeed, fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return rssFeed, fmt.Errorf("reading response: %w", err)
+	}
+
+	if err = json.Unmarshal(body, &rssFeed); err != nil {
+		return rssFeed, fmt.Errorf("parsing JSON: %w", err)
+	}
+
+	return rssFeed, nil
+}
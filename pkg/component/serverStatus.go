@@ -1,62 +1,70 @@
 package component
 
 import (
+	"context"
 	"log"
-	"net/http"
-	"reflect"
 	"time"
 
 	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
-	"github.com/PuerkitoBio/goquery"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
 	"github.com/maxence-charriere/go-app/v10/pkg/app"
 )
 
-// serverStatusResponse is struct that represents the server status data.
-type serverStatusResponse struct {
-	PCEU    string `json:"PC-EU"`
-	PCNA    string `json:"PC-NA"`
-	PCPTS 	string `json:"PC-PTS"`
-	XBOXEU  string `json:"XBOX-EU"`
-	XBOXNA  string `json:"XBOX-NA"`
-	PS4NA	string `json:"PS4-NA"`
-	PS4EU	string `json:"PS4-EU"`
+// serverStatusWidget shows the state of every region.
+var serverStatusWidget = Widget{
+	ID:          "serverStatus",
+	Title:       "ESO Server Status",
+	HeaderClass: "bg-primary",
+	CardClass:   "flex d-flex flex-column",
+	BodyClass:   "flex-grow-1 text-center flex-row d-flex align-items-center justify-content-center m-2",
+	Width:       4,
+	Live:        true,
+	New:         func() app.Composer { return &ServerStatus{} },
+	Wrap: func(body app.UI) app.UI {
+		return app.Div().Style("width", "100%").Body(
+			app.Div().ID("serverStatusList").Body(body),
+		)
+	},
+}
+
+// ServerStatusResponse is struct that represents the server status data.
+type ServerStatusResponse struct {
+	esostatus.Report
 }
 
 // ServerStatusType represents the different states a server can be in.
 type serverStatusType string
 
 const (
-	ServerStatusOperational      serverStatusType = "All servers operational"
-	ServerStatusMinorIssues      serverStatusType = "Minor issues detected"
-	ServerStatusMaintenance      serverStatusType = "Server maintenance ongoing"
-	ServerStatusCritical         serverStatusType = "Critical failure detected"
+	ServerStatusOperational serverStatusType = "All servers operational"
+	ServerStatusMinorIssues serverStatusType = "Minor issues detected"
+	ServerStatusMaintenance serverStatusType = "Server maintenance ongoing"
+	ServerStatusCritical    serverStatusType = "Critical failure detected"
 )
 
 func (s serverStatusType) String() string {
 	return string(s)
 }
 
-// ServerRegion represents the different regions a server can be in.
-type serverRegion string
-
-const (
-	PCEU   		serverRegion = "PC-EU"
-	PCNA  		serverRegion = "PC-NA"
-	PCPTS       serverRegion = "PC-PTS"
-	XBOXEU  	serverRegion = "XBOX-EU"
-	XBOXNA 		serverRegion = "XBOX-NA"
-	PS4NA		serverRegion = "PS4-NA"
-	PS4EU   	serverRegion = "PS4-EU"
-)
-
-func (s serverRegion) String() string {
-	return string(s)
+// Text returns the status translated into the language.
+func (s serverStatusType) Text(lang string) string {
+	return i18n.T(lang, string(s))
 }
 
 // ServerStatus is a component that displays the server status.
 type ServerStatus struct {
 	app.Compo
-	ServerStatus app.UI
+	Status      ServerStatusResponse
+	Preferences regionPreferences
+	// ShowOthers expands the regions collapsed into the summary line.
+	ShowOthers bool
+	// ShowHidden lists the hidden regions so they can be shown again.
+	ShowHidden bool
+	// Lang is the language the status is rendered in.
+	Lang   string
+	loaded bool
+	failed bool
 }
 
 // serverStatusCacheDuration is the duration for which the data is cached.
@@ -64,137 +72,249 @@ const serverStatusCacheDuration = 5 * time.Minute
 
 // OnMount Check if the app is installable and set the state according.
 func (s *ServerStatus) OnMount(ctx app.Context) {
-	s.ServerStatus = fetchServerStatus(ctx)
+	ObserveLanguage(ctx, &s.Lang, nil)
+	ObserveRefresh(ctx, func() { s.load(ctx) })
+	s.load(ctx)
+}
+
+// OnPreRender renders the ServerStatus in the language of the page on the server.
+func (s *ServerStatus) OnPreRender(ctx app.Context) {
+	s.Lang = DefaultLanguage(ctx)
 }
 
 // OnNav is called when the component is navigated to.
 func (s *ServerStatus) OnNav(ctx app.Context) {
-	s.ServerStatus = fetchServerStatus(ctx)
+	s.load(ctx)
+}
+
+// load reads the status and the region preferences of the user.
+func (s *ServerStatus) load(ctx app.Context) {
+	var err error
+	s.Status, err = fetchServerStatus(ctx)
+	s.failed = err != nil
+	s.Preferences = getRegionPreferences(ctx)
+	s.loaded = true
 }
 
 // Render is the main function that renders the ServerStatus component.
 func (s *ServerStatus) Render() app.UI {
-	return app.Div().Body(s.ServerStatus)
+	switch {
+	case !s.loaded:
+		return app.Div().Text(i18n.T(s.Lang, "Loading..."))
+	case s.failed:
+		return app.Div().Text(i18n.T(s.Lang, "Error loading server status"))
+	}
+
+	var starred, others, hidden []esostatus.RegionStatus
+	for _, status := range s.Status.Regions {
+		switch {
+		case s.Preferences.isHidden(status.Region):
+			hidden = append(hidden, status)
+		case s.Preferences.isStarred(status.Region):
+			starred = append(starred, status)
+		default:
+			others = append(others, status)
+		}
+	}
+
+	// Others are only collapsed next to starred regions and when nothing is wrong with them.
+	collapse := len(starred) > 0 && allOnline(others) && !s.ShowOthers
+
+	statusList := make([]app.UI, 0, len(s.Status.Regions)+1)
+	for _, status := range starred {
+		statusList = append(statusList, s.renderRegionStatus(status))
+	}
+	if collapse && len(others) > 0 {
+		statusList = append(statusList, app.Li().Class("list-group-item bg-success d-flex justify-content-between").Body(
+			app.Span().Body(
+				app.Span().Class("me-1").Aria("hidden", true).Text(statusIcon(esostatus.StateOnline)),
+				app.Text(i18n.T(s.Lang, "All %d other regions OK", len(others))),
+			),
+			app.Button().Class("btn btn-sm btn-link p-0 text-white").Text(i18n.T(s.Lang, "Show")).
+				OnClick(func(app.Context, app.Event) { s.ShowOthers = true }),
+		))
+	} else {
+		for _, status := range others {
+			statusList = append(statusList, s.renderRegionStatus(status))
+		}
+	}
+	if s.ShowHidden {
+		for _, status := range hidden {
+			statusList = append(statusList, s.renderRegionStatus(status))
+		}
+	}
+
+	announcements := make([]app.UI, 0, len(s.Status.Announcements))
+	for _, announcement := range s.Status.Announcements {
+		announcements = append(announcements, app.Div().Class("alert alert-warning mt-2 mb-0 small").Text(announcement.Message))
+	}
+
+	return app.Div().Body(
+		app.Ul().Class("list-group").Body(statusList...),
+		app.Div().Body(announcements...),
+		app.If(len(starred) > 0 && len(others) > 0 && s.ShowOthers, func() app.UI {
+			return app.Button().Class("btn btn-sm btn-link").Text(i18n.T(s.Lang, "Collapse other regions")).
+				OnClick(func(app.Context, app.Event) { s.ShowOthers = false })
+		}),
+		app.If(len(hidden) > 0, func() app.UI {
+			label := i18n.T(s.Lang, "Show %d hidden regions", len(hidden))
+			if s.ShowHidden {
+				label = i18n.T(s.Lang, "Hide hidden regions")
+			}
+			return app.Button().Class("btn btn-sm btn-link").Aria("expanded", s.ShowHidden).Text(label).
+				OnClick(func(app.Context, app.Event) { s.ShowHidden = !s.ShowHidden })
+		}),
+		app.If(!s.Status.UpdatedAt.IsZero(), func() app.UI {
+			return app.Small().Class("d-block mt-2 text-muted").
+				Text(i18n.T(s.Lang, "Last updated %s", i18n.FormatDateTime(s.Lang, s.Status.UpdatedAt.Local())))
+		}),
+	)
+}
+
+// statusIcon returns the symbol shown next to a state, so the state isn't
+// told by the colour alone.
+func statusIcon(state esostatus.State) string {
+	switch state {
+	case esostatus.StateOnline:
+		return "✔"
+	case esostatus.StateOffline:
+		return "✖"
+	case esostatus.StateMaintenance:
+		return "⚠"
+	default:
+		return "?"
+	}
 }
 
 // getStatusClass returns the class name based on the server status.
-func getStatusClass(status string) string {
-	switch status {
-	case "Online":
+func getStatusClass(state esostatus.State) string {
+	switch state {
+	case esostatus.StateOnline:
 		return "list-group-item bg-success"
-	case "Offline":
+	case esostatus.StateOffline, esostatus.StateMaintenance:
 		return "list-group-item bg-warning"
+	case esostatus.StateUnknown:
+		return "list-group-item bg-danger"
 	default:
 		return "list-group-item bg-danger"
 	}
 }
 
-// fetchServerStatus Cache data in state, so it doesn't need to be fetched every time the page is loaded.
-func fetchServerStatus(ctx app.Context) app.UI {
-	// Check if state value is set and return
-	serverStatus := serverStatusResponse{}
-	ctx.GetState("serverStatusResponse", &serverStatus)
-
-	// Create a list to hold the server status items
-	statusList := make([]app.UI, reflect.TypeOf(serverStatusResponse{}).NumField())
-
-	// Check if any entries in struct are empty
-	if serverStatus.PCEU != "" && serverStatus.PCNA != "" && serverStatus.PCPTS != "" &&
-	serverStatus.XBOXEU != "" && serverStatus.XBOXNA != "" && 
-	serverStatus.PS4NA != "" && serverStatus.PS4EU != "" {
-		// Return cached response
-		// Loop through each struct member and retrieve the cached status
-		statusList = []app.UI{
-			app.Li().Class(getStatusClass(serverStatus.PCEU)).
-			Body(app.Text(string(PCEU) + ": " + serverStatus.PCEU)),
-			app.Li().Class(getStatusClass(serverStatus.PCNA)).
-			Body(app.Text(string(PCNA) + ": " + serverStatus.PCNA)),
-			app.Li().Class(getStatusClass(serverStatus.PCPTS)).
-			Body(app.Text(string(PCPTS) + ": " + serverStatus.PCPTS)),
-			app.Li().Class(getStatusClass(serverStatus.XBOXEU)).
-			Body(app.Text(string(XBOXEU) + ": " + serverStatus.XBOXEU)),
-			app.Li().Class(getStatusClass(serverStatus.XBOXNA)).
-			Body(app.Text(string(XBOXNA) + ": " + serverStatus.XBOXNA)),
-			app.Li().Class(getStatusClass(serverStatus.PS4NA)).
-			Body(app.Text(string(PS4NA) + ": " + serverStatus.PS4NA)),
-			app.Li().Class(getStatusClass(serverStatus.PS4EU)).
-			Body(app.Text(string(PS4EU) + ": " + serverStatus.PS4EU)),
+// allOnline reports whether every region is online.
+func allOnline(statuses []esostatus.RegionStatus) bool {
+	for _, status := range statuses {
+		if status.State != esostatus.StateOnline {
+			return false
 		}
-		return app.Div().Body(statusList...)
 	}
+	return true
+}
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: constant.FetchTimeout,
-	}
+// fetchServerStatus Cache data in state, so it doesn't need to be fetched every time the page is loaded.
+func fetchServerStatus(ctx app.Context) (ServerStatusResponse, error) {
+	// Check if state value is set and return
+	serverStatus := ServerStatusResponse{}
+	ctx.GetState("serverStatusReport", &serverStatus)
 
-	// TODO - Add origins API to avoid CORS issues
-	// Make request through CORS proxy
-	req, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodGet,
-		"https://api.allorigins.win/raw?url=https://esoserverstatus.net/",
-		nil,
-		)
-	if err != nil {
-		log.Printf("Error creating request: %v", err)
-		return app.Div().Text("Error creating request")
+	// Only use the cached response when every region is set
+	if serverStatus.complete() {
+		return serverStatus, nil
 	}
-	resp, err := client.Do(req)
+
+	serverStatus, err := fetchOrSnapshot(ctx, "serverStatusReport", func() (ServerStatusResponse, error) {
+		return FetchServerStatus(ctx)
+	})
 	if err != nil {
 		log.Printf("Error fetching server status: %v", err)
-		return app.Div().Text("Error loading server status")
+		return serverStatus, err
 	}
-	defer resp.Body.Close()
 
-	// Parse HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		log.Printf("Error parsing response: %v", err)
-		return app.Div().Text("Error parsing server status")
-	}
-
-	// Check each server region
-	for key, region := range []serverRegion{PCEU, PCNA, PCPTS, XBOXEU, XBOXNA, PS4NA, PS4EU} {
-		selector := "#" + string(region)
-		status := "Unknown"
-		
-		doc.Find(selector).Each(func(_ int, s *goquery.Selection) {
-			if b := s.Find("b"); b.Length() > 0 {
-				status = b.Text()
-			}
-		})
-
-		// Set the status in the struct based on the current region
-		switch region {
-		case PCEU:
-			serverStatus.PCEU = status
-		case PCNA:
-			serverStatus.PCNA = status
-		case PCPTS:
-			serverStatus.PCPTS = status
-		case XBOXEU:
-			serverStatus.XBOXEU = status
-		case XBOXNA:
-			serverStatus.XBOXNA = status
-		case PS4NA:
-			serverStatus.PS4NA = status
-		case PS4EU:
-			serverStatus.PS4EU = status
-		}
+	// Cache the result
+	ctx.SetState("serverStatusReport", serverStatus).Persist().ExpiresIn(cacheDuration(ctx, serverStatusCacheDuration)) // TODO - ExpiresAt is always 0001-01-01T00:00:00Z inside local storage!?
+
+	return serverStatus, nil
+}
 
-		statusList[key] = app.Li().Class(getStatusClass(status)).Body(app.Text(string(region) + ": " + status))
+// renderRegionStatus renders the list item of a single region with its star and hide buttons.
+func (s *ServerStatus) renderRegionStatus(status esostatus.RegionStatus) app.UI {
+	starLabel := "☆"
+	if s.Preferences.isStarred(status.Region) {
+		starLabel = "★"
 	}
-	
-	// Check if statusList is empty
-	if len(statusList) == 0 {
-		return app.Div().Text("Error parsing server status")
+	hideLabel, hideTitle := i18n.T(s.Lang, "Hide"), i18n.T(s.Lang, "Hide region")
+	if s.Preferences.isHidden(status.Region) {
+		hideLabel, hideTitle = i18n.T(s.Lang, "Unhide"), i18n.T(s.Lang, "Unhide region")
 	}
 
-	statusDiv := app.Div().Body(statusList...)
+	return app.Li().Class(getStatusClass(status.State)).Body(
+		app.Div().Class("d-flex justify-content-between align-items-center").Body(
+			app.Span().Body(
+				app.Span().Class("me-1").Aria("hidden", true).Text(statusIcon(status.State)),
+				app.Text(status.Name+": "+i18n.T(s.Lang, status.State.String())),
+			),
+			app.Span().Body(
+				app.Button().Class("btn btn-sm btn-link p-0 me-2 text-white").Title(i18n.T(s.Lang, "Star region")).
+					Aria("label", i18n.T(s.Lang, "Star region")+" "+status.Name).Aria("pressed", s.Preferences.isStarred(status.Region)).Text(starLabel).
+					OnClick(func(ctx app.Context, _ app.Event) {
+						s.Preferences = s.Preferences.toggleStarred(status.Region)
+						setRegionPreferences(ctx, s.Preferences)
+					}),
+				app.Button().Class("btn btn-sm btn-link p-0 text-white").Title(hideTitle).Aria("label", hideTitle+" "+status.Name).Text(hideLabel).
+					OnClick(func(ctx app.Context, _ app.Event) {
+						s.Preferences = s.Preferences.toggleHidden(status.Region)
+						setRegionPreferences(ctx, s.Preferences)
+					}),
+			),
+		),
+		app.If(status.Message != "", func() app.UI {
+			return app.Small().Class("d-block").Text(status.Message)
+		}),
+		app.If(!status.Since.IsZero(), func() app.UI {
+			return app.Small().Class("d-block").Text(i18n.T(s.Lang, "Since %s", i18n.FormatDateTime(s.Lang, status.Since.Local())))
+		}),
+		app.Range(status.Conflicts).Slice(func(i int) app.UI {
+			conflict := status.Conflicts[i]
+			return app.Small().Class("d-block").Body(
+				app.Span().Class("badge bg-dark me-1").Text(i18n.T(s.Lang, "Disagreement")),
+				app.Text(i18n.T(s.Lang, "%s reports %s", conflict.Source, i18n.T(s.Lang, conflict.State.String()))),
+			)
+		}),
+	)
+}
 
-	// Cache the result
-	ctx.SetState("serverStatusResponse", serverStatus).Persist().ExpiresIn(serverStatusCacheDuration) // TODO - ExpiresAt is always 0001-01-01T00:00:00Z inside local storage!?
+// Regions returns the configured regions, read from constant.RegionsEnv.
+func Regions() []esostatus.Region {
+	regions, err := esostatus.ParseRegions(app.Getenv(constant.RegionsEnv))
+	if err != nil {
+		log.Println("Error reading regions, using the default ones:", err)
+		return esostatus.DefaultRegions
+	}
+	return regions
+}
+
+// complete reports whether every configured region has a status.
+func (s ServerStatusResponse) complete() bool {
+	for _, region := range Regions() {
+		if s.Region(region.ID).Source == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// FetchServerStatus loads and parses the status of the configured regions
+// without touching any component state, so it can be shared by the client and
+// the server API.
+func FetchServerStatus(ctx context.Context) (ServerStatusResponse, error) {
+	providers, err := esostatus.ParseProviders(app.Getenv(constant.StatusProvidersEnv))
+	if err != nil {
+		return ServerStatusResponse{}, err
+	}
+
+	report, err := esostatus.Chain{Providers: providers}.Fetch(ctx, Regions())
+	if err != nil {
+		return ServerStatusResponse{}, err
+	}
 
-	return statusDiv
+	return ServerStatusResponse{Report: report}, nil
 }
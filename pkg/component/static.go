@@ -0,0 +1,116 @@
+package component
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// StaticDataFile is the name of the data snapshot in a static export.
+const StaticDataFile = "data.json"
+
+// StaticSnapshot is the data baked into a static export, by the state keys
+// the components keep their snapshots under.
+type StaticSnapshot struct {
+	Taken time.Time                  `json:"taken"`
+	Data  map[string]json.RawMessage `json:"data"`
+}
+
+// staticData is the snapshot read by the app of a static export, loaded once.
+var staticData *StaticSnapshot
+
+// Static reports whether the app is a static export reading its data from
+// the snapshot at constant.StaticDataEnv.
+func Static() bool {
+	return app.Getenv(constant.StaticDataEnv) != ""
+}
+
+// CollectStaticSnapshot fetches the data of every component for a static
+// export. Failing sources are logged and left out, their cards show an error.
+func CollectStaticSnapshot(ctx context.Context) StaticSnapshot {
+	snapshot := StaticSnapshot{Taken: time.Now(), Data: map[string]json.RawMessage{}}
+	add := func(key string, value any, err error) {
+		if err != nil {
+			log.Printf("Error collecting %s: %v", key, err)
+			return
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			log.Printf("Error encoding %s: %v", key, err)
+			return
+		}
+		snapshot.Data[key] = data
+	}
+
+	serverStatus, err := FetchServerStatus(ctx)
+	add("serverStatusReport", serverStatus, err)
+	for _, newsLang := range NewsLanguages {
+		feed, err := FetchLocalizedRSSFeed(ctx, newsLang)
+		add(rssFeedState(newsLang), feed, err)
+	}
+	for _, game := range Games() {
+		current, err := FetchCurrentPlayers(ctx, game.AppID)
+		add(game.stateKey("currentPlayerCount"), current, err)
+		counts, err := FetchPlayerCount(ctx, game.AppID)
+		add(game.stateKey("playerCount"), counts, err)
+	}
+	windows, err := FetchMaintenance(ctx)
+	add("maintenanceWindows", windows, err)
+	if estimates := FetchPopulation(ctx, PopulationProviders()); len(estimates) > 0 {
+		add("platformPopulation", estimates, nil)
+	}
+	return snapshot
+}
+
+// staticValue returns the value of key from the snapshot of a static export
+// and when it was taken.
+func staticValue[T any](ctx context.Context, key string) (T, time.Time, error) {
+	var value T
+	if staticData == nil {
+		snapshot, err := fetchStaticSnapshot(ctx)
+		if err != nil {
+			return value, time.Time{}, err
+		}
+		staticData = &snapshot
+	}
+
+	data, ok := staticData.Data[key]
+	if !ok {
+		return value, time.Time{}, fmt.Errorf("%s missing from the static data", key)
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, time.Time{}, fmt.Errorf("decoding static %s: %w", key, err)
+	}
+	return value, staticData.Taken, nil
+}
+
+// fetchStaticSnapshot downloads the snapshot next to the static export.
+func fetchStaticSnapshot(ctx context.Context) (StaticSnapshot, error) {
+	endpoint := app.Window().URL().ResolveReference(&url.URL{Path: app.Getenv(constant.StaticDataEnv)})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return StaticSnapshot{}, fmt.Errorf("creating request: %w", err)
+	}
+	client := &http.Client{Timeout: constant.FetchTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return StaticSnapshot{}, fmt.Errorf("fetching static data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return StaticSnapshot{}, fmt.Errorf("fetching static data: status %d", resp.StatusCode)
+	}
+	var snapshot StaticSnapshot
+	if err = json.NewDecoder(resp.Body).Decode(&snapshot); err != nil {
+		return StaticSnapshot{}, fmt.Errorf("decoding static data: %w", err)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,101 @@
+package component
+
+import (
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// ThemeState is the state holding the ID of the theme picked by the user. It
+// is kept in local storage; without it the theme follows the browser.
+const ThemeState = "theme"
+
+// Theme is a look of the dashboard, applied through the data-theme attribute
+// that eso-dashboard.css styles.
+type Theme struct {
+	ID   string
+	Name string
+	// Dark tells Bootstrap to use its dark color mode.
+	Dark bool
+}
+
+// Themes are the built-in themes, the first one is the default.
+var Themes = []Theme{
+	{ID: "dark", Name: "Dark", Dark: true},
+	{ID: "light", Name: "Light"},
+	{ID: "high-contrast", Name: "High contrast", Dark: true},
+	{ID: "aldmeri", Name: "Aldmeri Dominion", Dark: true},
+	{ID: "daggerfall", Name: "Daggerfall Covenant", Dark: true},
+	{ID: "ebonheart", Name: "Ebonheart Pact", Dark: true},
+}
+
+// LookupTheme returns the theme with the ID, the default theme if there is none.
+func LookupTheme(id string) Theme {
+	for _, theme := range Themes {
+		if theme.ID == id {
+			return theme
+		}
+	}
+	return Themes[0]
+}
+
+// PreferredTheme returns the theme matching the prefers-contrast and
+// prefers-color-scheme media queries of the browser.
+func PreferredTheme() Theme {
+	if !app.IsClient {
+		return Themes[0]
+	}
+
+	matches := func(query string) bool {
+		return app.Window().Call("matchMedia", query).Get("matches").Bool()
+	}
+	switch {
+	case matches("(prefers-contrast: more)"):
+		return LookupTheme("high-contrast")
+	case matches("(prefers-color-scheme: light)"):
+		return LookupTheme("light")
+	default:
+		return Themes[0]
+	}
+}
+
+// ObserveTheme keeps *id in sync with the theme picked by the user, or sets
+// it to the preferred theme when none was picked.
+func ObserveTheme(ctx app.Context, id *string) {
+	ctx.ObserveState(ThemeState, id)
+	if *id == "" {
+		*id = PreferredTheme().ID
+	}
+}
+
+// ThemeSwitcher is a component that lets users pick a theme.
+type ThemeSwitcher struct {
+	app.Compo
+	Theme string
+	// Lang is the language of the theme names.
+	Lang string
+}
+
+// OnMount reads the current theme.
+func (t *ThemeSwitcher) OnMount(ctx app.Context) {
+	ObserveTheme(ctx, &t.Theme)
+	ObserveLanguage(ctx, &t.Lang, nil)
+}
+
+// OnPreRender renders the ThemeSwitcher in the language of the page on the server.
+func (t *ThemeSwitcher) OnPreRender(ctx app.Context) {
+	t.Lang = DefaultLanguage(ctx)
+}
+
+// Render is the main function that renders the ThemeSwitcher component.
+func (t *ThemeSwitcher) Render() app.UI {
+	return app.Select().Class("form-select form-select-sm w-auto").Title(i18n.T(t.Lang, "Theme")).
+		OnChange(func(ctx app.Context, _ app.Event) {
+			ctx.SetState(ThemeState, ctx.JSSrc().Get("value").String()).Persist().Broadcast()
+		}).
+		Body(
+			app.Range(Themes).Slice(func(i int) app.UI {
+				theme := Themes[i]
+				return app.Option().Value(theme.ID).Text(i18n.T(t.Lang, theme.Name)).Selected(theme.ID == LookupTheme(t.Theme).ID)
+			}),
+		)
+}
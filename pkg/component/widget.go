@@ -0,0 +1,116 @@
+package component
+
+import (
+	"log"
+	"strings"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// Widget describes a card the dashboard can show. The built-in widgets are
+// defined next to their component and listed in widgets; forks add theirs with
+// RegisterWidget before the app starts.
+type Widget struct {
+	// ID identifies the widget in constant.WidgetsEnv and saved layouts.
+	ID    string
+	Title string
+	// HeaderClass colours the card header, e.g. "bg-primary".
+	HeaderClass string
+	CardClass   string
+	BodyID      string
+	BodyClass   string
+	// Width is the default number of Bootstrap grid columns the card spans.
+	Width int
+	// Live makes the body a polite ARIA live region, for bodies whose counts
+	// or states update in place.
+	Live bool
+	// New returns the component shown in the card body. It is called once per
+	// dashboard, so the component keeps its state across renders.
+	New func() app.Composer
+	// Wrap puts markup around the component on every render, if set.
+	Wrap func(body app.UI) app.UI
+	// Available reports whether the widget applies to the configuration,
+	// always if nil.
+	Available func() bool
+}
+
+// DefaultWidgets are the IDs of the widgets shown when constant.WidgetsEnv is
+// unset, in their default order.
+var DefaultWidgets = []string{
+	"serverStatus",
+	"news",
+	"activeUsers",
+	"peak",
+	"allTimePeak",
+	"maintenance",
+	"notifications",
+	"population",
+	"gameComparison",
+}
+
+// Body renders the component returned by New with the markup of Wrap.
+func (w Widget) Body(compo app.Composer) app.UI {
+	if w.Wrap == nil {
+		return compo
+	}
+	return w.Wrap(compo)
+}
+
+// widgets are the registered widgets by ID.
+var widgets = map[string]Widget{
+	serverStatusWidget.ID:   serverStatusWidget,
+	newsWidget.ID:           newsWidget,
+	activeUsersWidget.ID:    activeUsersWidget,
+	peakWidget.ID:           peakWidget,
+	allTimePeakWidget.ID:    allTimePeakWidget,
+	maintenanceWidget.ID:    maintenanceWidget,
+	notificationsWidget.ID:  notificationsWidget,
+	populationWidget.ID:     populationWidget,
+	gameComparisonWidget.ID: gameComparisonWidget,
+}
+
+// RegisterWidget makes a widget available to the dashboard. It panics if the
+// ID is empty or already registered, as both are programming errors.
+func RegisterWidget(widget Widget) {
+	if widget.ID == "" || widget.New == nil {
+		panic("component: widget needs an ID and a constructor")
+	}
+	if _, ok := widgets[widget.ID]; ok {
+		panic("component: widget " + widget.ID + " registered twice")
+	}
+	widgets[widget.ID] = widget
+}
+
+// LookupWidget returns the registered widget with the ID.
+func LookupWidget(id string) (Widget, bool) {
+	widget, ok := widgets[id]
+	return widget, ok
+}
+
+// ConfiguredWidgets returns the available widgets listed in
+// constant.WidgetsEnv, comma separated, or DefaultWidgets when unset.
+// Unknown IDs are logged and skipped.
+func ConfiguredWidgets() []Widget {
+	ids := DefaultWidgets
+	if env := app.Getenv(constant.WidgetsEnv); env != "" {
+		ids = strings.Split(env, ",")
+	}
+
+	configured := make([]Widget, 0, len(ids))
+	seen := map[string]bool{}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		widget, ok := widgets[id]
+		switch {
+		case !ok:
+			log.Println("Unknown widget:", id)
+			continue
+		case seen[id], widget.Available != nil && !widget.Available():
+			continue
+		}
+		seen[id] = true
+		configured = append(configured, widget)
+	}
+	return configured
+}
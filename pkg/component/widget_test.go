@@ -0,0 +1,85 @@
+package component_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+func TestDefaultWidgetsAreRegistered(t *testing.T) {
+	for _, id := range component.DefaultWidgets {
+		widget, ok := component.LookupWidget(id)
+		if !ok {
+			t.Errorf("default widget %s isn't registered", id)
+			continue
+		}
+		if widget.ID != id || widget.New == nil || widget.Title == "" {
+			t.Errorf("widget %s = %+v, want its ID, a title and a constructor", id, widget)
+		}
+	}
+}
+
+func TestConfiguredWidgets(t *testing.T) {
+	t.Setenv(constant.GamesEnv, "")
+	t.Setenv(constant.PopulationEnv, "")
+
+	t.Setenv(constant.WidgetsEnv, "")
+	var ids []string
+	for _, widget := range component.ConfiguredWidgets() {
+		ids = append(ids, widget.ID)
+	}
+	// Population and game comparison need more configuration.
+	want := "serverStatus news activeUsers peak allTimePeak maintenance notifications"
+	if got := strings.Join(ids, " "); got != want {
+		t.Errorf("ConfiguredWidgets() = %s, want %s", got, want)
+	}
+
+	t.Setenv(constant.WidgetsEnv, " news, unknown,serverStatus,news,gameComparison")
+	ids = nil
+	for _, widget := range component.ConfiguredWidgets() {
+		ids = append(ids, widget.ID)
+	}
+	if got := strings.Join(ids, " "); got != "news serverStatus" {
+		t.Errorf("ConfiguredWidgets() = %s, want news serverStatus", got)
+	}
+}
+
+func TestRegisterWidget(t *testing.T) {
+	component.RestoreWidgets(t)
+	component.RegisterWidget(component.Widget{
+		ID:    "forkedWidget",
+		Title: "Forked",
+		New:   func() app.Composer { return &component.ServerStatus{} },
+	})
+	if _, ok := component.LookupWidget("forkedWidget"); !ok {
+		t.Error("registered widget not found")
+	}
+
+	for name, widget := range map[string]component.Widget{
+		"duplicate":      {ID: "serverStatus", New: func() app.Composer { return &component.ServerStatus{} }},
+		"no ID":          {New: func() app.Composer { return &component.ServerStatus{} }},
+		"no constructor": {ID: "broken"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("RegisterWidget() didn't panic")
+				}
+			}()
+			component.RegisterWidget(widget)
+		})
+	}
+}
+
+func TestRestoreWidgets(t *testing.T) {
+	t.Run("register", func(t *testing.T) {
+		component.RestoreWidgets(t)
+		component.RegisterWidget(component.Widget{ID: "forkedWidget", New: func() app.Composer { return &component.ServerStatus{} }})
+	})
+	if _, ok := component.LookupWidget("forkedWidget"); ok {
+		t.Error("widget registered by a test is still registered after it")
+	}
+}
@@ -5,5 +5,96 @@ import "time"
 // fetchTimeout is the duration for which the data is cached.
 const FetchTimeout = 10 * time.Second
 const Unreachable = "Unreachable"
+
 // PlayerCountCacheDuration is the duration for which the data is cached.
-const PlayerCountCacheDuration = 3 * time.Minute
\ No newline at end of file
+const PlayerCountCacheDuration = 3 * time.Minute
+
+// GamesEnv names the environment variable listing the tracked Steam games as
+// "appid:Name" entries separated by semicolons.
+const GamesEnv = "ESO_DASHBOARD_GAMES"
+
+// PopulationEnv names the environment variable holding the JSON list of
+// non-Steam population providers.
+const PopulationEnv = "ESO_DASHBOARD_POPULATION"
+
+// StatusProvidersEnv names the environment variable listing the server status
+// providers in fallback order.
+const StatusProvidersEnv = "ESO_DASHBOARD_STATUS_PROVIDERS"
+
+// RegionsEnv names the environment variable holding the JSON list of tracked
+// server regions.
+const RegionsEnv = "ESO_DASHBOARD_REGIONS"
+
+// PollInterval is how often the server collects the dashboard data.
+const PollInterval = 3 * time.Minute
+
+// DataDirEnv names the environment variable of the directory the server keeps
+// its files in, DefaultDataDir when unset.
+const DataDirEnv = "ESO_DASHBOARD_DATA_DIR"
+
+// DefaultDataDir is the data directory used when DataDirEnv is unset.
+const DefaultDataDir = "data"
+
+// PushSubjectEnv names the environment variable holding the mailto: or https:
+// contact sent to push services.
+const PushSubjectEnv = "ESO_DASHBOARD_PUSH_SUBJECT"
+
+// PushAllowLocalEnv names the environment variable that, set to true, lets
+// push subscriptions use http and local endpoints such as cmd/push-stub.
+const PushAllowLocalEnv = "ESO_DASHBOARD_PUSH_ALLOW_LOCAL"
+
+// VAPIDPublicKeyEnv is set by the server so the client can subscribe to push messages.
+const VAPIDPublicKeyEnv = "ESO_DASHBOARD_VAPID_PUBLIC_KEY"
+
+// SMTPAddrEnv names the environment variable holding the host:port of the SMTP
+// server digests are sent through. Digests are off when it's unset.
+const SMTPAddrEnv = "ESO_DASHBOARD_SMTP_ADDR"
+
+// SMTPUsernameEnv and SMTPPasswordEnv name the environment variables holding
+// the SMTP credentials, no authentication when unset.
+const (
+	SMTPUsernameEnv = "ESO_DASHBOARD_SMTP_USERNAME"
+	SMTPPasswordEnv = "ESO_DASHBOARD_SMTP_PASSWORD"
+)
+
+// DigestFromEnv and DigestToEnv name the environment variables holding the
+// sender and the comma separated recipients of the digest.
+const (
+	DigestFromEnv = "ESO_DASHBOARD_DIGEST_FROM"
+	DigestToEnv   = "ESO_DASHBOARD_DIGEST_TO"
+)
+
+// DigestScheduleEnv names the environment variable holding when digests are
+// sent, e.g. "daily 08:00" or "weekly monday 08:00".
+const DigestScheduleEnv = "ESO_DASHBOARD_DIGEST_SCHEDULE"
+
+// DigestTextTemplateEnv and DigestHTMLTemplateEnv name the environment
+// variables holding the paths of custom digest templates.
+const (
+	DigestTextTemplateEnv = "ESO_DASHBOARD_DIGEST_TEXT_TEMPLATE"
+	DigestHTMLTemplateEnv = "ESO_DASHBOARD_DIGEST_HTML_TEMPLATE"
+)
+
+// WidgetsEnv names the environment variable listing the IDs of the dashboard
+// widgets, comma separated, in their default order.
+const WidgetsEnv = "ESO_DASHBOARD_WIDGETS"
+
+// ImageHostsEnv names the environment variable listing the hosts the image
+// proxy may fetch from, comma separated. Subdomains are included.
+const ImageHostsEnv = "ESO_DASHBOARD_IMAGE_HOSTS"
+
+// ThumbnailWidth is the width news thumbnails are served at, wide enough for
+// the news card on high density screens.
+const ThumbnailWidth = 640
+
+// LiteThumbnailWidth is the width news thumbnails are served at in lite mode.
+const LiteThumbnailWidth = 320
+
+// ImageCacheSizeEnv names the environment variable holding the size limit of
+// the image cache in megabytes.
+const ImageCacheSizeEnv = "ESO_DASHBOARD_IMAGE_CACHE_MB"
+
+// StaticDataEnv names the environment variable holding the path of the data
+// snapshot a static export reads instead of fetching. It is set by the
+// generate command, the app fetches its data itself when it's unset.
+const StaticDataEnv = "ESO_DASHBOARD_STATIC_DATA"
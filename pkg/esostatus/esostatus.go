@@ -0,0 +1,313 @@
+// Package esostatus parses the esoserverstatus.net status page: the state of
+// every region, maintenance announcements and when the page was last updated.
+package esostatus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Source is the name reported as the origin of parsed statuses.
+const Source = "esoserverstatus.net"
+
+// ErrLayoutChanged is returned when the page no longer has the expected structure.
+var ErrLayoutChanged = errors.New("esoserverstatus page layout changed")
+
+// State is the state a region is in.
+type State string
+
+const (
+	StateOnline      State = "online"
+	StateOffline     State = "offline"
+	StateMaintenance State = "maintenance"
+	StateUnknown     State = "unknown"
+)
+
+// String returns the state as displayed, e.g. "Online".
+func (s State) String() string {
+	if s == "" {
+		return "Unknown"
+	}
+	return strings.ToUpper(string(s[:1])) + string(s[1:])
+}
+
+// RegionStatus is the status of one region.
+type RegionStatus struct {
+	// Region is the ID of the region.
+	Region      string      `json:"region"`
+	Name        string      `json:"name"`
+	Platform    string      `json:"platform"`
+	Environment Environment `json:"environment"`
+	State       State       `json:"state"`
+	// Message is any extra text shown for the region, e.g. "Maintenance in progress".
+	Message string `json:"message,omitempty"`
+	// Since is when the region entered its state, zero if the page doesn't say.
+	Since  time.Time `json:"since"`
+	Source string    `json:"source"`
+	// Conflicts lists other providers that report a different state.
+	Conflicts []Conflict `json:"conflicts,omitempty"`
+}
+
+// Conflict is the differing state another provider reports for a region.
+type Conflict struct {
+	Source string `json:"source"`
+	State  State  `json:"state"`
+}
+
+// Announcement is a maintenance notice shown on the status page.
+type Announcement struct {
+	Message string `json:"message"`
+	// Start and End bound the announced downtime, zero when not given.
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Report is everything parsed from the status page.
+type Report struct {
+	Regions       []RegionStatus `json:"regions"`
+	Announcements []Announcement `json:"announcements"`
+	// UpdatedAt is when the status page was last updated, zero if unknown.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Region returns the status of a region by ID, StateUnknown if it isn't in the report.
+func (r Report) Region(id string) RegionStatus {
+	for _, status := range r.Regions {
+		if status.Region == id {
+			return status
+		}
+	}
+	return RegionStatus{Region: id, Name: id, State: StateUnknown}
+}
+
+// announcementSelector matches the blocks the page uses for notices.
+const announcementSelector = ".alert, .announcement, .maintenance, #maintenance, .notice"
+
+// updatedSelector matches the element holding the last update time.
+const updatedSelector = "#last-updated, .last-updated, .updated"
+
+// blockSelector matches the elements a notice missed by announcementSelector
+// would be written in.
+const blockSelector = "div, p, li, td, section, article, aside, header, footer"
+
+// timeLayouts are the layouts tried for timestamps written as text.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04 MST",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"January 2, 2006 15:04 MST",
+	"Jan 2, 2006 15:04 MST",
+}
+
+// timestampPattern finds timestamps in free text for the layouts above.
+var timestampPattern = regexp.MustCompile(
+	`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}(?::\d{2})?(?:Z|[+-]\d{2}:\d{2}| [A-Z]{2,4})?` +
+		`|[A-Z][a-z]+ \d{1,2}, \d{4} \d{2}:\d{2} [A-Z]{2,4}`,
+)
+
+// Fetch downloads the status page at url and parses the given regions.
+func Fetch(ctx context.Context, url string, regions []Region) (Report, error) {
+	client := &http.Client{Timeout: constant.FetchTimeout}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Report{}, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Report{}, fmt.Errorf("fetching server status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Report{}, fmt.Errorf("fetching server status: unexpected status %s", resp.Status)
+	}
+
+	return Parse(resp.Body, regions)
+}
+
+// Parse extracts the status of the given regions, the maintenance
+// announcements and the last update time from the status page. The page
+// always shows when it was updated, so a page without an update time, or
+// with a dated maintenance notice the announcement selectors don't match,
+// is reported as ErrLayoutChanged instead of being read as having none.
+func Parse(r io.Reader, regions []Region) (Report, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return Report{}, fmt.Errorf("parsing server status: %w", err)
+	}
+
+	report := Report{}
+	found := 0
+	for _, region := range regions {
+		selection := doc.Find(region.selector()).First()
+		if selection.Length() == 0 {
+			report.Regions = append(report.Regions, region.unknownStatus(Source))
+			continue
+		}
+		found++
+		report.Regions = append(report.Regions, parseRegion(region, selection))
+	}
+	if found == 0 && len(regions) > 0 {
+		return Report{}, fmt.Errorf("%w: no region found", ErrLayoutChanged)
+	}
+
+	doc.Find(announcementSelector).Each(func(_ int, s *goquery.Selection) {
+		if announcement, ok := parseAnnouncement(s); ok {
+			report.Announcements = append(report.Announcements, announcement)
+		}
+	})
+
+	if len(report.Announcements) == 0 && missedNotice(doc, regions) {
+		return Report{}, fmt.Errorf("%w: maintenance notice outside the announcements", ErrLayoutChanged)
+	}
+
+	report.UpdatedAt = parseUpdated(doc)
+	if report.UpdatedAt.IsZero() {
+		return Report{}, fmt.Errorf("%w: no last update time", ErrLayoutChanged)
+	}
+
+	return report, nil
+}
+
+// missedNotice reports whether a block of the page that isn't a region, a
+// notice or the update time mentions maintenance with a date, i.e. a notice
+// announcementSelector no longer matches.
+func missedNotice(doc *goquery.Document, regions []Region) bool {
+	page := doc.Find("body").Clone()
+	page.Find(announcementSelector + ", " + updatedSelector + ", script, style").Remove()
+	for _, region := range regions {
+		page.Find(region.selector()).Remove()
+	}
+
+	found := false
+	page.Find(blockSelector).EachWithBreak(func(_ int, block *goquery.Selection) bool {
+		if block.Find(blockSelector).Length() > 0 {
+			return true
+		}
+		text := block.Text()
+		found = strings.Contains(strings.ToLower(text), "maintenance") && len(ParseTimes(text)) > 0
+		return !found
+	})
+	return found
+}
+
+// parseRegion reads the state from the bold text of a region element and
+// treats the remaining text as its message.
+func parseRegion(region Region, s *goquery.Selection) RegionStatus {
+	status := region.unknownStatus(Source)
+
+	status.State = ParseState(strings.TrimSpace(s.Find("b").First().Text()))
+
+	// Whatever is left once the state and timestamps are removed is the message.
+	rest := s.Clone()
+	rest.Find("b, time").Remove()
+	message := strings.Join(strings.Fields(rest.Text()), " ")
+	message = strings.TrimPrefix(message, region.ID)
+	status.Message = strings.Trim(message, " :-")
+
+	status.Since = findTime(s)
+
+	return status
+}
+
+// ParseState maps the text shown for a region to a State.
+func ParseState(text string) State {
+	text = strings.ToLower(text)
+	switch {
+	case strings.Contains(text, "maintenance"):
+		return StateMaintenance
+	case strings.Contains(text, "online"), text == "up":
+		return StateOnline
+	case strings.Contains(text, "offline"), text == "down":
+		return StateOffline
+	default:
+		return StateUnknown
+	}
+}
+
+// parseAnnouncement reads a notice. Only maintenance related notices are kept.
+func parseAnnouncement(s *goquery.Selection) (Announcement, bool) {
+	message := strings.Join(strings.Fields(s.Text()), " ")
+	if message == "" || !strings.Contains(strings.ToLower(message), "maintenance") {
+		return Announcement{}, false
+	}
+
+	announcement := Announcement{Message: message}
+	times := findTimes(s)
+	if len(times) > 0 {
+		announcement.Start = times[0]
+	}
+	if len(times) > 1 {
+		announcement.End = times[1]
+	}
+	return announcement, true
+}
+
+// parseUpdated returns the last update time of the page.
+func parseUpdated(doc *goquery.Document) time.Time {
+	if updated := doc.Find(updatedSelector).First(); updated.Length() > 0 {
+		return findTime(updated)
+	}
+
+	// Fall back to a "Last updated: <time>" sentence anywhere on the page.
+	text := doc.Text()
+	if i := strings.Index(strings.ToLower(text), "last updated"); i >= 0 {
+		if times := ParseTimes(text[i:]); len(times) > 0 {
+			return times[0]
+		}
+	}
+	return time.Time{}
+}
+
+// findTime returns the first timestamp inside s, zero if there is none.
+func findTime(s *goquery.Selection) time.Time {
+	if times := findTimes(s); len(times) > 0 {
+		return times[0]
+	}
+	return time.Time{}
+}
+
+// findTimes returns the timestamps inside s, preferring machine readable
+// <time datetime> elements over text.
+func findTimes(s *goquery.Selection) []time.Time {
+	var times []time.Time
+	s.Find("time[datetime]").AddSelection(s.Filter("[data-since]")).Each(func(_ int, t *goquery.Selection) {
+		value, ok := t.Attr("datetime")
+		if !ok {
+			value, _ = t.Attr("data-since")
+		}
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			times = append(times, parsed)
+		}
+	})
+	if len(times) > 0 {
+		return times
+	}
+	return ParseTimes(s.Text())
+}
+
+// ParseTimes returns the timestamps written in text, in order of appearance.
+func ParseTimes(text string) []time.Time {
+	var times []time.Time
+	for _, match := range timestampPattern.FindAllString(text, -1) {
+		for _, layout := range timeLayouts {
+			if parsed, err := time.Parse(layout, match); err == nil {
+				times = append(times, parsed)
+				break
+			}
+		}
+	}
+	return times
+}
@@ -0,0 +1,195 @@
+package esostatus_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+)
+
+// at returns a time on the day the fixtures are set on, in UTC.
+func at(day, hour, minute int) time.Time {
+	return time.Date(2025, time.October, day, hour, minute, 0, 0, time.UTC)
+}
+
+// parseFixture parses a status page of testdata for the default regions. The
+// pages are synthetic, written to the markup the parser expects.
+func parseFixture(t *testing.T, name string) (esostatus.Report, error) {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	return esostatus.Parse(f, esostatus.DefaultRegions)
+}
+
+// regionWant is what a fixture shows for a region.
+type regionWant struct {
+	state   esostatus.State
+	message string
+	since   time.Time
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		fixture       string
+		regions       map[string]regionWant
+		announcements []esostatus.Announcement
+		updated       time.Time
+	}{
+		{
+			fixture: "online.html",
+			regions: map[string]regionWant{
+				"PC-EU":  {state: esostatus.StateOnline},
+				"PC-NA":  {state: esostatus.StateOnline},
+				"PC-PTS": {state: esostatus.StateOnline},
+				"PS4-EU": {state: esostatus.StateOnline},
+			},
+			updated: at(19, 11, 58),
+		},
+		{
+			// Notices without "maintenance" are left out, the update time
+			// comes from a "Last updated" sentence.
+			fixture: "offline.html",
+			regions: map[string]regionWant{
+				"PC-EU":  {state: esostatus.StateOffline, message: "Login servers unreachable", since: at(19, 10, 15)},
+				"PC-NA":  {state: esostatus.StateOffline, since: at(19, 10, 20)},
+				"PC-PTS": {state: esostatus.StateOnline},
+				"PS4-EU": {state: esostatus.StateUnknown},
+			},
+			updated: at(19, 11, 58),
+		},
+		{
+			fixture: "maintenance.html",
+			regions: map[string]regionWant{
+				"PC-EU":   {state: esostatus.StateMaintenance, message: "Maintenance in progress", since: at(19, 8, 0)},
+				"PC-NA":   {state: esostatus.StateMaintenance},
+				"PC-PTS":  {state: esostatus.StateOffline},
+				"XBOX-EU": {state: esostatus.StateOnline},
+			},
+			announcements: []esostatus.Announcement{
+				{
+					Message: "Scheduled maintenance The PC/Mac megaservers will be unavailable from " +
+						"2025-10-20 08:00 UTC to 2025-10-20 12:00 UTC.",
+					Start: at(20, 8, 0),
+					End:   at(20, 12, 0),
+				},
+				{
+					Message: "Console maintenance: Oct 21, 09:00 until 14:00",
+					Start:   at(21, 9, 0),
+					End:     at(21, 14, 0),
+				},
+			},
+			updated: at(19, 11, 58),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			report, err := parseFixture(t, tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(report.Regions) != len(esostatus.DefaultRegions) {
+				t.Fatalf("Parse() returned %d regions, want %d", len(report.Regions), len(esostatus.DefaultRegions))
+			}
+
+			for id, want := range tt.regions {
+				got := report.Region(id)
+				if got.State != want.state || got.Message != want.message || !got.Since.Equal(want.since) {
+					t.Errorf("%s = %s %q since %v, want %s %q since %v",
+						id, got.State, got.Message, got.Since, want.state, want.message, want.since)
+				}
+				if got.Source != esostatus.Source {
+					t.Errorf("%s source = %q, want %q", id, got.Source, esostatus.Source)
+				}
+			}
+			if !reflect.DeepEqual(report.Announcements, tt.announcements) {
+				t.Errorf("announcements = %+v\nwant %+v", report.Announcements, tt.announcements)
+			}
+			if !report.UpdatedAt.Equal(tt.updated) {
+				t.Errorf("updated at %v, want %v", report.UpdatedAt, tt.updated)
+			}
+		})
+	}
+}
+
+func TestParseLayoutChanged(t *testing.T) {
+	if _, err := parseFixture(t, "layout-changed.html"); !errors.Is(err, esostatus.ErrLayoutChanged) {
+		t.Errorf("Parse() error = %v, want ErrLayoutChanged", err)
+	}
+}
+
+func TestParseFailsLoudly(t *testing.T) {
+	const regions = `<div class="server" id="PC-EU">PC-EU: <b>Online</b></div>`
+	tests := map[string]string{
+		"no update time": regions,
+		"notice in unknown markup": regions +
+			`<p class="banner">Maintenance of the PC megaservers on 2025-10-20 08:00 UTC</p>` +
+			`<span id="last-updated">2025-10-19T11:58:00Z</span>`,
+	}
+	for name, page := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := esostatus.Parse(strings.NewReader(page), esostatus.DefaultRegions); !errors.Is(err, esostatus.ErrLayoutChanged) {
+				t.Errorf("Parse() error = %v, want ErrLayoutChanged", err)
+			}
+		})
+	}
+
+	// Mentions of maintenance without a date, such as a link, aren't notices.
+	page := `<div><a href="/maintenance">Maintenance schedule</a></div>` + regions +
+		`<p>Last updated 2025-10-19T11:58:00Z</p>`
+	if _, err := esostatus.Parse(strings.NewReader(page), esostatus.DefaultRegions); err != nil {
+		t.Errorf("Parse() error = %v, want the page read", err)
+	}
+}
+
+func TestParseState(t *testing.T) {
+	tests := map[string]esostatus.State{
+		"Online":                  esostatus.StateOnline,
+		"UP":                      esostatus.StateOnline,
+		"Offline":                 esostatus.StateOffline,
+		"down":                    esostatus.StateOffline,
+		"Maintenance":             esostatus.StateMaintenance,
+		"Online (maintenance)":    esostatus.StateMaintenance,
+		"":                        esostatus.StateUnknown,
+		"Experiencing difficulty": esostatus.StateUnknown,
+	}
+	for text, want := range tests {
+		if got := esostatus.ParseState(text); got != want {
+			t.Errorf("ParseState(%q) = %s, want %s", text, got, want)
+		}
+	}
+}
+
+func TestParseTimes(t *testing.T) {
+	tests := []struct {
+		text string
+		want []time.Time
+	}{
+		{text: "no time here"},
+		{text: "at 2025-10-19T08:00:00Z", want: []time.Time{at(19, 8, 0)}},
+		{text: "from 2025-10-19 08:00 UTC to 2025-10-19 12:30 UTC", want: []time.Time{at(19, 8, 0), at(19, 12, 30)}},
+		{text: "2025-10-19 08:00:30 UTC", want: []time.Time{at(19, 8, 0).Add(30 * time.Second)}},
+		{text: "since 2025-10-19T08:00", want: []time.Time{at(19, 8, 0)}},
+		{text: "October 19, 2025 08:00 UTC", want: []time.Time{at(19, 8, 0)}},
+	}
+	for _, tt := range tests {
+		got := esostatus.ParseTimes(tt.text)
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseTimes(%q) = %v, want %v", tt.text, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(tt.want[i]) {
+				t.Errorf("ParseTimes(%q)[%d] = %v, want %v", tt.text, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
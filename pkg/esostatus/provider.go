@@ -0,0 +1,223 @@
+package esostatus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+)
+
+// Provider kinds accepted by ParseProviders.
+const (
+	KindStatusPage   = "esoserverstatus"
+	KindLiveServices = "liveservices"
+)
+
+// Default upstream URLs, requested through a CORS proxy so they work in the browser.
+const (
+	// TODO - Add origins API to avoid CORS issues
+	DefaultStatusPageURL   = "https://api.allorigins.win/raw?url=https://esoserverstatus.net/"
+	DefaultLiveServicesURL = "https://api.allorigins.win/raw?url=https://live-services.elderscrollsonline.com/status/realms"
+)
+
+// LiveServicesSource is the name reported for statuses from the official endpoint.
+const LiveServicesSource = "live-services.elderscrollsonline.com"
+
+// ErrNoProvider is returned by a Chain without providers.
+var ErrNoProvider = errors.New("no status provider configured")
+
+// Provider supplies the status of the given regions.
+type Provider interface {
+	Name() string
+	Fetch(ctx context.Context, regions []Region) (Report, error)
+}
+
+// StatusPageProvider reads the esoserverstatus.net page.
+type StatusPageProvider struct {
+	URL string
+}
+
+// Name returns the source name of the provider.
+func (StatusPageProvider) Name() string {
+	return Source
+}
+
+// Fetch downloads and parses the status page.
+func (p StatusPageProvider) Fetch(ctx context.Context, regions []Region) (Report, error) {
+	return Fetch(ctx, p.URL, regions)
+}
+
+// LiveServicesProvider reads the official ZeniMax live services realm status.
+type LiveServicesProvider struct {
+	URL string
+}
+
+// Name returns the source name of the provider.
+func (LiveServicesProvider) Name() string {
+	return LiveServicesSource
+}
+
+// Fetch requests the realm status JSON and maps realms to regions.
+func (p LiveServicesProvider) Fetch(ctx context.Context, regions []Region) (Report, error) {
+	client := &http.Client{Timeout: constant.FetchTimeout}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL, nil)
+	if err != nil {
+		return Report{}, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Report{}, fmt.Errorf("fetching live services status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Report{}, fmt.Errorf("fetching live services status: unexpected status %s", resp.Status)
+	}
+
+	var data struct {
+		Platform struct {
+			Response map[string]string `json:"response"`
+		} `json:"zos_platform_response"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return Report{}, fmt.Errorf("decoding live services status: %w", err)
+	}
+	if len(data.Platform.Response) == 0 {
+		return Report{}, fmt.Errorf("%w: live services response has no realms", ErrLayoutChanged)
+	}
+
+	report := Report{}
+	for _, region := range regions {
+		status := region.unknownStatus(LiveServicesSource)
+		if state, ok := data.Platform.Response[region.Realm]; ok && region.Realm != "" {
+			status.State = ParseState(state)
+		}
+		report.Regions = append(report.Regions, status)
+	}
+	return report, nil
+}
+
+// ParseProviders builds the providers from a comma separated list of
+// "kind" or "kind=url" entries, e.g. "esoserverstatus,liveservices=http://localhost:8080".
+// The order of the list is the order of the fallback chain.
+func ParseProviders(config string) ([]Provider, error) {
+	if strings.TrimSpace(config) == "" {
+		return DefaultProviders(), nil
+	}
+
+	var providers []Provider
+	for _, entry := range strings.Split(config, ",") {
+		kind, url, _ := strings.Cut(strings.TrimSpace(entry), "=")
+		switch kind {
+		case KindStatusPage:
+			if url == "" {
+				url = DefaultStatusPageURL
+			}
+			providers = append(providers, StatusPageProvider{URL: url})
+		case KindLiveServices:
+			if url == "" {
+				url = DefaultLiveServicesURL
+			}
+			providers = append(providers, LiveServicesProvider{URL: url})
+		default:
+			return nil, fmt.Errorf("unknown status provider %q", kind)
+		}
+	}
+	return providers, nil
+}
+
+// DefaultProviders returns the status page followed by the official endpoint.
+func DefaultProviders() []Provider {
+	return []Provider{
+		StatusPageProvider{URL: DefaultStatusPageURL},
+		LiveServicesProvider{URL: DefaultLiveServicesURL},
+	}
+}
+
+// Chain asks every provider and uses, per region, the first one in order that
+// knows its state. The answers of the others are compared against it to flag
+// disagreements.
+type Chain struct {
+	Providers []Provider
+}
+
+// Fetch returns the report of the first provider that succeeds. Regions it
+// doesn't know the state of are taken from the next provider that does, and
+// conflicting states of the other providers are recorded per region. The
+// announcements of every provider that answers are merged, so a notice only
+// one source shows isn't lost.
+func (c Chain) Fetch(ctx context.Context, regions []Region) (Report, error) {
+	if len(c.Providers) == 0 {
+		return Report{}, ErrNoProvider
+	}
+
+	reports := make([]Report, len(c.Providers))
+	errs := make([]error, len(c.Providers))
+	var wg sync.WaitGroup
+	for i, provider := range c.Providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reports[i], errs[i] = provider.Fetch(ctx, regions)
+		}()
+	}
+	wg.Wait()
+
+	primary := -1
+	for i, err := range errs {
+		if err == nil {
+			primary = i
+			break
+		}
+	}
+	if primary < 0 {
+		return Report{}, errors.Join(errs...)
+	}
+
+	report := reports[primary]
+	report.Regions = slices.Clone(report.Regions)
+	for i := range report.Regions {
+		status := &report.Regions[i]
+		source := primary
+		for j := primary + 1; j < len(reports) && status.State == StateUnknown; j++ {
+			if other := reports[j].Region(status.Region); errs[j] == nil && other.State != StateUnknown {
+				*status, source = other, j
+			}
+		}
+		for j, other := range reports {
+			if j == source || errs[j] != nil {
+				continue
+			}
+			otherStatus := other.Region(status.Region)
+			if status.State != StateUnknown && otherStatus.State != StateUnknown && otherStatus.State != status.State {
+				status.Conflicts = append(status.Conflicts, Conflict{Source: otherStatus.Source, State: otherStatus.State})
+			}
+		}
+	}
+	for j, other := range reports {
+		if j != primary && errs[j] == nil {
+			report.Announcements = mergeAnnouncements(report.Announcements, other.Announcements)
+		}
+	}
+	return report, nil
+}
+
+// mergeAnnouncements appends the announcements of other that aren't in
+// announcements yet.
+func mergeAnnouncements(announcements, other []Announcement) []Announcement {
+	for _, announcement := range other {
+		if !slices.ContainsFunc(announcements, func(known Announcement) bool {
+			return known.Message == announcement.Message && known.Start.Equal(announcement.Start) && known.End.Equal(announcement.End)
+		}) {
+			announcements = append(announcements, announcement)
+		}
+	}
+	return announcements
+}
@@ -0,0 +1,217 @@
+package esostatus_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+)
+
+// liveServicesUp is a live services answer with every default realm up
+// except the EU PC megaserver.
+const liveServicesUp = `{"zos_platform_response": {"response": {
+	"The Elder Scrolls Online (EU)": "DOWN",
+	"The Elder Scrolls Online (NA)": "UP",
+	"The Elder Scrolls Online (PTS)": "UP",
+	"The Elder Scrolls Online (XBox - EU)": "UP",
+	"The Elder Scrolls Online (XBox - US)": "UP",
+	"The Elder Scrolls Online (PS4 - US)": "UP",
+	"The Elder Scrolls Online (PS4 - EU)": "UP"
+}}}`
+
+// statusPageServer serves a status page of testdata.
+func statusPageServer(t *testing.T, fixture string) esostatus.Provider {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, filepath.Join("testdata", fixture))
+	}))
+	t.Cleanup(server.Close)
+	return esostatus.StatusPageProvider{URL: server.URL}
+}
+
+// liveServicesServer serves a live services answer.
+func liveServicesServer(t *testing.T, body string) esostatus.Provider {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return esostatus.LiveServicesProvider{URL: server.URL}
+}
+
+// failingServer answers every request with an error status.
+func failingServer(t *testing.T, provider func(url string) esostatus.Provider) esostatus.Provider {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "upstream down", http.StatusBadGateway)
+	}))
+	t.Cleanup(server.Close)
+	return provider(server.URL)
+}
+
+// statusPageAt and liveServicesAt create providers for failingServer.
+func statusPageAt(url string) esostatus.Provider   { return esostatus.StatusPageProvider{URL: url} }
+func liveServicesAt(url string) esostatus.Provider { return esostatus.LiveServicesProvider{URL: url} }
+
+func TestChainFallsBack(t *testing.T) {
+	chain := esostatus.Chain{Providers: []esostatus.Provider{
+		failingServer(t, statusPageAt),
+		liveServicesServer(t, liveServicesUp),
+	}}
+
+	report, err := chain.Fetch(context.Background(), esostatus.DefaultRegions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, region := range []struct {
+		id    string
+		state esostatus.State
+	}{{id: "PC-EU", state: esostatus.StateOffline}, {id: "PC-NA", state: esostatus.StateOnline}} {
+		status := report.Region(region.id)
+		if status.State != region.state || status.Source != esostatus.LiveServicesSource {
+			t.Errorf("%s = %s from %s, want %s from %s", region.id, status.State, status.Source, region.state, esostatus.LiveServicesSource)
+		}
+		if len(status.Conflicts) > 0 {
+			t.Errorf("%s has conflicts %v, the failed provider can't disagree", region.id, status.Conflicts)
+		}
+	}
+}
+
+func TestChainRecordsConflicts(t *testing.T) {
+	chain := esostatus.Chain{Providers: []esostatus.Provider{
+		statusPageServer(t, "online.html"),
+		liveServicesServer(t, liveServicesUp),
+	}}
+
+	report, err := chain.Fetch(context.Background(), esostatus.DefaultRegions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range report.Regions {
+		if status.Source != esostatus.Source {
+			t.Errorf("%s is from %s, want the first provider %s", status.Region, status.Source, esostatus.Source)
+		}
+		if status.State != esostatus.StateOnline {
+			t.Errorf("%s = %s, want the state of the first provider", status.Region, status.State)
+		}
+
+		var want []esostatus.Conflict
+		if status.Region == "PC-EU" {
+			want = []esostatus.Conflict{{Source: esostatus.LiveServicesSource, State: esostatus.StateOffline}}
+		}
+		if len(status.Conflicts) != len(want) || (len(want) > 0 && status.Conflicts[0] != want[0]) {
+			t.Errorf("%s conflicts = %v, want %v", status.Region, status.Conflicts, want)
+		}
+	}
+}
+
+func TestChainIgnoresUnknownStates(t *testing.T) {
+	// The live services answer doesn't know the PTS realm, which isn't a
+	// disagreement.
+	chain := esostatus.Chain{Providers: []esostatus.Provider{
+		statusPageServer(t, "maintenance.html"),
+		liveServicesServer(t, `{"zos_platform_response": {"response": {"The Elder Scrolls Online (NA)": "UP"}}}`),
+	}}
+
+	report, err := chain.Fetch(context.Background(), esostatus.DefaultRegions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conflicts := report.Region("PC-PTS").Conflicts; len(conflicts) > 0 {
+		t.Errorf("PC-PTS conflicts = %v, want none", conflicts)
+	}
+	if conflicts := report.Region("PC-NA").Conflicts; len(conflicts) != 1 {
+		t.Errorf("PC-NA conflicts = %v, want the online state of live services", conflicts)
+	}
+}
+
+func TestChainMergesRegions(t *testing.T) {
+	// Live services only knows the NA megaserver, the status page fills in
+	// the other regions.
+	chain := esostatus.Chain{Providers: []esostatus.Provider{
+		liveServicesServer(t, `{"zos_platform_response": {"response": {"The Elder Scrolls Online (NA)": "DOWN"}}}`),
+		failingServer(t, statusPageAt),
+		statusPageServer(t, "online.html"),
+	}}
+
+	report, err := chain.Fetch(context.Background(), esostatus.DefaultRegions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range report.Regions {
+		wantState, wantSource := esostatus.StateOnline, esostatus.Source
+		var wantConflicts []esostatus.Conflict
+		if status.Region == "PC-NA" {
+			wantState, wantSource = esostatus.StateOffline, esostatus.LiveServicesSource
+			wantConflicts = []esostatus.Conflict{{Source: esostatus.Source, State: esostatus.StateOnline}}
+		}
+		if status.State != wantState || status.Source != wantSource {
+			t.Errorf("%s = %s from %s, want %s from %s", status.Region, status.State, status.Source, wantState, wantSource)
+		}
+		if len(status.Conflicts) != len(wantConflicts) || (len(wantConflicts) > 0 && status.Conflicts[0] != wantConflicts[0]) {
+			t.Errorf("%s conflicts = %v, want %v", status.Region, status.Conflicts, wantConflicts)
+		}
+	}
+}
+
+func TestChainMergesAnnouncements(t *testing.T) {
+	chain := esostatus.Chain{Providers: []esostatus.Provider{
+		statusPageServer(t, "online.html"),
+		statusPageServer(t, "maintenance.html"),
+		statusPageServer(t, "maintenance.html"),
+	}}
+
+	report, err := chain.Fetch(context.Background(), esostatus.DefaultRegions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Both announcements of the fallback, once although two providers show them.
+	if len(report.Announcements) != 2 {
+		t.Errorf("announcements = %+v, want the 2 of maintenance.html", report.Announcements)
+	}
+}
+
+func TestChainAllFail(t *testing.T) {
+	chain := esostatus.Chain{Providers: []esostatus.Provider{
+		failingServer(t, statusPageAt),
+		failingServer(t, liveServicesAt),
+		liveServicesServer(t, `{"unexpected": true}`),
+	}}
+
+	_, err := chain.Fetch(context.Background(), esostatus.DefaultRegions)
+	if err == nil {
+		t.Fatal("Fetch() succeeded, want the errors of every provider")
+	}
+	if !errors.Is(err, esostatus.ErrLayoutChanged) {
+		t.Errorf("Fetch() error = %v, want it to include the layout change of the last provider", err)
+	}
+}
+
+func TestChainWithoutProviders(t *testing.T) {
+	if _, err := (esostatus.Chain{}).Fetch(context.Background(), esostatus.DefaultRegions); !errors.Is(err, esostatus.ErrNoProvider) {
+		t.Errorf("Fetch() error = %v, want ErrNoProvider", err)
+	}
+}
+
+func TestParseProviders(t *testing.T) {
+	providers, err := esostatus.ParseProviders("liveservices=http://localhost:8099/realms, esoserverstatus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []esostatus.Provider{
+		esostatus.LiveServicesProvider{URL: "http://localhost:8099/realms"},
+		esostatus.StatusPageProvider{URL: esostatus.DefaultStatusPageURL},
+	}
+	if len(providers) != len(want) || providers[0] != want[0] || providers[1] != want[1] {
+		t.Errorf("ParseProviders() = %v, want %v", providers, want)
+	}
+
+	if _, err = esostatus.ParseProviders("carrier-pigeon"); err == nil {
+		t.Error("ParseProviders(carrier-pigeon) succeeded, want an error")
+	}
+}
@@ -0,0 +1,83 @@
+package esostatus
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Environment tells whether a region is a live or a test realm.
+type Environment string
+
+const (
+	EnvironmentLive Environment = "live"
+	EnvironmentPTS  Environment = "pts"
+)
+
+// Region is a server region whose status is tracked.
+type Region struct {
+	// ID identifies the region, e.g. "PC-EU".
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Platform    string      `json:"platform"`
+	Environment Environment `json:"environment"`
+	// Selector is the CSS selector of the region on the status page, "#<ID>" if empty.
+	Selector string `json:"selector,omitempty"`
+	// Realm is the realm name used by the official live services endpoint.
+	Realm string `json:"realm,omitempty"`
+}
+
+// DefaultRegions are the regions tracked when none are configured.
+var DefaultRegions = []Region{
+	{ID: "PC-EU", Name: "PC-EU", Platform: "PC", Environment: EnvironmentLive, Realm: "The Elder Scrolls Online (EU)"},
+	{ID: "PC-NA", Name: "PC-NA", Platform: "PC", Environment: EnvironmentLive, Realm: "The Elder Scrolls Online (NA)"},
+	{ID: "PC-PTS", Name: "PC-PTS", Platform: "PC", Environment: EnvironmentPTS, Realm: "The Elder Scrolls Online (PTS)"},
+	{ID: "XBOX-EU", Name: "XBOX-EU", Platform: "Xbox", Environment: EnvironmentLive, Realm: "The Elder Scrolls Online (XBox - EU)"},
+	{ID: "XBOX-NA", Name: "XBOX-NA", Platform: "Xbox", Environment: EnvironmentLive, Realm: "The Elder Scrolls Online (XBox - US)"},
+	{ID: "PS4-NA", Name: "PS4-NA", Platform: "PlayStation", Environment: EnvironmentLive, Realm: "The Elder Scrolls Online (PS4 - US)"},
+	{ID: "PS4-EU", Name: "PS4-EU", Platform: "PlayStation", Environment: EnvironmentLive, Realm: "The Elder Scrolls Online (PS4 - EU)"},
+}
+
+// ParseRegions parses a JSON array of regions. An empty config yields DefaultRegions.
+func ParseRegions(config string) ([]Region, error) {
+	if strings.TrimSpace(config) == "" {
+		return DefaultRegions, nil
+	}
+
+	var regions []Region
+	if err := json.Unmarshal([]byte(config), &regions); err != nil {
+		return nil, fmt.Errorf("parsing regions: %w", err)
+	}
+	for i, region := range regions {
+		if region.ID == "" {
+			return nil, fmt.Errorf("parsing regions: entry %d needs an id", i)
+		}
+		if region.Name == "" {
+			regions[i].Name = region.ID
+		}
+		if region.Environment == "" {
+			regions[i].Environment = EnvironmentLive
+		}
+	}
+	return regions, nil
+}
+
+// selector returns the CSS selector of the region on the status page.
+func (r Region) selector() string {
+	if r.Selector != "" {
+		return r.Selector
+	}
+	return "#" + r.ID
+}
+
+// unknownStatus returns the status used when a provider knows nothing about the region.
+func (r Region) unknownStatus(source string) RegionStatus {
+	return RegionStatus{
+		Region:      r.ID,
+		Name:        r.Name,
+		Platform:    r.Platform,
+		Environment: r.Environment,
+		State:       StateUnknown,
+		Source:      source,
+	}
+}
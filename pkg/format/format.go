@@ -0,0 +1,94 @@
+// Package format renders numbers for display according to the viewer's locale.
+package format
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is used when the viewer's locale is unknown.
+const DefaultLocale = "en"
+
+// separators holds the thousands and decimal separator of a language.
+type separators struct {
+	thousands string
+	decimal   string
+}
+
+// localeSeparators maps a language to its separators. Languages that aren't
+// listed use the English ones.
+var localeSeparators = map[string]separators{
+	"en": {thousands: ",", decimal: "."},
+	"de": {thousands: ".", decimal: ","},
+	"es": {thousands: ".", decimal: ","},
+	"fr": {thousands: " ", decimal: ","},
+}
+
+// compactUnits are the suffixes used by Compact, largest first.
+var compactUnits = []struct {
+	value  float64
+	suffix string
+}{
+	{value: 1e9, suffix: "B"},
+	{value: 1e6, suffix: "M"},
+	{value: 1e3, suffix: "k"},
+}
+
+// lookup returns the separators for a locale such as "de-DE".
+func lookup(locale string) separators {
+	language, _, _ := strings.Cut(strings.ToLower(locale), "-")
+	if sep, ok := localeSeparators[language]; ok {
+		return sep
+	}
+	return localeSeparators[DefaultLocale]
+}
+
+// Number formats n with the locale's thousands separator, e.g. "12,345".
+func Number(n int, locale string) string {
+	// The sign is cut from the digits, negating math.MinInt would overflow.
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(lookup(locale).thousands)
+		}
+		b.WriteRune(digit)
+	}
+	return sign + b.String()
+}
+
+// Compact formats n in a short form with one decimal, e.g. "12.3k".
+func Compact(n int, locale string) string {
+	value := math.Abs(float64(n))
+	sign := ""
+	if n < 0 {
+		sign = "-"
+	}
+
+	for _, unit := range compactUnits {
+		if value < unit.value {
+			continue
+		}
+		short := strconv.FormatFloat(math.Floor(value/unit.value*10)/10, 'f', -1, 64)
+		return sign + strings.Replace(short, ".", lookup(locale).decimal, 1) + unit.suffix
+	}
+	return sign + strconv.Itoa(int(value))
+}
+
+// Delta formats a change with an up or down arrow, e.g. "▲ 1.2k".
+func Delta(n int, locale string) string {
+	switch {
+	case n > 0:
+		return "▲ " + Compact(n, locale)
+	case n < 0:
+		return "▼ " + strings.TrimPrefix(Compact(n, locale), "-")
+	default:
+		return "= 0"
+	}
+}
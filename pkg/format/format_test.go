@@ -0,0 +1,77 @@
+package format_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/format"
+)
+
+func TestNumber(t *testing.T) {
+	tests := []struct {
+		n      int
+		locale string
+		want   string
+	}{
+		{n: 0, locale: "en", want: "0"},
+		{n: 999, locale: "en", want: "999"},
+		{n: 1000, locale: "en", want: "1,000"},
+		{n: 12345, locale: "en-US", want: "12,345"},
+		{n: 1234567, locale: "de", want: "1.234.567"},
+		{n: 1234567, locale: "fr-FR", want: "1\u202f234\u202f567"},
+		{n: 1234567, locale: "es", want: "1.234.567"},
+		{n: 1234567, locale: "xx", want: "1,234,567"},
+		{n: -1234, locale: "en", want: "-1,234"},
+		{n: -123, locale: "en", want: "-123"},
+		{n: math.MaxInt64, locale: "en", want: "9,223,372,036,854,775,807"},
+		{n: math.MinInt64, locale: "en", want: "-9,223,372,036,854,775,808"},
+	}
+	for _, tt := range tests {
+		if got := format.Number(tt.n, tt.locale); got != tt.want {
+			t.Errorf("Number(%d, %q) = %q, want %q", tt.n, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		n      int
+		locale string
+		want   string
+	}{
+		{n: 0, locale: "en", want: "0"},
+		{n: 999, locale: "en", want: "999"},
+		{n: 1000, locale: "en", want: "1k"},
+		{n: 12345, locale: "en", want: "12.3k"},
+		{n: 12399, locale: "en", want: "12.3k"},
+		{n: 12345, locale: "de", want: "12,3k"},
+		{n: 2500000, locale: "fr", want: "2,5M"},
+		{n: 3000000000, locale: "en", want: "3B"},
+		{n: -12345, locale: "en", want: "-12.3k"},
+		{n: -5, locale: "en", want: "-5"},
+	}
+	for _, tt := range tests {
+		if got := format.Compact(tt.n, tt.locale); got != tt.want {
+			t.Errorf("Compact(%d, %q) = %q, want %q", tt.n, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestDelta(t *testing.T) {
+	tests := []struct {
+		n      int
+		locale string
+		want   string
+	}{
+		{n: 0, locale: "en", want: "= 0"},
+		{n: 1234, locale: "en", want: "▲ 1.2k"},
+		{n: -1234, locale: "de", want: "▼ 1,2k"},
+		{n: -12, locale: "en", want: "▼ 12"},
+		{n: math.MinInt64, locale: "en", want: "▼ 9223372036.8B"},
+	}
+	for _, tt := range tests {
+		if got := format.Delta(tt.n, tt.locale); got != tt.want {
+			t.Errorf("Delta(%d, %q) = %q, want %q", tt.n, tt.locale, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package history
+
+import (
+	"iter"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// chunkSize is how many entries an iterator copies at a time, so long ranges
+// neither sit in memory at once nor keep the lock while they are consumed.
+const chunkSize = 1024
+
+// chunks iterates over the entries of the time sorted list from start up to
+// end. Every chunkSize entries it takes mu again and continues after the last
+// time it yielded, so entries added or pruned meanwhile are handled.
+func chunks[T any](mu *sync.Mutex, list *[]T, timeOf func(T) time.Time, start, end time.Time) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		after := func(t time.Time) bool { return !t.Before(start) }
+		for {
+			mu.Lock()
+			entries := *list
+			i := sort.Search(len(entries), func(i int) bool { return after(timeOf(entries[i])) })
+			j := min(i+chunkSize, len(entries))
+			// Entries of the same time go in the same chunk, as the next one
+			// starts after that time.
+			for j > i && j < len(entries) && timeOf(entries[j]).Equal(timeOf(entries[j-1])) {
+				j++
+			}
+			chunk := slices.Clone(entries[i:j])
+			last := j == len(entries)
+			mu.Unlock()
+
+			for _, entry := range chunk {
+				if timeOf(entry).After(end) || !yield(entry) {
+					return
+				}
+			}
+			if last || len(chunk) == 0 {
+				return
+			}
+			cursor := timeOf(chunk[len(chunk)-1])
+			after = func(t time.Time) bool { return t.After(cursor) }
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Package history keeps time-stamped samples of a value, e.g. a player count,
+// so the dashboard can show how it changed over time.
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+// Retention is how long samples are kept. It covers the "same time yesterday"
+// comparison with some slack.
+const Retention = 25 * time.Hour
+
+// Tolerance is how far a stored sample may be from the requested time to
+// still be used for a comparison.
+const Tolerance = 20 * time.Minute
+
+// minSampleInterval prevents flooding the series when the page is reloaded often.
+const minSampleInterval = time.Minute
+
+// Granularity tells how a sample was taken.
+type Granularity string
+
+const (
+	// Sampled samples were polled at their time.
+	Sampled Granularity = ""
+	// Imported samples were polled at their time by another install and
+	// restored from its export.
+	Imported Granularity = "imported"
+	// Monthly samples are the average of the month starting at their time,
+	// imported from Steam Charts with the peak of the month.
+	Monthly Granularity = "monthly"
+)
+
+// Sample is a value observed at a point in time.
+type Sample struct {
+	Time  time.Time `json:"time"`
+	Value int       `json:"value"`
+	// Peak is the highest value of the period of a Monthly sample.
+	Peak        int         `json:"peak,omitempty"`
+	Granularity Granularity `json:"granularity,omitempty"`
+}
+
+// Series is a list of samples ordered by time.
+type Series []Sample
+
+// Add appends a sample and drops samples older than Retention. A sample taken
+// within a minute of the previous one replaces it.
+func (s Series) Add(t time.Time, value int) Series {
+	if n := len(s); n > 0 && t.Sub(s[n-1].Time) < minSampleInterval {
+		s[n-1] = Sample{Time: t, Value: value}
+	} else {
+		s = append(s, Sample{Time: t, Value: value})
+	}
+	return s.Prune(t.Add(-Retention))
+}
+
+// Prune returns the samples taken at or after the cutoff.
+func (s Series) Prune(cutoff time.Time) Series {
+	i := sort.Search(len(s), func(i int) bool { return !s[i].Time.Before(cutoff) })
+	return append(Series(nil), s[i:]...)
+}
+
+// At returns the sample closest to t, if one lies within Tolerance.
+func (s Series) At(t time.Time) (Sample, bool) {
+	var closest Sample
+	found := false
+	for _, sample := range s {
+		if d := absDuration(sample.Time.Sub(t)); d <= Tolerance &&
+			(!found || d < absDuration(closest.Time.Sub(t))) {
+			closest = sample
+			found = true
+		}
+	}
+	return closest, found
+}
+
+// Delta returns how much value changed compared to the sample taken ago
+// before now.
+func (s Series) Delta(now time.Time, value int, ago time.Duration) (int, bool) {
+	sample, ok := s.At(now.Add(-ago))
+	if !ok {
+		return 0, false
+	}
+	return value - sample.Value, true
+}
+
+// absDuration returns the absolute value of d.
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
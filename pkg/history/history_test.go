@@ -0,0 +1,88 @@
+package history_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+)
+
+// now is the time the series are compared at.
+var now = time.Date(2025, time.October, 19, 12, 0, 0, 0, time.UTC)
+
+// series holds samples one hour, one day and 25 hours before now.
+var series = history.Series{
+	{Time: now.Add(-25 * time.Hour), Value: 900},
+	{Time: now.Add(-24*time.Hour - 5*time.Minute), Value: 1000},
+	{Time: now.Add(-65 * time.Minute), Value: 1400},
+	{Time: now.Add(-50 * time.Minute), Value: 1500},
+}
+
+func TestSeriesAt(t *testing.T) {
+	tests := []struct {
+		name  string
+		at    time.Time
+		want  int
+		found bool
+	}{
+		{name: "exact", at: now.Add(-50 * time.Minute), want: 1500, found: true},
+		{name: "closest of two", at: now.Add(-time.Hour), want: 1400, found: true},
+		{name: "closest of two after", at: now.Add(-55 * time.Minute), want: 1500, found: true},
+		{name: "within tolerance", at: now.Add(-24*time.Hour + 15*time.Minute), want: 1000, found: true},
+		{name: "beyond tolerance", at: now.Add(-25 * time.Minute), found: false},
+		{name: "before every sample", at: now.Add(-48 * time.Hour), found: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sample, found := series.At(tt.at)
+			if found != tt.found || (found && sample.Value != tt.want) {
+				t.Errorf("At() = %d, %t, want %d, %t", sample.Value, found, tt.want, tt.found)
+			}
+		})
+	}
+
+	if _, found := (history.Series{}).At(now); found {
+		t.Error("At() of an empty series found a sample")
+	}
+}
+
+func TestSeriesDelta(t *testing.T) {
+	tests := []struct {
+		name  string
+		value int
+		ago   time.Duration
+		want  int
+		ok    bool
+	}{
+		{name: "1h ago", value: 1700, ago: time.Hour, want: 300, ok: true},
+		{name: "yesterday", value: 800, ago: 24 * time.Hour, want: -200, ok: true},
+		{name: "unchanged", value: 1400, ago: time.Hour, want: 0, ok: true},
+		{name: "no sample", value: 1700, ago: 12 * time.Hour, ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta, ok := series.Delta(now, tt.value, tt.ago)
+			if delta != tt.want || ok != tt.ok {
+				t.Errorf("Delta() = %d, %t, want %d, %t", delta, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSeriesAdd(t *testing.T) {
+	s := history.Series{}.Add(now.Add(-26*time.Hour), 1)
+	s = s.Add(now.Add(-time.Hour), 2)
+	s = s.Add(now.Add(-time.Hour+30*time.Second), 3)
+	s = s.Add(now, 4)
+
+	// The first sample is older than Retention, the third replaces the second.
+	want := history.Series{{Time: now.Add(-time.Hour + 30*time.Second), Value: 3}, {Time: now, Value: 4}}
+	if len(s) != len(want) {
+		t.Fatalf("Add() = %v, want %v", s, want)
+	}
+	for i := range want {
+		if !s[i].Time.Equal(want[i].Time) || s[i].Value != want[i].Value {
+			t.Errorf("Add()[%d] = %v, want %v", i, s[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// lockFile is the file in the data directory that is locked while a process
+// writes the histories.
+const lockFile = "lock"
+
+// ErrLocked is returned by LockDir while another process holds the lock, e.g.
+// the server while the history is imported.
+var ErrLocked = errors.New("the data directory is in use by another process")
+
+// Lock is the exclusive lock of a data directory. Only one process may keep
+// the histories of a directory in memory, or the last one saving wins.
+type Lock struct {
+	file *os.File
+}
+
+// lockPath returns the path of the lock file of dir.
+func lockPath(dir string) string {
+	return filepath.Join(dir, lockFile)
+}
@@ -0,0 +1,33 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// LockDir takes the lock of the data directory dir without waiting, ErrLocked
+// if another process holds it. Without flock the lock is the existence of the
+// lock file, which stays behind if the process crashes and must then be
+// removed by hand.
+func LockDir(dir string) (*Lock, error) {
+	file, err := os.OpenFile(lockPath(dir), os.O_CREATE|os.O_EXCL|os.O_RDWR, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return nil, ErrLocked
+	}
+	if err != nil {
+		return nil, fmt.Errorf("locking %s: %w", dir, err)
+	}
+	return &Lock{file: file}, nil
+}
+
+// Unlock releases the lock.
+func (l *Lock) Unlock() error {
+	err := l.file.Close()
+	if removeErr := os.Remove(l.file.Name()); err == nil {
+		err = removeErr
+	}
+	return err
+}
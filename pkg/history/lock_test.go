@@ -0,0 +1,30 @@
+package history_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+)
+
+func TestLockDir(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := history.LockDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = history.LockDir(dir); !errors.Is(err, history.ErrLocked) {
+		t.Fatalf("LockDir() of a locked directory = %v, want ErrLocked", err)
+	}
+
+	if err = lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	lock, err = history.LockDir(dir)
+	if err != nil {
+		t.Fatalf("LockDir() after Unlock() = %v", err)
+	}
+	if err = lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+}
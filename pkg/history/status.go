@@ -0,0 +1,88 @@
+package history
+
+import (
+	"fmt"
+	"iter"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/jsonfile"
+)
+
+// StatusChange is a region entering a state.
+type StatusChange struct {
+	Time   time.Time       `json:"time"`
+	Region string          `json:"region"`
+	Name   string          `json:"name"`
+	State  esostatus.State `json:"state"`
+}
+
+// StatusLog keeps the state changes of the regions polled by the server in a
+// JSON file. Only changes are stored, a region keeps its state until the next
+// one.
+type StatusLog struct {
+	path    string
+	mu      sync.Mutex
+	changes []StatusChange
+}
+
+// OpenStatusLog loads the changes stored at path. A missing file starts an
+// empty log, a corrupt one is kept as a backup and starts an empty one too.
+func OpenStatusLog(path string) (*StatusLog, error) {
+	changes, err := jsonfile.Load[[]StatusChange](path)
+	if err != nil {
+		return nil, fmt.Errorf("loading status history: %w", err)
+	}
+	return &StatusLog{path: path, changes: changes}, nil
+}
+
+// Record stores the regions whose state differs from their last change and
+// drops the changes older than StoreRetention.
+func (l *StatusLog) Record(t time.Time, regions []esostatus.RegionStatus) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	last := map[string]esostatus.State{}
+	for _, change := range l.changes {
+		last[change.Region] = change.State
+	}
+
+	changed := false
+	for _, region := range regions {
+		if state, ok := last[region.Region]; ok && state == region.State {
+			continue
+		}
+		l.changes = append(l.changes, StatusChange{Time: t, Region: region.Region, Name: region.Name, State: region.State})
+		changed = true
+	}
+
+	cutoff := t.Add(-StoreRetention)
+	for len(l.changes) > 0 && l.changes[0].Time.Before(cutoff) {
+		l.changes = l.changes[1:]
+		changed = true
+	}
+	if changed {
+		l.save()
+	}
+}
+
+// Range returns the changes from start up to end.
+func (l *StatusLog) Range(start, end time.Time) []StatusChange {
+	return slices.Collect(l.Changes(start, end))
+}
+
+// Changes iterates over the changes from start up to end without copying all
+// of them at once, e.g. to stream an export.
+func (l *StatusLog) Changes(start, end time.Time) iter.Seq[StatusChange] {
+	return chunks(&l.mu, &l.changes, func(change StatusChange) time.Time { return change.Time }, start, end)
+}
+
+// save writes the changes, logging failures as the poller can't handle them.
+func (l *StatusLog) save() {
+	if err := jsonfile.Write(l.path, l.changes); err != nil {
+		log.Println("Error saving status history:", err)
+	}
+}
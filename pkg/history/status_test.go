@@ -0,0 +1,60 @@
+package history_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+)
+
+// regions returns PC-EU in the state.
+func regions(state esostatus.State) []esostatus.RegionStatus {
+	return []esostatus.RegionStatus{{Region: "PC-EU", Name: "PC Europe", State: state}}
+}
+
+func TestStatusLog(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "status-history.json")
+	statusLog, err := history.OpenStatusLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statusLog.Record(now.Add(-2*time.Hour), regions(esostatus.StateOnline))
+	statusLog.Record(now.Add(-time.Hour), regions(esostatus.StateOnline))
+	statusLog.Record(now, regions(esostatus.StateMaintenance))
+
+	reopened, err := history.OpenStatusLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := reopened.Range(now.Add(-3*time.Hour), now)
+	if len(changes) != 2 || changes[0].State != esostatus.StateOnline || changes[1].State != esostatus.StateMaintenance {
+		t.Errorf("changes = %+v, want online and then maintenance", changes)
+	}
+	if names := files(t, dir); len(names) != 1 {
+		t.Errorf("data directory holds %v, want only the log", names)
+	}
+}
+
+func TestOpenStatusLogCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "status-history.json")
+	if err := os.WriteFile(path, []byte(`{"not":"a list"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	statusLog, err := history.OpenStatusLog(path)
+	if err != nil {
+		t.Fatalf("OpenStatusLog() error = %v, want an empty log", err)
+	}
+	if changes := statusLog.Range(time.Time{}, now); len(changes) > 0 {
+		t.Errorf("changes = %+v, want none", changes)
+	}
+	if names := files(t, dir); len(names) != 1 || !strings.HasPrefix(names[0], "status-history.json.corrupt-") {
+		t.Errorf("data directory holds %v, want the backup of the corrupt file", names)
+	}
+}
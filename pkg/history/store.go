@@ -0,0 +1,172 @@
+package history
+
+import (
+	"fmt"
+	"iter"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/jsonfile"
+)
+
+// StoreRetention is how long the server keeps the polled player counts.
+// Monthly samples are kept for good, there are only twelve a year.
+const StoreRetention = 90 * 24 * time.Hour
+
+// Store keeps the player counts polled by the server in a JSON file, so the
+// history survives restarts and can be read by the command line tools.
+type Store struct {
+	path     string
+	readOnly bool
+	mu       sync.Mutex
+	series   Series
+}
+
+// OpenStore loads the samples stored at path. A missing file starts an empty
+// history, a corrupt one is kept as a backup and starts an empty one too.
+func OpenStore(path string) (*Store, error) {
+	series, err := jsonfile.Load[Series](path)
+	if err != nil {
+		return nil, fmt.Errorf("loading history: %w", err)
+	}
+	return &Store{path: path, series: series}, nil
+}
+
+// ReadStore loads the samples stored at path for reading only, next to a
+// server that may be running: a missing file is an empty history, a corrupt
+// one is an error and left to the server. Adding to the store doesn't write
+// the file.
+func ReadStore(path string) (*Store, error) {
+	series, err := jsonfile.Read[Series](path)
+	if err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+	return &Store{path: path, readOnly: true, series: series}, nil
+}
+
+// Add records a sample and drops the ones older than StoreRetention.
+func (s *Store) Add(t time.Time, value int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n := len(s.series); n > 0 && s.series[n-1].Granularity == Sampled && t.Sub(s.series[n-1].Time) < minSampleInterval {
+		s.series[n-1] = Sample{Time: t, Value: value}
+	} else {
+		s.series = append(s.series, Sample{Time: t, Value: value})
+	}
+	s.prune(t.Add(-StoreRetention))
+	s.save()
+}
+
+// ImportResult counts what Import did with the samples.
+type ImportResult struct {
+	// Added samples are in the history now.
+	Added int
+	// Overlapping samples were skipped as the history has them already.
+	Overlapping int
+	// Expired samples are polled ones older than StoreRetention, skipped as
+	// they would be dropped right away.
+	Expired int
+}
+
+// Import merges samples into the history. Overlapping samples are skipped:
+// polled ones in the minute of a stored polled one or next to it, monthly
+// ones for a month that is stored already, and monthly ones for months with
+// polled samples, which are more precise. Polled samples older than
+// StoreRetention are skipped as expired.
+func (s *Store) Import(samples Series) ImportResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	monthly := map[time.Time]bool{}
+	polled := map[time.Time]bool{}
+	minutes := map[int64]bool{}
+	for _, sample := range s.series {
+		if sample.Granularity == Monthly {
+			monthly[monthOf(sample.Time)] = true
+			continue
+		}
+		polled[monthOf(sample.Time)] = true
+		minutes[minuteOf(sample.Time)] = true
+	}
+
+	cutoff := time.Now().Add(-StoreRetention)
+	var result ImportResult
+	for _, sample := range samples {
+		month := monthOf(sample.Time)
+		if sample.Granularity == Monthly {
+			if monthly[month] || polled[month] {
+				result.Overlapping++
+				continue
+			}
+			monthly[month] = true
+		} else {
+			minute := minuteOf(sample.Time)
+			if sample.Time.Before(cutoff) {
+				result.Expired++
+				continue
+			}
+			if minutes[minute-1] || minutes[minute] || minutes[minute+1] {
+				result.Overlapping++
+				continue
+			}
+			minutes[minute] = true
+			polled[month] = true
+		}
+		s.series = append(s.series, sample)
+		result.Added++
+	}
+	if result.Added == 0 {
+		return result
+	}
+
+	// Polled samples replace the monthly average of their month.
+	s.series = slices.DeleteFunc(s.series, func(sample Sample) bool {
+		return sample.Granularity == Monthly && polled[monthOf(sample.Time)]
+	})
+	slices.SortStableFunc(s.series, func(a, b Sample) int { return a.Time.Compare(b.Time) })
+	s.save()
+	return result
+}
+
+// minuteOf numbers the minute of t.
+func minuteOf(t time.Time) int64 {
+	return t.Unix() / 60
+}
+
+// prune drops the polled and imported samples taken before the cutoff.
+func (s *Store) prune(cutoff time.Time) {
+	s.series = slices.DeleteFunc(s.series, func(sample Sample) bool {
+		return sample.Granularity != Monthly && sample.Time.Before(cutoff)
+	})
+}
+
+// monthOf returns the first instant of the month of t in UTC, the time
+// Steam Charts months start at.
+func monthOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Range returns the samples taken from start up to end.
+func (s *Store) Range(start, end time.Time) Series {
+	return slices.Collect(s.Samples(start, end))
+}
+
+// Samples iterates over the samples taken from start up to end without
+// copying all of them at once, e.g. to stream an export.
+func (s *Store) Samples(start, end time.Time) iter.Seq[Sample] {
+	return chunks(&s.mu, (*[]Sample)(&s.series), func(sample Sample) time.Time { return sample.Time }, start, end)
+}
+
+// save writes the samples, logging failures as the poller can't handle them.
+func (s *Store) save() {
+	if s.readOnly {
+		return
+	}
+	if err := jsonfile.Write(s.path, s.series); err != nil {
+		log.Println("Error saving history:", err)
+	}
+}
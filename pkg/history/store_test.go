@@ -0,0 +1,273 @@
+package history_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/history"
+)
+
+// files returns the names of the files in dir.
+func files(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestStorePersists(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "player-history.json")
+	store, err := history.OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Add(now.Add(-time.Hour), 1400)
+	store.Add(now, 1500)
+
+	reopened, err := history.OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Range(now.Add(-2*time.Hour), now); len(got) != 2 || got[1].Value != 1500 {
+		t.Errorf("reopened history = %+v, want both samples", got)
+	}
+	// The temporary files are renamed over the history.
+	if names := files(t, dir); len(names) != 1 || names[0] != "player-history.json" {
+		t.Errorf("data directory holds %v, want only the history", names)
+	}
+}
+
+func TestOpenStoreCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "player-history.json")
+	corrupt := []byte(`[{"time":"2025-10-19T11:00:00Z","value":14`)
+	if err := os.WriteFile(path, corrupt, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := history.OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v, want an empty history", err)
+	}
+	if got := store.Range(time.Time{}, now); len(got) > 0 {
+		t.Errorf("history = %+v, want it empty", got)
+	}
+
+	names := files(t, dir)
+	if len(names) != 1 || !strings.HasPrefix(names[0], "player-history.json.corrupt-") {
+		t.Fatalf("data directory holds %v, want the backup of the corrupt file", names)
+	}
+	backup, err := os.ReadFile(filepath.Join(dir, names[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(backup) != string(corrupt) {
+		t.Errorf("backup = %q, want the corrupt file", backup)
+	}
+
+	// The empty history is written over nothing, the backup stays.
+	store.Add(now, 1500)
+	if names = files(t, dir); len(names) != 2 {
+		t.Errorf("data directory holds %v, want the backup and the new history", names)
+	}
+}
+
+func TestReadStore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "player-history.json")
+	writer, err := history.OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer.Add(now, 1500)
+
+	reader, err := history.ReadStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reader.Range(time.Time{}, now); len(got) != 1 || got[0].Value != 1500 {
+		t.Errorf("history = %+v, want the stored sample", got)
+	}
+	// Adding to a read-only store doesn't write the file.
+	reader.Add(now.Add(time.Hour), 1600)
+	if stored, err := history.ReadStore(path); err != nil || len(stored.Range(time.Time{}, now.Add(time.Hour))) != 1 {
+		t.Errorf("the read-only store wrote the file: %v", err)
+	}
+
+	corrupt := []byte(`[{"time":"2025-10-19T11:00:00Z","value":14`)
+	if err = os.WriteFile(path, corrupt, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = history.ReadStore(path); err == nil {
+		t.Error("ReadStore() of a corrupt file succeeded")
+	}
+	// The corrupt file is left to the server.
+	if names := files(t, dir); len(names) != 1 || names[0] != "player-history.json" {
+		t.Errorf("data directory holds %v, want the corrupt history untouched", names)
+	}
+}
+
+func TestStoreSamples(t *testing.T) {
+	store, err := history.OpenStore(filepath.Join(t.TempDir(), "player-history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// More samples than are copied at a time, five minutes apart up to now.
+	end := time.Now().Truncate(time.Minute)
+	var samples history.Series
+	for i := 2500; i > 0; i-- {
+		samples = append(samples, history.Sample{Time: end.Add(-time.Duration(i) * 5 * time.Minute), Value: i})
+	}
+	if result := store.Import(samples); result.Added != len(samples) {
+		t.Fatalf("Import() added %d samples, want %d", result.Added, len(samples))
+	}
+
+	start := samples[100].Time
+	var got history.Series
+	for sample := range store.Samples(start, end) {
+		got = append(got, sample)
+		// The store isn't locked while the samples are consumed.
+		if len(got) == 1 {
+			store.Add(end.Add(time.Minute), 1)
+		}
+	}
+	if len(got) != len(samples)-100 || !got[0].Time.Equal(start) || got[len(got)-1].Value != 1 {
+		t.Fatalf("Samples() yielded %d samples from %v, want %d from %v", len(got), got[0].Time, len(samples)-100, start)
+	}
+	for i := 1; i < len(got); i++ {
+		if !got[i].Time.After(got[i-1].Time) {
+			t.Fatalf("sample %d at %v doesn't follow %v", i, got[i].Time, got[i-1].Time)
+		}
+	}
+
+	n := 0
+	for range store.Samples(time.Time{}, end) {
+		n++
+		if n == 10 {
+			break
+		}
+	}
+	if n != 10 {
+		t.Errorf("Samples() went on after the loop stopped, %d samples", n)
+	}
+}
+
+func TestStoreImport(t *testing.T) {
+	// Polled samples must be within StoreRetention of the wall clock.
+	recent := time.Now().UTC().Truncate(time.Minute).Add(-24 * time.Hour)
+	month := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	recentMonth := time.Date(recent.Year(), recent.Month(), 1, 0, 0, 0, 0, time.UTC)
+	polled := func(t time.Time, value int) history.Sample {
+		return history.Sample{Time: t, Value: value, Granularity: history.Imported}
+	}
+	monthly := func(t time.Time, value int) history.Sample {
+		return history.Sample{Time: t, Value: value, Peak: value * 2, Granularity: history.Monthly}
+	}
+
+	tests := []struct {
+		name     string
+		stored   history.Series
+		imported history.Series
+		want     history.ImportResult
+		// values are the values of the history after the import, by time.
+		values []int
+	}{
+		{
+			name:     "into an empty history",
+			imported: history.Series{monthly(month, 10), polled(recent, 20)},
+			want:     history.ImportResult{Added: 2},
+			values:   []int{10, 20},
+		},
+		{
+			name:     "polled in the same minute",
+			stored:   history.Series{polled(recent, 20)},
+			imported: history.Series{polled(recent.Add(30*time.Second), 21)},
+			want:     history.ImportResult{Overlapping: 1},
+			values:   []int{20},
+		},
+		{
+			name:     "polled in the next minute",
+			stored:   history.Series{polled(recent, 20)},
+			imported: history.Series{polled(recent.Add(time.Minute), 21), polled(recent.Add(-time.Minute), 19)},
+			want:     history.ImportResult{Overlapping: 2},
+			values:   []int{20},
+		},
+		{
+			name:     "polled two minutes apart",
+			stored:   history.Series{polled(recent, 20)},
+			imported: history.Series{polled(recent.Add(2*time.Minute), 22)},
+			want:     history.ImportResult{Added: 1},
+			values:   []int{20, 22},
+		},
+		{
+			name:     "overlapping within the import",
+			imported: history.Series{polled(recent, 20), polled(recent.Add(10*time.Second), 21), monthly(month, 10), monthly(month.Add(time.Hour), 11)},
+			want:     history.ImportResult{Added: 2, Overlapping: 2},
+			values:   []int{10, 20},
+		},
+		{
+			name:     "expired polled",
+			imported: history.Series{polled(time.Now().Add(-history.StoreRetention-time.Hour), 5), polled(recent, 20)},
+			want:     history.ImportResult{Added: 1, Expired: 1},
+			values:   []int{20},
+		},
+		{
+			name:     "old monthly kept for good",
+			imported: history.Series{monthly(time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC), 3)},
+			want:     history.ImportResult{Added: 1},
+			values:   []int{3},
+		},
+		{
+			name:     "monthly of a stored month",
+			stored:   history.Series{monthly(month, 10)},
+			imported: history.Series{monthly(month, 12)},
+			want:     history.ImportResult{Overlapping: 1},
+			values:   []int{10},
+		},
+		{
+			name:     "monthly of a polled month",
+			stored:   history.Series{polled(recent, 20)},
+			imported: history.Series{monthly(recentMonth, 12)},
+			want:     history.ImportResult{Overlapping: 1},
+			values:   []int{20},
+		},
+		{
+			name:     "polled replacing the monthly average",
+			stored:   history.Series{monthly(recentMonth, 12)},
+			imported: history.Series{polled(recent, 20)},
+			want:     history.ImportResult{Added: 1},
+			values:   []int{20},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := history.OpenStore(filepath.Join(t.TempDir(), "player-history.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			store.Import(tt.stored)
+
+			if got := store.Import(tt.imported); got != tt.want {
+				t.Errorf("Import() = %+v, want %+v", got, tt.want)
+			}
+			var values []int
+			for sample := range store.Samples(time.Time{}, time.Now()) {
+				values = append(values, sample.Value)
+			}
+			if !slices.Equal(values, tt.values) {
+				t.Errorf("history = %v, want %v", values, tt.values)
+			}
+		})
+	}
+}
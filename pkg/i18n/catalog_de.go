@@ -0,0 +1,115 @@
+package i18n
+
+// de are the German translations.
+var de = map[string]string{
+	// Dashboard
+	"Elder Scrolls Online":   "Elder Scrolls Online",
+	"Customize layout":       "Layout anpassen",
+	"Done":                   "Fertig",
+	"Reset layout":           "Layout zurücksetzen",
+	"Move earlier":           "Nach vorne verschieben",
+	"Move later":             "Nach hinten verschieben",
+	"Narrower":               "Schmaler",
+	"Wider":                  "Breiter",
+	"Hide card":              "Karte ausblenden",
+	"Show card":              "Karte einblenden",
+	"Theme":                  "Design",
+	"Language":               "Sprache",
+	"Loading...":             "Wird geladen ...",
+	"Unreachable":            "Nicht erreichbar",
+	"Show":                   "Anzeigen",
+	"Hide":                   "Ausblenden",
+	"Skip to the cards":      "Zu den Karten springen",
+	"Pause background video": "Hintergrundvideo anhalten",
+	"Play background video":  "Hintergrundvideo abspielen",
+	"Lite mode":              "Sparmodus",
+	"Save data: no background video, smaller images and less frequent updates": "Daten sparen: kein Hintergrundvideo, kleinere Bilder und seltenere Aktualisierungen",
+	"Offline — data from %s":                               "Offline — Daten vom %s",
+	"Offline — the data refreshes once you're back online": "Offline — die Daten werden aktualisiert, sobald du wieder online bist",
+	"Couldn't refresh — data from %s":                      "Aktualisierung fehlgeschlagen — Daten vom %s",
+	"Install app":                                          "App installieren",
+	"Read-only copy — data from %s":                        "Schreibgeschützte Kopie — Daten vom %s",
+	"Read-only copy":                                       "Schreibgeschützte Kopie",
+
+	// Widgets
+	"ESO Server Status":    "ESO-Serverstatus",
+	"Latest ESO News":      "Neueste ESO-News",
+	"Active Users":         "Aktive Spieler",
+	"24 Hour Peak":         "24-Stunden-Höchstwert",
+	"All-Time Peak":        "Allzeit-Höchstwert",
+	"Upcoming Maintenance": "Anstehende Wartungen",
+	"Status Notifications": "Statusbenachrichtigungen",
+	"Players per Platform": "Spieler pro Plattform",
+	"Game Comparison":      "Spielevergleich",
+
+	// Themes
+	"Dark":                "Dunkel",
+	"Light":               "Hell",
+	"High contrast":       "Hoher Kontrast",
+	"Aldmeri Dominion":    "Aldmeri-Dominion",
+	"Daggerfall Covenant": "Dolchsturz-Bündnis",
+	"Ebonheart Pact":      "Ebenherz-Pakt",
+
+	// Player counts
+	"Download history:":                 "Verlauf herunterladen:",
+	"Download the player history as %s": "Spielerverlauf als %s herunterladen",
+	"CSV":                               "CSV",
+	"JSON":                              "JSON",
+	"vs 1h ago":                         "ggü. vor 1 Std.",
+	"vs yesterday":                      "ggü. gestern",
+	"Game":                              "Spiel",
+
+	// Population
+	"Error loading population estimates": "Fehler beim Laden der Bevölkerungsschätzungen",
+	"Source: %s":                         "Quelle: %s",
+	"high confidence":                    "hohe Zuverlässigkeit",
+	"medium confidence":                  "mittlere Zuverlässigkeit",
+	"low confidence":                     "geringe Zuverlässigkeit",
+
+	// Server status
+	"All servers operational":     "Alle Server in Betrieb",
+	"Minor issues detected":       "Kleinere Probleme erkannt",
+	"Server maintenance ongoing":  "Serverwartung läuft",
+	"Critical failure detected":   "Kritischer Ausfall erkannt",
+	"Error loading server status": "Fehler beim Laden des Serverstatus",
+	"All %d other regions OK":     "Alle %d anderen Regionen OK",
+	"Collapse other regions":      "Andere Regionen einklappen",
+	"Show %d hidden regions":      "%d ausgeblendete Regionen anzeigen",
+	"Hide hidden regions":         "Ausgeblendete Regionen verbergen",
+	"Last updated %s":             "Zuletzt aktualisiert: %s",
+	"Hide region":                 "Region ausblenden",
+	"Unhide":                      "Einblenden",
+	"Unhide region":               "Region einblenden",
+	"Star region":                 "Region markieren",
+	"Online":                      "Online",
+	"Offline":                     "Offline",
+	"Maintenance":                 "Wartung",
+	"Unknown":                     "Unbekannt",
+	"Since %s":                    "Seit %s",
+	"Disagreement":                "Widerspruch",
+	"%s reports %s":               "%s meldet %s",
+
+	// Maintenance
+	"No maintenance announced":                     "Keine Wartung angekündigt",
+	"Subscribe to the maintenance calendar (.ics)": "Wartungskalender abonnieren (.ics)",
+	"ongoing": "läuft",
+	"in %s":   "in %s",
+
+	// News
+	"Published on %s":         "Veröffentlicht am %s",
+	"Error fetching RSS feed": "Fehler beim Abrufen des RSS-Feeds",
+	"Error parsing RSS feed":  "Fehler beim Verarbeiten des RSS-Feeds",
+	"News language":           "Sprache der News",
+	"(opens in a new tab)":    "(öffnet in einem neuen Tab)",
+
+	// Push notifications
+	"Push notifications aren't available on this server": "Push-Benachrichtigungen sind auf diesem Server nicht verfügbar",
+	"Enable notifications":                               "Benachrichtigungen aktivieren",
+	"Update regions":                                     "Regionen aktualisieren",
+	"Get notified when a region goes down or comes back. Leave every region unticked to watch all of them.": "Lass dich benachrichtigen, wenn eine Region ausfällt oder wieder erreichbar ist. Ohne ausgewählte Region werden alle beobachtet.",
+	"Disable notifications":             "Benachrichtigungen deaktivieren",
+	"Error enabling notifications: %v":  "Fehler beim Aktivieren der Benachrichtigungen: %v",
+	"Notifications enabled":             "Benachrichtigungen aktiviert",
+	"Error disabling notifications: %v": "Fehler beim Deaktivieren der Benachrichtigungen: %v",
+	"Notifications disabled":            "Benachrichtigungen deaktiviert",
+}
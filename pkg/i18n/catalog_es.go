@@ -0,0 +1,115 @@
+package i18n
+
+// es are the Spanish translations.
+var es = map[string]string{
+	// Dashboard
+	"Elder Scrolls Online":   "Elder Scrolls Online",
+	"Customize layout":       "Personalizar diseño",
+	"Done":                   "Listo",
+	"Reset layout":           "Restablecer diseño",
+	"Move earlier":           "Mover antes",
+	"Move later":             "Mover después",
+	"Narrower":               "Más estrecho",
+	"Wider":                  "Más ancho",
+	"Hide card":              "Ocultar tarjeta",
+	"Show card":              "Mostrar tarjeta",
+	"Theme":                  "Tema",
+	"Language":               "Idioma",
+	"Loading...":             "Cargando...",
+	"Unreachable":            "Inaccesible",
+	"Show":                   "Mostrar",
+	"Hide":                   "Ocultar",
+	"Skip to the cards":      "Saltar a las tarjetas",
+	"Pause background video": "Pausar el vídeo de fondo",
+	"Play background video":  "Reproducir el vídeo de fondo",
+	"Lite mode":              "Modo ligero",
+	"Save data: no background video, smaller images and less frequent updates": "Ahorrar datos: sin vídeo de fondo, imágenes más pequeñas y actualizaciones menos frecuentes",
+	"Offline — data from %s":                               "Sin conexión — datos del %s",
+	"Offline — the data refreshes once you're back online": "Sin conexión — los datos se actualizarán cuando vuelvas a estar en línea",
+	"Couldn't refresh — data from %s":                      "No se pudo actualizar — datos del %s",
+	"Install app":                                          "Instalar la aplicación",
+	"Read-only copy — data from %s":                        "Copia de solo lectura — datos del %s",
+	"Read-only copy":                                       "Copia de solo lectura",
+
+	// Widgets
+	"ESO Server Status":    "Estado de los servidores de ESO",
+	"Latest ESO News":      "Últimas noticias de ESO",
+	"Active Users":         "Jugadores activos",
+	"24 Hour Peak":         "Pico de 24 horas",
+	"All-Time Peak":        "Récord histórico",
+	"Upcoming Maintenance": "Próximos mantenimientos",
+	"Status Notifications": "Notificaciones de estado",
+	"Players per Platform": "Jugadores por plataforma",
+	"Game Comparison":      "Comparación de juegos",
+
+	// Themes
+	"Dark":                "Oscuro",
+	"Light":               "Claro",
+	"High contrast":       "Alto contraste",
+	"Aldmeri Dominion":    "Dominio Aldmeri",
+	"Daggerfall Covenant": "Pacto de Daggerfall",
+	"Ebonheart Pact":      "Pacto del Pecho de Ébano",
+
+	// Player counts
+	"Download history:":                 "Descargar historial:",
+	"Download the player history as %s": "Descargar el historial de jugadores en %s",
+	"CSV":                               "CSV",
+	"JSON":                              "JSON",
+	"vs 1h ago":                         "vs hace 1 h",
+	"vs yesterday":                      "vs ayer",
+	"Game":                              "Juego",
+
+	// Population
+	"Error loading population estimates": "Error al cargar las estimaciones de población",
+	"Source: %s":                         "Fuente: %s",
+	"high confidence":                    "fiabilidad alta",
+	"medium confidence":                  "fiabilidad media",
+	"low confidence":                     "fiabilidad baja",
+
+	// Server status
+	"All servers operational":     "Todos los servidores operativos",
+	"Minor issues detected":       "Problemas menores detectados",
+	"Server maintenance ongoing":  "Mantenimiento de servidores en curso",
+	"Critical failure detected":   "Fallo crítico detectado",
+	"Error loading server status": "Error al cargar el estado de los servidores",
+	"All %d other regions OK":     "Las otras %d regiones están bien",
+	"Collapse other regions":      "Contraer otras regiones",
+	"Show %d hidden regions":      "Mostrar %d regiones ocultas",
+	"Hide hidden regions":         "Ocultar regiones ocultas",
+	"Last updated %s":             "Última actualización: %s",
+	"Hide region":                 "Ocultar región",
+	"Unhide":                      "Volver a mostrar",
+	"Unhide region":               "Volver a mostrar la región",
+	"Star region":                 "Destacar región",
+	"Online":                      "En línea",
+	"Offline":                     "Fuera de línea",
+	"Maintenance":                 "Mantenimiento",
+	"Unknown":                     "Desconocido",
+	"Since %s":                    "Desde %s",
+	"Disagreement":                "Discrepancia",
+	"%s reports %s":               "%s indica %s",
+
+	// Maintenance
+	"No maintenance announced":                     "No hay mantenimiento anunciado",
+	"Subscribe to the maintenance calendar (.ics)": "Suscribirse al calendario de mantenimiento (.ics)",
+	"ongoing": "en curso",
+	"in %s":   "en %s",
+
+	// News
+	"Published on %s":         "Publicado el %s",
+	"Error fetching RSS feed": "Error al obtener el feed RSS",
+	"Error parsing RSS feed":  "Error al procesar el feed RSS",
+	"News language":           "Idioma de las noticias",
+	"(opens in a new tab)":    "(se abre en una pestaña nueva)",
+
+	// Push notifications
+	"Push notifications aren't available on this server": "Las notificaciones push no están disponibles en este servidor",
+	"Enable notifications":                               "Activar notificaciones",
+	"Update regions":                                     "Actualizar regiones",
+	"Get notified when a region goes down or comes back. Leave every region unticked to watch all of them.": "Recibe un aviso cuando una región se caiga o vuelva. Deja todas las regiones sin marcar para vigilarlas todas.",
+	"Disable notifications":             "Desactivar notificaciones",
+	"Error enabling notifications: %v":  "Error al activar las notificaciones: %v",
+	"Notifications enabled":             "Notificaciones activadas",
+	"Error disabling notifications: %v": "Error al desactivar las notificaciones: %v",
+	"Notifications disabled":            "Notificaciones desactivadas",
+}
@@ -0,0 +1,115 @@
+package i18n
+
+// fr are the French translations.
+var fr = map[string]string{
+	// Dashboard
+	"Elder Scrolls Online":   "Elder Scrolls Online",
+	"Customize layout":       "Personnaliser la disposition",
+	"Done":                   "Terminé",
+	"Reset layout":           "Réinitialiser la disposition",
+	"Move earlier":           "Déplacer avant",
+	"Move later":             "Déplacer après",
+	"Narrower":               "Plus étroit",
+	"Wider":                  "Plus large",
+	"Hide card":              "Masquer la carte",
+	"Show card":              "Afficher la carte",
+	"Theme":                  "Thème",
+	"Language":               "Langue",
+	"Loading...":             "Chargement…",
+	"Unreachable":            "Injoignable",
+	"Show":                   "Afficher",
+	"Hide":                   "Masquer",
+	"Skip to the cards":      "Aller aux cartes",
+	"Pause background video": "Mettre en pause la vidéo de fond",
+	"Play background video":  "Lire la vidéo de fond",
+	"Lite mode":              "Mode allégé",
+	"Save data: no background video, smaller images and less frequent updates": "Économiser les données : pas de vidéo de fond, images plus petites et mises à jour moins fréquentes",
+	"Offline — data from %s":                               "Hors ligne — données du %s",
+	"Offline — the data refreshes once you're back online": "Hors ligne — les données seront actualisées dès le retour de la connexion",
+	"Couldn't refresh — data from %s":                      "Actualisation impossible — données du %s",
+	"Install app":                                          "Installer l'application",
+	"Read-only copy — data from %s":                        "Copie en lecture seule — données du %s",
+	"Read-only copy":                                       "Copie en lecture seule",
+
+	// Widgets
+	"ESO Server Status":    "État des serveurs ESO",
+	"Latest ESO News":      "Dernières actualités ESO",
+	"Active Users":         "Joueurs actifs",
+	"24 Hour Peak":         "Pic sur 24 heures",
+	"All-Time Peak":        "Record absolu",
+	"Upcoming Maintenance": "Maintenances à venir",
+	"Status Notifications": "Notifications d'état",
+	"Players per Platform": "Joueurs par plateforme",
+	"Game Comparison":      "Comparaison des jeux",
+
+	// Themes
+	"Dark":                "Sombre",
+	"Light":               "Clair",
+	"High contrast":       "Contraste élevé",
+	"Aldmeri Dominion":    "Domaine aldmeri",
+	"Daggerfall Covenant": "Pacte de Daguefilante",
+	"Ebonheart Pact":      "Pacte de Cœurébène",
+
+	// Player counts
+	"Download history:":                 "Télécharger l'historique :",
+	"Download the player history as %s": "Télécharger l'historique des joueurs en %s",
+	"CSV":                               "CSV",
+	"JSON":                              "JSON",
+	"vs 1h ago":                         "vs il y a 1 h",
+	"vs yesterday":                      "vs hier",
+	"Game":                              "Jeu",
+
+	// Population
+	"Error loading population estimates": "Erreur lors du chargement des estimations de population",
+	"Source: %s":                         "Source : %s",
+	"high confidence":                    "fiabilité élevée",
+	"medium confidence":                  "fiabilité moyenne",
+	"low confidence":                     "fiabilité faible",
+
+	// Server status
+	"All servers operational":     "Tous les serveurs sont opérationnels",
+	"Minor issues detected":       "Problèmes mineurs détectés",
+	"Server maintenance ongoing":  "Maintenance des serveurs en cours",
+	"Critical failure detected":   "Panne critique détectée",
+	"Error loading server status": "Erreur lors du chargement de l'état des serveurs",
+	"All %d other regions OK":     "Les %d autres régions sont OK",
+	"Collapse other regions":      "Replier les autres régions",
+	"Show %d hidden regions":      "Afficher %d régions masquées",
+	"Hide hidden regions":         "Cacher les régions masquées",
+	"Last updated %s":             "Dernière mise à jour : %s",
+	"Hide region":                 "Masquer la région",
+	"Unhide":                      "Réafficher",
+	"Unhide region":               "Réafficher la région",
+	"Star region":                 "Épingler la région",
+	"Online":                      "En ligne",
+	"Offline":                     "Hors ligne",
+	"Maintenance":                 "Maintenance",
+	"Unknown":                     "Inconnu",
+	"Since %s":                    "Depuis le %s",
+	"Disagreement":                "Désaccord",
+	"%s reports %s":               "%s indique %s",
+
+	// Maintenance
+	"No maintenance announced":                     "Aucune maintenance annoncée",
+	"Subscribe to the maintenance calendar (.ics)": "S'abonner au calendrier des maintenances (.ics)",
+	"ongoing": "en cours",
+	"in %s":   "dans %s",
+
+	// News
+	"Published on %s":         "Publié le %s",
+	"Error fetching RSS feed": "Erreur lors de la récupération du flux RSS",
+	"Error parsing RSS feed":  "Erreur lors de la lecture du flux RSS",
+	"News language":           "Langue des actualités",
+	"(opens in a new tab)":    "(s'ouvre dans un nouvel onglet)",
+
+	// Push notifications
+	"Push notifications aren't available on this server": "Les notifications push ne sont pas disponibles sur ce serveur",
+	"Enable notifications":                               "Activer les notifications",
+	"Update regions":                                     "Mettre à jour les régions",
+	"Get notified when a region goes down or comes back. Leave every region unticked to watch all of them.": "Soyez averti quand une région tombe ou revient. Ne cochez aucune région pour les surveiller toutes.",
+	"Disable notifications":             "Désactiver les notifications",
+	"Error enabling notifications: %v":  "Erreur lors de l'activation des notifications : %v",
+	"Notifications enabled":             "Notifications activées",
+	"Error disabling notifications: %v": "Erreur lors de la désactivation des notifications : %v",
+	"Notifications disabled":            "Notifications désactivées",
+}
@@ -0,0 +1,4 @@
+package i18n
+
+// Catalogs exposes the translations to the tests.
+var Catalogs = catalogs
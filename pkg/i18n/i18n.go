@@ -0,0 +1,90 @@
+// Package i18n translates the dashboard texts and formats dates for the
+// languages supported by ESO. Messages are looked up by their English text,
+// so untranslated messages simply stay English.
+package i18n
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Default is the language used when no supported language is asked for.
+const Default = "en"
+
+// Language is a supported language.
+type Language struct {
+	// Code is the ISO 639-1 code, e.g. "de".
+	Code string
+	// Name is the name of the language in itself, e.g. "Deutsch".
+	Name string
+}
+
+// Languages are the supported languages, the ones ESO is available in.
+var Languages = []Language{
+	{Code: "en", Name: "English"},
+	{Code: "de", Name: "Deutsch"},
+	{Code: "fr", Name: "Français"},
+	{Code: "es", Name: "Español"},
+}
+
+// catalogs maps a language to its translations of the English messages.
+var catalogs = map[string]map[string]string{
+	"de": de,
+	"fr": fr,
+	"es": es,
+}
+
+// Supported reports whether the language code is supported.
+func Supported(code string) bool {
+	return slices.ContainsFunc(Languages, func(l Language) bool { return l.Code == code })
+}
+
+// Match returns the supported language of a tag such as "de-AT", or "" if it
+// isn't supported.
+func Match(tag string) string {
+	code, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	code, _, _ = strings.Cut(code, "_")
+	if Supported(code) {
+		return code
+	}
+	return ""
+}
+
+// Negotiate picks the supported language the client prefers most from an
+// Accept-Language header, e.g. "fr-CH, fr;q=0.9, en;q=0.8". It returns
+// Default when none is supported.
+func Negotiate(acceptLanguage string) string {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && name == "q" {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		// Earlier tags win ties, as browsers list them by preference.
+		if code := Match(tag); code != "" && q > bestQ {
+			best, bestQ = code, q
+		}
+	}
+	return best
+}
+
+// T translates the English message into the language. With args the message
+// is a fmt format, e.g. T("de", "All %d other regions OK", 6).
+func T(lang, message string, args ...any) string {
+	if translated, ok := catalogs[lang][message]; ok {
+		message = translated
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
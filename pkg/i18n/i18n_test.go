@@ -0,0 +1,159 @@
+package i18n_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
+)
+
+func TestMatch(t *testing.T) {
+	tests := map[string]string{
+		"de":     "de",
+		"de-AT":  "de",
+		"DE_at":  "de",
+		" fr-CH": "fr",
+		"en-US":  "en",
+		"es-419": "es",
+		"pt-BR":  "",
+		"ja":     "",
+		"*":      "",
+		"":       "",
+	}
+	for tag, want := range tests {
+		if got := i18n.Match(tag); got != want {
+			t.Errorf("Match(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: i18n.Default},
+		{header: "de-AT", want: "de"},
+		{header: "fr-CH, fr;q=0.9, en;q=0.8", want: "fr"},
+		{header: "en;q=0.5, es;q=0.8", want: "es"},
+		{header: "pt-BR, ja;q=0.9, de;q=0.5", want: "de"},
+		{header: "pt-BR, ja", want: i18n.Default},
+		{header: "*", want: i18n.Default},
+		// Earlier tags win ties.
+		{header: "es, de", want: "es"},
+		{header: "de;q=0.7, fr;q=0.7", want: "de"},
+		// q=0 means not acceptable.
+		{header: "de;q=0", want: i18n.Default},
+		{header: "de;q=0, fr;q=0.1", want: "fr"},
+		// Invalid q-values count as 1.
+		{header: "en;q=0.9, de;q=high", want: "de"},
+		{header: "de-DE;level=1;q=0.4, fr;q=0.3", want: "de"},
+	}
+	for _, tt := range tests {
+		if got := i18n.Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := i18n.T("de", "Language"); got != "Sprache" {
+		t.Errorf("T(de) = %q, want the translation", got)
+	}
+	if got := i18n.T("de", "Download the player history as %s", "CSV"); got != "Spielerverlauf als CSV herunterladen" {
+		t.Errorf("T(de) with args = %q, want the formatted translation", got)
+	}
+	for _, lang := range []string{"en", "ja", ""} {
+		if got := i18n.T(lang, "Language"); got != "Language" {
+			t.Errorf("T(%q) = %q, want the English message", lang, got)
+		}
+	}
+	if got := i18n.T("fr", "Not translated %d", 3); got != "Not translated 3" {
+		t.Errorf("T() of an unknown message = %q, want it formatted in English", got)
+	}
+}
+
+// verbs matches the fmt verbs of a message.
+var verbs = regexp.MustCompile(`%[-+# 0-9.\[\]]*[a-zA-Z%]`)
+
+func TestCatalogsAreComplete(t *testing.T) {
+	used := messages(t)
+	for _, language := range i18n.Languages {
+		if language.Code == i18n.Default {
+			continue
+		}
+		catalog, ok := i18n.Catalogs[language.Code]
+		if !ok {
+			t.Errorf("%s has no catalog", language.Code)
+			continue
+		}
+		for message, translated := range catalog {
+			want, got := verbs.FindAllString(message, -1), verbs.FindAllString(translated, -1)
+			slices.Sort(want)
+			slices.Sort(got)
+			if !slices.Equal(got, want) {
+				t.Errorf("%s translation of %q has the verbs %v, want %v", language.Code, message, got, want)
+			}
+		}
+		for _, message := range used {
+			if _, ok := catalog[message]; !ok {
+				t.Errorf("%s catalog misses %q", language.Code, message)
+			}
+		}
+	}
+}
+
+// messages returns the literal messages the repository translates with T.
+func messages(t *testing.T) []string {
+	t.Helper()
+	var found []string
+	for _, dir := range []string{"../../pkg", "../../cmd"} {
+		err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+			file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+			if err != nil {
+				return err
+			}
+			ast.Inspect(file, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok || len(call.Args) < 2 {
+					return true
+				}
+				fn, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || fn.Sel.Name != "T" {
+					return true
+				}
+				if pkg, ok := fn.X.(*ast.Ident); !ok || pkg.Name != "i18n" {
+					return true
+				}
+				if literal, ok := call.Args[1].(*ast.BasicLit); ok && literal.Kind == token.STRING {
+					message, err := strconv.Unquote(literal.Value)
+					if err != nil {
+						t.Errorf("%s: %v", path, err)
+					}
+					found = append(found, message)
+				}
+				return true
+			})
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(found) == 0 {
+		t.Fatal("found no messages in the repository")
+	}
+	slices.Sort(found)
+	return slices.Compact(found)
+}
@@ -0,0 +1,43 @@
+package i18n
+
+import "time"
+
+// dateLayouts are the date layouts of the languages.
+var dateLayouts = map[string]string{
+	"en": "2006-01-02",
+	"de": "02.01.2006",
+	"fr": "02/01/2006",
+	"es": "02/01/2006",
+}
+
+// weekdays are the abbreviated weekday names of the languages, Sunday first.
+var weekdays = map[string][7]string{
+	"en": {"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	"de": {"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+	"fr": {"dim", "lun", "mar", "mer", "jeu", "ven", "sam"},
+	"es": {"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+}
+
+// FormatDate formats the date of t in the language, e.g. "24.12.2025".
+func FormatDate(lang string, t time.Time) string {
+	layout, ok := dateLayouts[lang]
+	if !ok {
+		layout = dateLayouts[Default]
+	}
+	return t.Format(layout)
+}
+
+// FormatDateTime formats the date and time of t in the language, e.g.
+// "24.12.2025 18:30". All supported languages use a 24-hour clock.
+func FormatDateTime(lang string, t time.Time) string {
+	return FormatDate(lang, t) + " " + t.Format("15:04")
+}
+
+// Weekday returns the abbreviated weekday name of t in the language.
+func Weekday(lang string, t time.Time) string {
+	names, ok := weekdays[lang]
+	if !ok {
+		names = weekdays[Default]
+	}
+	return names[t.Weekday()]
+}
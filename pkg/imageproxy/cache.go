@@ -0,0 +1,124 @@
+package imageproxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultCacheSize is the size of the disk cache when none is configured, in bytes.
+const DefaultCacheSize = 100 << 20
+
+// Cache keeps scaled images on disk. When it grows beyond MaxBytes the least
+// recently served images are removed.
+type Cache struct {
+	dir      string
+	maxBytes int64
+	mu       sync.Mutex
+}
+
+// OpenCache opens the cache in dir, creating the directory if needed.
+func OpenCache(dir string, maxBytes int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("creating image cache: %w", err)
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultCacheSize
+	}
+	return &Cache{dir: dir, maxBytes: maxBytes}, nil
+}
+
+// cacheKey names the cached file of a source image scaled to width.
+func cacheKey(source string, width int) string {
+	sum := sha256.Sum256([]byte(source + "\n" + strconv.Itoa(width)))
+	return hex.EncodeToString(sum[:]) + ".jpg"
+}
+
+// serve writes the cached image to w and reports whether it was cached.
+// Serving an image marks it as recently used.
+func (c *Cache) serve(w http.ResponseWriter, r *http.Request, key string) bool {
+	path := filepath.Join(c.dir, key)
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	// The modification time tracks the last use, so the ETag identifies the
+	// image instead.
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+
+	setImageHeaders(w, key)
+	http.ServeContent(w, r, "", time.Time{}, file)
+	return true
+}
+
+// put stores an image and removes the least recently used ones beyond the
+// size limit.
+func (c *Cache) put(key string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tmp, err := os.CreateTemp(c.dir, "*.tmp")
+	if err != nil {
+		return fmt.Errorf("caching image: %w", err)
+	}
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("caching image: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("caching image: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), filepath.Join(c.dir, key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("caching image: %w", err)
+	}
+
+	return c.evict()
+}
+
+// evict removes the least recently used images until the cache fits in
+// maxBytes.
+func (c *Cache) evict() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("reading image cache: %w", err)
+	}
+
+	files := make([]os.FileInfo, 0, len(entries))
+	var size int64
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != ".jpg" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, info)
+		size += info.Size()
+	}
+
+	slices.SortFunc(files, func(a, b os.FileInfo) int { return a.ModTime().Compare(b.ModTime()) })
+	for _, info := range files {
+		if size <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(filepath.Join(c.dir, info.Name())); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("evicting cached image: %w", err)
+		}
+		size -= info.Size()
+	}
+	return nil
+}
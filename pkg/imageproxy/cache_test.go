@@ -0,0 +1,56 @@
+package imageproxy_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/imageproxy"
+)
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	body := pngImage(t, 400, 200)
+	sources := map[string]source{}
+	for _, name := range []string{"a", "b", "c"} {
+		sources["https://eso-hub.com/"+name+".png"] = source{contentType: "image/png", body: body}
+	}
+	// The images are alike, so the cache has room for two and a half.
+	uncached, _ := newHandler(sources, nil)
+	size := int64(get(uncached, "https://eso-hub.com/a.png", "320").Body.Len())
+
+	dir := t.TempDir()
+	cache, err := imageproxy.OpenCache(dir, 2*size+size/2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, stub := newHandler(sources, cache)
+	get(handler, "https://eso-hub.com/a.png", "320")
+	get(handler, "https://eso-hub.com/b.png", "320")
+	// Serving a from the cache makes b the least recently used.
+	get(handler, "https://eso-hub.com/a.png", "320")
+	get(handler, "https://eso-hub.com/c.png", "320")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("cache holds %d images, want 2", len(entries))
+	}
+
+	stub.requests = 0
+	get(handler, "https://eso-hub.com/a.png", "320")
+	get(handler, "https://eso-hub.com/c.png", "320")
+	if stub.requests != 0 {
+		t.Errorf("fetched %d of the recently used images again", stub.requests)
+	}
+	get(handler, "https://eso-hub.com/b.png", "320")
+	if stub.requests != 1 {
+		t.Error("the least recently used image wasn't evicted")
+	}
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != ".jpg" {
+			t.Errorf("left %s in the cache", entry.Name())
+		}
+	}
+}
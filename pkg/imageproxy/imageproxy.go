@@ -0,0 +1,239 @@
+// Package imageproxy serves images of other hosts through the dashboard
+// server, scaled down to the size they are shown at and cached on disk, so
+// news thumbnails don't cost the full image, browsers don't contact the image
+// hosts and cached images load without them.
+package imageproxy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	// Decoders of the formats news thumbnails come in.
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+)
+
+const (
+	// maxSourceSize is the largest source image fetched, in bytes.
+	maxSourceSize = 10 << 20
+	// maxSourcePixels is the largest source image decoded, so small files
+	// claiming huge dimensions can't exhaust the memory. 12 MP are a 4000x3000
+	// photo, about 48 MB once decoded.
+	maxSourcePixels = 12_000_000
+	// jpegQuality is the quality images are re-encoded with.
+	jpegQuality = 80
+	// maxRedirects is the number of redirects followed to a source image.
+	maxRedirects = 5
+)
+
+// DefaultHosts are the hosts images are fetched from when Handler.Hosts is
+// unset: eso-hub, where the news come from, and the official ESO sites.
+var DefaultHosts = []string{
+	"eso-hub.com",
+	"elderscrollsonline.com",
+	"esosslfiles-a.akamaihd.net",
+}
+
+// DefaultWidths are the widths images are served at when Handler.Widths is
+// unset, the ones the news card asks for.
+var DefaultWidths = []int{constant.LiteThumbnailWidth, constant.ThumbnailWidth}
+
+// contentTypes are the image types accepted from source hosts.
+var contentTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+// genericContentTypes are declared by hosts that don't know the type, the
+// content decides then.
+var genericContentTypes = []string{"", "application/octet-stream", "binary/octet-stream"}
+
+// Handler serves GET /img?url=<image URL>&w=<width> as a JPEG at most width
+// pixels wide. Only a few widths are served, so a client can't fill the cache
+// with a copy of an image per width.
+type Handler struct {
+	// Client fetches the source images, a client with constant.FetchTimeout if nil.
+	Client *http.Client
+	// Hosts are the hosts images may be fetched from, including their
+	// subdomains. DefaultHosts if nil.
+	Hosts []string
+	// Widths are the widths that may be asked for, DefaultWidths if nil.
+	Widths []int
+	// Cache keeps the scaled images, nothing is cached if nil.
+	Cache *Cache
+}
+
+// errNotAllowed is returned for source URLs the proxy must not fetch.
+var errNotAllowed = errors.New("image host not allowed")
+
+// ServeHTTP fetches, scales and serves the image.
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	source, err := h.source(r.URL.Query().Get("url"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	width, err := h.width(r.URL.Query().Get("w"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	key := cacheKey(source.String(), width)
+	if h.Cache != nil && h.Cache.serve(w, r, key) {
+		return
+	}
+
+	img, err := h.fetch(r, source)
+	if err != nil {
+		log.Println("Error proxying image:", err)
+		http.Error(w, "image unavailable", http.StatusBadGateway)
+		return
+	}
+
+	var scaled bytes.Buffer
+	if err = jpeg.Encode(&scaled, Resize(img, width), &jpeg.Options{Quality: jpegQuality}); err != nil {
+		log.Println("Error encoding image:", err)
+		http.Error(w, "image unavailable", http.StatusInternalServerError)
+		return
+	}
+	if h.Cache != nil {
+		if err = h.Cache.put(key, scaled.Bytes()); err != nil {
+			log.Println("Error caching image:", err)
+		}
+	}
+
+	setImageHeaders(w, key)
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(scaled.Bytes()))
+}
+
+// setImageHeaders marks a scaled image as cacheable for a week. Its URL names
+// the source and width, so it never changes and browsers can show it offline.
+func setImageHeaders(w http.ResponseWriter, key string) {
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "public, max-age=604800, immutable")
+	w.Header().Set("ETag", `"`+strings.TrimSuffix(key, ".jpg")+`"`)
+}
+
+// checkContentType accepts images whose content is one of contentTypes, as
+// long as the host doesn't declare another type.
+func checkContentType(declared string, body []byte) error {
+	declared, _, _ = strings.Cut(declared, ";")
+	declared = strings.ToLower(strings.TrimSpace(declared))
+	if !slices.Contains(contentTypes, declared) && !slices.Contains(genericContentTypes, declared) {
+		return fmt.Errorf("unsupported content type %q", declared)
+	}
+
+	if sniffed := http.DetectContentType(body); !slices.Contains(contentTypes, sniffed) {
+		return fmt.Errorf("content is %q, not an image", sniffed)
+	}
+	return nil
+}
+
+// source parses the source URL and checks its host is allowed.
+func (h Handler) source(raw string) (*url.URL, error) {
+	source, err := url.Parse(raw)
+	if err != nil || (source.Scheme != "https" && source.Scheme != "http") || source.Host == "" {
+		return nil, fmt.Errorf("invalid image URL %q", raw)
+	}
+
+	hosts := h.Hosts
+	if hosts == nil {
+		hosts = DefaultHosts
+	}
+	host := strings.ToLower(source.Hostname())
+	if !slices.ContainsFunc(hosts, func(allowed string) bool {
+		return host == allowed || strings.HasSuffix(host, "."+allowed)
+	}) {
+		return nil, fmt.Errorf("%w: %s", errNotAllowed, host)
+	}
+	return source, nil
+}
+
+// width parses the width asked for, which must be one of Widths. Without
+// one the image is served at the widest.
+func (h Handler) width(raw string) (int, error) {
+	widths := h.Widths
+	if widths == nil {
+		widths = DefaultWidths
+	}
+	if raw == "" {
+		return slices.Max(widths), nil
+	}
+
+	width, err := strconv.Atoi(raw)
+	if err != nil || !slices.Contains(widths, width) {
+		return 0, fmt.Errorf("invalid width %q, expected one of %v", raw, widths)
+	}
+	return width, nil
+}
+
+// fetch downloads and decodes the source image. Redirects must stay on the
+// allowed hosts too.
+func (h Handler) fetch(r *http.Request, source *url.URL) (image.Image, error) {
+	client := http.Client{Timeout: constant.FetchTimeout}
+	if h.Client != nil {
+		client = *h.Client
+	}
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return errors.New("too many redirects")
+		}
+		_, err := h.source(req.URL.String())
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, source.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: status %d", source, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSourceSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", source, err)
+	}
+	if len(body) > maxSourceSize {
+		return nil, fmt.Errorf("fetching %s: image larger than %d bytes", source, maxSourceSize)
+	}
+	if err = checkContentType(resp.Header.Get("Content-Type"), body); err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", source, err)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", source, err)
+	}
+	if config.Width*config.Height > maxSourcePixels {
+		return nil, fmt.Errorf("decoding %s: %dx%d image too large", source, config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", source, err)
+	}
+	return img, nil
+}
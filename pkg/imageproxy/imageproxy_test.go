@@ -0,0 +1,177 @@
+package imageproxy_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/imageproxy"
+)
+
+// source is an image served by the stub host.
+type source struct {
+	contentType string
+	body        []byte
+}
+
+// hosts serves the sources by URL and counts the requests made.
+type hosts struct {
+	mu       sync.Mutex
+	sources  map[string]source
+	requests int
+}
+
+// RoundTrip serves the source of the URL, 404 for unknown ones.
+func (h *hosts) RoundTrip(req *http.Request) (*http.Response, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.requests++
+
+	recorder := httptest.NewRecorder()
+	src, ok := h.sources[req.URL.String()]
+	if !ok {
+		recorder.WriteHeader(http.StatusNotFound)
+	} else {
+		recorder.Header().Set("Content-Type", src.contentType)
+		_, _ = recorder.Write(src.body)
+	}
+	resp := recorder.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// pngImage encodes a uniform PNG of the size.
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 200
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+// newHandler returns a handler fetching from the sources, caching in cache.
+func newHandler(sources map[string]source, cache *imageproxy.Cache) (imageproxy.Handler, *hosts) {
+	stub := &hosts{sources: sources}
+	return imageproxy.Handler{Client: &http.Client{Transport: stub}, Hosts: []string{"eso-hub.com"}, Cache: cache}, stub
+}
+
+// get asks the handler for the image at width.
+func get(handler http.Handler, src, width string) *httptest.ResponseRecorder {
+	target := "/img?url=" + url.QueryEscape(src)
+	if width != "" {
+		target += "&w=" + width
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder
+}
+
+func TestHandlerScales(t *testing.T) {
+	const src = "https://cdn.eso-hub.com/news/1.png"
+	handler, _ := newHandler(map[string]source{src: {contentType: "image/png", body: pngImage(t, 1000, 500)}}, nil)
+
+	for width, want := range map[string]image.Point{"320": {320, 160}, "640": {640, 320}, "": {640, 320}} {
+		resp := get(handler, src, width)
+		if resp.Code != http.StatusOK || resp.Header().Get("Content-Type") != "image/jpeg" {
+			t.Fatalf("w=%s: status %d, Content-Type %q", width, resp.Code, resp.Header().Get("Content-Type"))
+		}
+		config, _, err := image.DecodeConfig(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (image.Point{config.Width, config.Height}) != want {
+			t.Errorf("w=%s: %dx%d, want %v", width, config.Width, config.Height, want)
+		}
+	}
+}
+
+func TestHandlerRejects(t *testing.T) {
+	const allowed = "https://eso-hub.com/news/1.png"
+	handler, stub := newHandler(map[string]source{allowed: {contentType: "image/png", body: pngImage(t, 10, 10)}}, nil)
+
+	tests := []struct {
+		name, src, width string
+	}{
+		{name: "other host", src: "https://example.com/1.png"},
+		{name: "suffix without dot", src: "https://evil-eso-hub.com/1.png"},
+		{name: "host in the path", src: "https://example.com/eso-hub.com/1.png"},
+		{name: "scheme", src: "file:///etc/passwd"},
+		{name: "no host", src: "/news/1.png"},
+		{name: "width not served", src: allowed, width: "500"},
+		{name: "width larger", src: allowed, width: "1280"},
+		{name: "width not a number", src: allowed, width: "wide"},
+	}
+	for _, tt := range tests {
+		if resp := get(handler, tt.src, tt.width); resp.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want %d", tt.name, resp.Code, http.StatusBadRequest)
+		}
+	}
+	if stub.requests > 0 {
+		t.Errorf("fetched %d images of rejected requests", stub.requests)
+	}
+}
+
+func TestHandlerRejectsSources(t *testing.T) {
+	small := pngImage(t, 10, 10)
+	tooLarge := append(append([]byte{}, small...), make([]byte, 10<<20)...)
+	sources := map[string]source{
+		"https://eso-hub.com/html.png":      {contentType: "text/html", body: small},
+		"https://eso-hub.com/svg.png":       {contentType: "image/svg+xml", body: []byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`)},
+		"https://eso-hub.com/script.png":    {contentType: "image/png", body: []byte("<html><script>alert(1)</script></html>")},
+		"https://eso-hub.com/too-large.png": {contentType: "image/png", body: tooLarge},
+		// Compresses to a small file but has 16 MP.
+		"https://eso-hub.com/too-many-pixels.png": {contentType: "image/png", body: pngImage(t, 4000, 4000)},
+		"https://eso-hub.com/generic.png":         {contentType: "application/octet-stream", body: small},
+	}
+	handler, _ := newHandler(sources, nil)
+
+	for src := range sources {
+		want := http.StatusBadGateway
+		if strings.HasSuffix(src, "generic.png") {
+			want = http.StatusOK
+		}
+		if resp := get(handler, src, "320"); resp.Code != want {
+			t.Errorf("%s: status %d, want %d", src, resp.Code, want)
+		}
+	}
+	if resp := get(handler, "https://eso-hub.com/missing.png", "320"); resp.Code != http.StatusBadGateway {
+		t.Errorf("missing image: status %d, want %d", resp.Code, http.StatusBadGateway)
+	}
+}
+
+func TestHandlerCaches(t *testing.T) {
+	const src = "https://eso-hub.com/news/1.png"
+	cache, err := imageproxy.OpenCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, stub := newHandler(map[string]source{src: {contentType: "image/png", body: pngImage(t, 800, 400)}}, cache)
+
+	first := get(handler, src, "320")
+	second := get(handler, src, "320")
+	if first.Code != http.StatusOK || second.Code != http.StatusOK {
+		t.Fatalf("status %d and %d", first.Code, second.Code)
+	}
+	if stub.requests != 1 {
+		t.Errorf("fetched the image %d times, want once", stub.requests)
+	}
+	if !bytes.Equal(first.Body.Bytes(), second.Body.Bytes()) || first.Header().Get("ETag") != second.Header().Get("ETag") {
+		t.Error("the cached image differs from the scaled one")
+	}
+
+	get(handler, src, "640")
+	if stub.requests != 2 {
+		t.Errorf("fetched the image %d times, want once per width", stub.requests)
+	}
+}
@@ -0,0 +1,71 @@
+package imageproxy
+
+import (
+	"image"
+	"image/color"
+)
+
+// background is the colour transparent pixels are flattened onto, the card
+// background of the dark theme.
+var background = color.RGBA{R: 20, G: 20, B: 20, A: 255}
+
+// Resize scales src down to width pixels, keeping its aspect ratio, onto an
+// opaque background as JPEG has no transparency. Each destination pixel
+// averages the source pixels it covers, which keeps thumbnails smooth without
+// external packages. The source is read in place, so no full-size copy is
+// made. Images that are already narrow enough keep their size.
+func Resize(src image.Image, width int) *image.RGBA {
+	bounds := src.Bounds()
+	if width <= 0 || width >= bounds.Dx() {
+		width = bounds.Dx()
+	}
+	height := max(1, bounds.Dy()*width/bounds.Dx())
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := range width {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb := opaqueAt(src, sx, sy)
+					r += pr
+					g += pg
+					b += pb
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: 255})
+		}
+	}
+	return dst
+}
+
+// opaqueAt returns the 8 bit colour of a pixel of src flattened onto the
+// background. The types the decoders return are read without allocating.
+func opaqueAt(src image.Image, x, y int) (r, g, b uint32) {
+	switch img := src.(type) {
+	case *image.YCbCr:
+		c := img.YCbCrAt(x, y)
+		cr, cg, cb := color.YCbCrToRGB(c.Y, c.Cb, c.Cr)
+		return uint32(cr), uint32(cg), uint32(cb)
+	case *image.RGBA:
+		c := img.RGBAAt(x, y)
+		return over(uint32(c.R)*0x101, uint32(c.G)*0x101, uint32(c.B)*0x101, uint32(c.A)*0x101)
+	default:
+		return over(src.At(x, y).RGBA())
+	}
+}
+
+// over composites a 16 bit alpha-premultiplied colour onto the background and
+// returns it with 8 bits per channel.
+func over(r, g, b, a uint32) (uint32, uint32, uint32) {
+	rest := 0xffff - a
+	return (r + uint32(background.R)*0x101*rest/0xffff) >> 8,
+		(g + uint32(background.G)*0x101*rest/0xffff) >> 8,
+		(b + uint32(background.B)*0x101*rest/0xffff) >> 8
+}
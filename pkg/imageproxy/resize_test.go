@@ -0,0 +1,34 @@
+package imageproxy_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/imageproxy"
+)
+
+func TestResizeFlattensTransparency(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(10, 10, 14, 12))
+	src.SetNRGBA(10, 10, color.NRGBA{R: 255, A: 255})
+
+	dst := imageproxy.Resize(src, 0)
+	if dst.Bounds() != image.Rect(0, 0, 4, 2) {
+		t.Fatalf("bounds = %v, want the size of the source at 0,0", dst.Bounds())
+	}
+	if got := dst.RGBAAt(0, 0); got != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("opaque pixel = %v", got)
+	}
+	if got := dst.RGBAAt(3, 1); got != (color.RGBA{R: 20, G: 20, B: 20, A: 255}) {
+		t.Errorf("transparent pixel = %v, want the background", got)
+	}
+
+	half := imageproxy.Resize(src, 2)
+	if half.Bounds() != image.Rect(0, 0, 2, 1) {
+		t.Fatalf("bounds = %v, want 2x1", half.Bounds())
+	}
+	// The red pixel averaged with three background ones.
+	if got := half.RGBAAt(0, 0); got != (color.RGBA{R: (255 + 3*20) / 4, G: 15, B: 15, A: 255}) {
+		t.Errorf("averaged pixel = %v", got)
+	}
+}
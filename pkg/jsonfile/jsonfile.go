@@ -0,0 +1,102 @@
+// Package jsonfile keeps values in JSON files that survive crashes: writes
+// never leave a partly written file behind and files that can't be decoded
+// are kept aside instead of stopping the server.
+package jsonfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// corruptTimeLayout stamps the backups of files that can't be decoded.
+const corruptTimeLayout = "20060102T150405.000000000"
+
+// Load decodes the file at path. A missing file yields the zero value. A
+// file that can't be decoded, e.g. cut short by a crash, is moved aside as
+// <path>.corrupt-<time> and yields the zero value too, so the server starts
+// empty instead of not at all.
+func Load[T any](path string) (T, error) {
+	value, err := Read[T](path)
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		return value, err
+	}
+
+	backup := path + ".corrupt-" + time.Now().UTC().Format(corruptTimeLayout)
+	if renameErr := os.Rename(path, backup); renameErr != nil {
+		return value, fmt.Errorf("%w, keeping a backup: %w", err, renameErr)
+	}
+	log.Printf("Error decoding %s, starting empty and keeping it as %s: %v", path, backup, decodeErr.Err)
+	return value, nil
+}
+
+// Read decodes the file at path without changing it, for readers next to a
+// running server. A missing file yields the zero value, one that can't be
+// decoded a *DecodeError.
+func Read[T any](path string) (T, error) {
+	var value T
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return value, nil
+	}
+	if err != nil {
+		return value, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err = json.Unmarshal(data, &value); err != nil {
+		var empty T
+		return empty, &DecodeError{Path: path, Err: err}
+	}
+	return value, nil
+}
+
+// DecodeError reports a file that can't be decoded.
+type DecodeError struct {
+	// Path is the file.
+	Path string
+	// Err is the error of the JSON decoder.
+	Err error
+}
+
+// Error names the file and the decoding error.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decoding %s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the error of the JSON decoder.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Write encodes v to a temporary file next to path, readable by the owner
+// only, and renames it over path, so a crash never leaves a partly written
+// file behind.
+func Write(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
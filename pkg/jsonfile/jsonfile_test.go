@@ -0,0 +1,99 @@
+package jsonfile_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/jsonfile"
+)
+
+func TestWriteLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "values.json")
+
+	values, err := jsonfile.Load[[]int](path)
+	if err != nil || values != nil {
+		t.Fatalf("Load() of a missing file = %v, %v, want nothing", values, err)
+	}
+
+	if err = jsonfile.Write(path, []int{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err = jsonfile.Write(path, []int{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if values, err = jsonfile.Load[[]int](path); err != nil || len(values) != 3 {
+		t.Errorf("Load() = %v, %v, want the last written values", values, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d files, want only the written one", len(entries))
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("file mode = %v, want readable by the owner only", perm)
+	}
+}
+
+func TestLoadCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "values.json")
+	if err := os.WriteFile(path, []byte("[1, 2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := jsonfile.Load[[]int](path)
+	if err != nil || values != nil {
+		t.Fatalf("Load() = %v, %v, want nothing and no error", values, err)
+	}
+	// A partly decoded value isn't returned either.
+	if err = os.WriteFile(path, []byte(`{"a": 1, "b": "x"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	object, err := jsonfile.Load[struct{ A, B int }](path)
+	if err != nil || object.A != 0 {
+		t.Errorf("Load() = %+v, %v, want the zero value", object, err)
+	}
+
+	backups, err := filepath.Glob(path + ".corrupt-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The second backup doesn't replace the first.
+	if len(backups) != 2 || !strings.HasPrefix(filepath.Base(backups[0]), "values.json.corrupt-") {
+		t.Errorf("backups = %v, want both corrupt files kept", backups)
+	}
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("the corrupt file is still at its path: %v", err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.json")
+	values, err := jsonfile.Read[[]int](path)
+	if err != nil || values != nil {
+		t.Fatalf("Read() of a missing file = %v, %v, want nothing", values, err)
+	}
+
+	if err = os.WriteFile(path, []byte("[1, 2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var decodeErr *jsonfile.DecodeError
+	if values, err = jsonfile.Read[[]int](path); !errors.As(err, &decodeErr) || values != nil {
+		t.Errorf("Read() = %v, %v, want a decode error", values, err)
+	}
+	// Readers leave the corrupt file to the server.
+	if data, err := os.ReadFile(path); err != nil || string(data) != "[1, 2" {
+		t.Errorf("the corrupt file was changed: %q, %v", data, err)
+	}
+}
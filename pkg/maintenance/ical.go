@@ -0,0 +1,78 @@
+package maintenance
+
+import (
+	"io"
+	"strings"
+	"time"
+)
+
+// ContentType is the MIME type of the iCalendar feed.
+const ContentType = "text/calendar; charset=utf-8"
+
+// icalTimeLayout is the UTC date-time layout of iCalendar (RFC 5545).
+const icalTimeLayout = "20060102T150405Z"
+
+// maxLineLength is the length at which iCalendar content lines are folded.
+const maxLineLength = 75
+
+// WriteICS writes the windows as an iCalendar feed stamped with now.
+func WriteICS(w io.Writer, windows []Window, now time.Time) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//go-eso-dashboard//Maintenance//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:ESO Maintenance",
+	}
+	for _, window := range windows {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+window.UID(),
+			"DTSTAMP:"+now.UTC().Format(icalTimeLayout),
+			"DTSTART:"+window.Start.UTC().Format(icalTimeLayout),
+		)
+		if !window.End.IsZero() {
+			lines = append(lines, "DTEND:"+window.End.UTC().Format(icalTimeLayout))
+		}
+		lines = append(lines,
+			"SUMMARY:"+escapeText(window.Title),
+			"DESCRIPTION:"+escapeText(window.Description),
+		)
+		if strings.HasPrefix(window.Source, "http") {
+			lines = append(lines, "URL:"+window.Source)
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldLine(line))
+		b.WriteString("\r\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeText escapes a TEXT value.
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// foldLine splits a content line into lines of at most 75 octets, continuing
+// with a leading space, without cutting UTF-8 sequences apart.
+func foldLine(line string) string {
+	var b strings.Builder
+	length := 0
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > maxLineLength {
+			b.WriteString("\r\n ")
+			length = 1
+		}
+		b.WriteRune(r)
+		length += size
+	}
+	return b.String()
+}
@@ -0,0 +1,116 @@
+package maintenance_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/maintenance"
+)
+
+// writeICS returns the feed of the windows.
+func writeICS(t *testing.T, windows ...maintenance.Window) string {
+	t.Helper()
+	var b strings.Builder
+	if err := maintenance.WriteICS(&b, windows, now); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+// unfold joins folded content lines again.
+func unfold(feed string) []string {
+	return strings.Split(strings.ReplaceAll(strings.TrimSuffix(feed, "\r\n"), "\r\n ", ""), "\r\n")
+}
+
+func TestWriteICS(t *testing.T) {
+	window := maintenance.Window{
+		Title:       "PC maintenance",
+		Description: "Megaservers down",
+		Source:      "https://example.com/news/1",
+		Start:       time.Date(2025, time.October, 20, 10, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+		End:         time.Date(2025, time.October, 20, 12, 0, 0, 0, time.UTC),
+	}
+	lines := unfold(writeICS(t, window, maintenance.Window{Title: "Console", Source: "status", Start: now}))
+
+	want := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//go-eso-dashboard//Maintenance//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:ESO Maintenance",
+		"BEGIN:VEVENT",
+		"UID:" + window.UID(),
+		"DTSTAMP:20251019T120000Z",
+		"DTSTART:20251020T080000Z",
+		"DTEND:20251020T120000Z",
+		"SUMMARY:PC maintenance",
+		"DESCRIPTION:Megaservers down",
+		"URL:https://example.com/news/1",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:" + maintenance.Window{Title: "Console", Start: now}.UID(),
+		"DTSTAMP:20251019T120000Z",
+		"DTSTART:20251019T120000Z",
+		"SUMMARY:Console",
+		"DESCRIPTION:",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("WriteICS() =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestWriteICSEscapes(t *testing.T) {
+	window := maintenance.Window{
+		Title:       `Patch; servers, \ clients`,
+		Description: "First line\r\nSecond line\nThird line",
+		Start:       now,
+	}
+	lines := unfold(writeICS(t, window))
+
+	for _, want := range []string{
+		`SUMMARY:Patch\; servers\, \\ clients`,
+		`DESCRIPTION:First line\nSecond line\nThird line`,
+	} {
+		found := false
+		for _, line := range lines {
+			found = found || line == want
+		}
+		if !found {
+			t.Errorf("WriteICS() has no line %q in %q", want, lines)
+		}
+	}
+}
+
+func TestWriteICSFolds(t *testing.T) {
+	window := maintenance.Window{
+		Title: "Maintenance",
+		// Multi-byte runes around the folding points must not be cut apart.
+		Description: strings.Repeat("Wartungsarbeiten für die Megaserver – ", 8),
+		Start:       now,
+	}
+	feed := writeICS(t, window)
+
+	for _, line := range strings.Split(strings.TrimSuffix(feed, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line %q has %d octets, want at most 75", line, len(line))
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line %q cuts a UTF-8 sequence apart", line)
+		}
+	}
+	if !strings.Contains(feed, "\r\n ") {
+		t.Error("WriteICS() didn't fold the long description")
+	}
+
+	want := "DESCRIPTION:" + window.Description
+	for _, line := range unfold(feed) {
+		if strings.HasPrefix(line, "DESCRIPTION:") && line != want {
+			t.Errorf("unfolded description = %q, want %q", line, want)
+		}
+	}
+}
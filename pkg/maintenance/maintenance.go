@@ -0,0 +1,136 @@
+// Package maintenance collects announced maintenance windows and exports them
+// as an iCalendar feed.
+package maintenance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+)
+
+// keyword marks text that is about maintenance.
+const keyword = "maintenance"
+
+// Window is an announced maintenance window.
+type Window struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// Source tells where the window was announced, e.g. a status provider or news link.
+	Source string    `json:"source"`
+	Start  time.Time `json:"start"`
+	// End is when the maintenance is expected to be over, zero if not announced.
+	End time.Time `json:"end"`
+}
+
+// UID returns a stable identifier for calendar clients.
+func (w Window) UID() string {
+	sum := sha256.Sum256([]byte(w.Start.UTC().Format(time.RFC3339) + "|" + w.Title))
+	return hex.EncodeToString(sum[:8]) + "@go-eso-dashboard"
+}
+
+// Ongoing reports whether the window is in progress at now.
+func (w Window) Ongoing(now time.Time) bool {
+	return !now.Before(w.Start) && !w.End.IsZero() && now.Before(w.End)
+}
+
+// FromAnnouncements converts status page announcements with a start time.
+func FromAnnouncements(source string, announcements []esostatus.Announcement) []Window {
+	var windows []Window
+	for _, announcement := range announcements {
+		if announcement.Start.IsZero() {
+			continue
+		}
+		windows = append(windows, Window{
+			Title:       "ESO maintenance",
+			Description: announcement.Message,
+			Source:      source,
+			Start:       announcement.Start,
+			End:         announcement.End,
+		})
+	}
+	return windows
+}
+
+// FromText extracts a window from a news article when it is tagged or titled
+// as maintenance and mentions when it starts.
+func FromText(title, text, source string, categories []string) (Window, bool) {
+	tagged := strings.Contains(strings.ToLower(title), keyword)
+	for _, category := range categories {
+		if strings.Contains(strings.ToLower(category), keyword) {
+			tagged = true
+		}
+	}
+	if !tagged {
+		return Window{}, false
+	}
+
+	times := esostatus.ParseTimes(title + " " + text)
+	if len(times) == 0 {
+		return Window{}, false
+	}
+
+	window := Window{Title: title, Description: text, Source: source, Start: times[0]}
+	if len(times) > 1 && times[1].After(window.Start) {
+		window.End = times[1]
+	}
+	return window, true
+}
+
+// Upcoming returns the windows that haven't ended at now, sorted by start and
+// without duplicates announced by several sources. Windows are the same when
+// they share their UID, so differently titled windows starting at the same
+// time are all kept.
+func Upcoming(windows []Window, now time.Time) []Window {
+	sorted := append([]Window(nil), windows...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	seen := map[string]bool{}
+	upcoming := make([]Window, 0, len(sorted))
+	for _, window := range sorted {
+		end := window.End
+		if end.IsZero() {
+			end = window.Start
+		}
+		if end.Before(now) || seen[window.UID()] {
+			continue
+		}
+		seen[window.UID()] = true
+		upcoming = append(upcoming, window)
+	}
+	return upcoming
+}
+
+// Countdown describes how long until the window starts, e.g. "in 2d 3h".
+func Countdown(window Window, now time.Time) string {
+	remaining, started := Remaining(window, now)
+	if started {
+		return "ongoing"
+	}
+	return "in " + remaining
+}
+
+// Remaining returns the time left until the window starts, e.g. "2d 3h", or
+// started if it already has.
+func Remaining(window Window, now time.Time) (remaining string, started bool) {
+	if window.Ongoing(now) || (window.End.IsZero() && !now.Before(window.Start)) {
+		return "", true
+	}
+
+	left := window.Start.Sub(now).Round(time.Minute)
+	days := int(left.Hours()) / 24
+	hours := int(left.Hours()) % 24
+	minutes := int(left.Minutes()) % 60
+	switch {
+	case days > 0:
+		return strconv.Itoa(days) + "d " + strconv.Itoa(hours) + "h", false
+	case hours > 0:
+		return strconv.Itoa(hours) + "h " + strconv.Itoa(minutes) + "m", false
+	default:
+		return strconv.Itoa(minutes) + "m", false
+	}
+}
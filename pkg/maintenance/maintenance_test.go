@@ -0,0 +1,97 @@
+package maintenance_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/maintenance"
+)
+
+// now is the time the tests look at the windows from.
+var now = time.Date(2025, time.October, 19, 12, 0, 0, 0, time.UTC)
+
+func TestUpcoming(t *testing.T) {
+	ended := maintenance.Window{Title: "Ended", Start: now.Add(-4 * time.Hour), End: now.Add(-time.Hour)}
+	ongoing := maintenance.Window{Title: "Ongoing", Start: now.Add(-time.Hour), End: now.Add(time.Hour)}
+	startedWithoutEnd := maintenance.Window{Title: "Started", Start: now.Add(-time.Minute)}
+	pc := maintenance.Window{Title: "PC maintenance", Source: "status", Start: now.Add(24 * time.Hour)}
+	// Another source announcing the same window, in another time zone.
+	pcAgain := maintenance.Window{
+		Title:  "PC maintenance",
+		Source: "news",
+		Start:  now.Add(24 * time.Hour).In(time.FixedZone("CEST", 2*60*60)),
+	}
+	console := maintenance.Window{Title: "Console maintenance", Start: now.Add(24 * time.Hour)}
+	later := maintenance.Window{Title: "Later", Start: now.Add(48 * time.Hour)}
+
+	got := maintenance.Upcoming([]maintenance.Window{later, pc, ended, console, pcAgain, ongoing, startedWithoutEnd}, now)
+
+	want := []maintenance.Window{ongoing, pc, console, later}
+	if len(got) != len(want) {
+		t.Fatalf("Upcoming() = %+v\nwant %+v", got, want)
+	}
+	for i := range want {
+		if got[i].Title != want[i].Title || got[i].Source != want[i].Source || !got[i].Start.Equal(want[i].Start) {
+			t.Errorf("Upcoming()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestUpcomingKeepsInput(t *testing.T) {
+	windows := []maintenance.Window{
+		{Title: "Later", Start: now.Add(48 * time.Hour)},
+		{Title: "Sooner", Start: now.Add(time.Hour)},
+	}
+	maintenance.Upcoming(windows, now)
+	if windows[0].Title != "Later" {
+		t.Errorf("Upcoming() reordered its input to %+v", windows)
+	}
+}
+
+func TestFromText(t *testing.T) {
+	window, ok := maintenance.FromText(
+		"Maintenance for the PC megaservers",
+		"From 2025-10-20 08:00 UTC to 2025-10-20 12:00 UTC.",
+		"https://example.com/news/1",
+		nil,
+	)
+	if !ok {
+		t.Fatal("FromText() found no window")
+	}
+	if start := time.Date(2025, time.October, 20, 8, 0, 0, 0, time.UTC); !window.Start.Equal(start) {
+		t.Errorf("start = %v, want %v", window.Start, start)
+	}
+	if end := time.Date(2025, time.October, 20, 12, 0, 0, 0, time.UTC); !window.End.Equal(end) {
+		t.Errorf("end = %v, want %v", window.End, end)
+	}
+
+	if _, ok = maintenance.FromText("Patch notes", "2025-10-20 08:00 UTC", "", nil); ok {
+		t.Error("FromText() found a window in an article not about maintenance")
+	}
+	if _, ok = maintenance.FromText("Patch notes", "2025-10-20 08:00 UTC", "", []string{"Maintenance"}); !ok {
+		t.Error("FromText() ignored the maintenance category")
+	}
+	if _, ok = maintenance.FromText("Maintenance soon", "no date yet", "", nil); ok {
+		t.Error("FromText() found a window without a start")
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	tests := []struct {
+		window  maintenance.Window
+		want    string
+		started bool
+	}{
+		{window: maintenance.Window{Start: now.Add(51 * time.Hour)}, want: "2d 3h"},
+		{window: maintenance.Window{Start: now.Add(90 * time.Minute)}, want: "1h 30m"},
+		{window: maintenance.Window{Start: now.Add(5 * time.Minute)}, want: "5m"},
+		{window: maintenance.Window{Start: now.Add(-time.Minute), End: now.Add(time.Hour)}, started: true},
+		{window: maintenance.Window{Start: now}, started: true},
+	}
+	for _, tt := range tests {
+		remaining, started := maintenance.Remaining(tt.window, now)
+		if remaining != tt.want || started != tt.started {
+			t.Errorf("Remaining(%v) = %q, %v, want %q, %v", tt.window.Start, remaining, started, tt.want, tt.started)
+		}
+	}
+}
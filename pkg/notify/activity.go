@@ -0,0 +1,205 @@
+package notify
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/jsonfile"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/poller"
+)
+
+// Activity is what happened between two digests.
+type Activity struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// PeakPlayers is the highest polled player count, 0 if none was polled.
+	PeakPlayers   int       `json:"peakPlayers"`
+	PeakPlayersAt time.Time `json:"peakPlayersAt"`
+	// Regions are keyed by region ID.
+	Regions map[string]*RegionActivity `json:"regions"`
+}
+
+// RegionActivity is the observed uptime and the outages of one region.
+type RegionActivity struct {
+	Name     string        `json:"name"`
+	Observed time.Duration `json:"observed"`
+	Up       time.Duration `json:"up"`
+	Outages  []Outage      `json:"outages"`
+}
+
+// Outage is a time a region wasn't online.
+type Outage struct {
+	State esostatus.State `json:"state"`
+	Start time.Time       `json:"start"`
+	// End is zero while the outage is ongoing.
+	End time.Time `json:"end"`
+}
+
+// SortedRegions returns the regions ordered by name.
+func (a Activity) SortedRegions() []*RegionActivity {
+	regions := make([]*RegionActivity, 0, len(a.Regions))
+	for _, region := range a.Regions {
+		regions = append(regions, region)
+	}
+	slices.SortFunc(regions, func(a, b *RegionActivity) int { return cmp.Compare(a.Name, b.Name) })
+	return regions
+}
+
+// Uptime returns the share of the observed time the region was online, e.g.
+// "99.5%", or "n/a" if it wasn't observed.
+func (r RegionActivity) Uptime() string {
+	if r.Observed <= 0 {
+		return "n/a"
+	}
+	return strconv.FormatFloat(float64(r.Up)*100/float64(r.Observed), 'f', 1, 64) + "%"
+}
+
+// Ongoing reports whether the outage hasn't ended yet.
+func (o Outage) Ongoing() bool {
+	return o.End.IsZero()
+}
+
+// Duration returns how long the outage lasted, up to now if it's ongoing,
+// e.g. "1h 5m".
+func (o Outage) Duration(now time.Time) string {
+	end := o.End
+	if o.Ongoing() {
+		end = now
+	}
+
+	minutes := int(end.Sub(o.Start).Round(time.Minute).Minutes())
+	if minutes < 60 {
+		return strconv.Itoa(minutes) + "m"
+	}
+	return strconv.Itoa(minutes/60) + "h " + strconv.Itoa(minutes%60) + "m"
+}
+
+// Recorder collects the activity from the poller snapshots. It keeps the
+// activity in a JSON file so a restart doesn't lose the current period.
+type Recorder struct {
+	path     string
+	mu       sync.Mutex
+	activity Activity
+}
+
+// OpenRecorder loads the activity stored at path. A missing file starts a new
+// period now, so does a file that can't be decoded, which is kept as a backup.
+func OpenRecorder(path string) (*Recorder, error) {
+	activity, err := jsonfile.Load[Activity](path)
+	if err != nil {
+		return nil, fmt.Errorf("reading activity: %w", err)
+	}
+	if activity.Start.IsZero() {
+		activity.Start = time.Now()
+	}
+	return &Recorder{path: path, activity: activity}, nil
+}
+
+// OnSnapshot is a poller.Listener. The time between two snapshots counts for
+// the state of the previous one; states that aren't known aren't counted.
+func (r *Recorder) OnSnapshot(_ context.Context, previous, current poller.Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.activity.Regions == nil {
+		r.activity.Regions = map[string]*RegionActivity{}
+	}
+	if current.Players > r.activity.PeakPlayers {
+		r.activity.PeakPlayers = current.Players
+		r.activity.PeakPlayersAt = current.Time
+	}
+
+	elapsed := current.Time.Sub(previous.Time)
+	for _, status := range current.Status.Regions {
+		region, ok := r.activity.Regions[status.Region]
+		if !ok {
+			region = &RegionActivity{}
+			r.activity.Regions[status.Region] = region
+		}
+		region.Name = status.Name
+
+		before := previous.Status.Region(status.Region).State
+		if !previous.Time.IsZero() && before != esostatus.StateUnknown {
+			region.Observed += elapsed
+			if before == esostatus.StateOnline {
+				region.Up += elapsed
+			}
+		}
+
+		region.track(status, current.Time)
+	}
+
+	r.save()
+}
+
+// track opens an outage when the region goes down and closes it when it's back.
+func (r *RegionActivity) track(status esostatus.RegionStatus, now time.Time) {
+	var open *Outage
+	if n := len(r.Outages); n > 0 && r.Outages[n-1].Ongoing() {
+		open = &r.Outages[n-1]
+	}
+
+	switch status.State {
+	case esostatus.StateOnline:
+		if open != nil {
+			open.End = now
+		}
+	case esostatus.StateOffline, esostatus.StateMaintenance:
+		if open == nil {
+			start := status.Since
+			if start.IsZero() || start.After(now) {
+				start = now
+			}
+			r.Outages = append(r.Outages, Outage{State: status.State, Start: start})
+		}
+	case esostatus.StateUnknown:
+	}
+}
+
+// Current returns a copy of the activity of the period up to now.
+func (r *Recorder) Current(now time.Time) Activity {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.activity
+	current.End = now
+	current.Regions = make(map[string]*RegionActivity, len(r.activity.Regions))
+	for id, region := range r.activity.Regions {
+		copied := *region
+		copied.Outages = slices.Clone(region.Outages)
+		current.Regions[id] = &copied
+	}
+	return current
+}
+
+// Reset starts a new period at now, once the activity up to now has been
+// sent. Ongoing outages are carried over.
+func (r *Recorder) Reset(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next := Activity{Start: now, Regions: map[string]*RegionActivity{}}
+	for id, region := range r.activity.Regions {
+		carried := &RegionActivity{Name: region.Name}
+		if n := len(region.Outages); n > 0 && region.Outages[n-1].Ongoing() {
+			carried.Outages = []Outage{region.Outages[n-1]}
+		}
+		next.Regions[id] = carried
+	}
+	r.activity = next
+	r.save()
+}
+
+// save writes the activity, logging failures as the poller can't handle them.
+func (r *Recorder) save() {
+	if err := jsonfile.Write(r.path, r.activity); err != nil {
+		log.Println("Error saving activity:", err)
+	}
+}
@@ -0,0 +1,141 @@
+package notify_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/notify"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/poller"
+)
+
+// start is when the recorded snapshots begin.
+var start = time.Date(2025, time.October, 19, 8, 0, 0, 0, time.UTC)
+
+// snapshot returns a poll minutes after start with PC-EU in the state.
+func snapshot(minutes int, players int, state esostatus.State) poller.Snapshot {
+	return poller.Snapshot{
+		Time:    start.Add(time.Duration(minutes) * time.Minute),
+		Players: players,
+		Status: esostatus.Report{Regions: []esostatus.RegionStatus{
+			{Region: "PC-EU", Name: "PC Europe", State: state},
+		}},
+	}
+}
+
+// record feeds the snapshots to the recorder like the poller does.
+func record(recorder *notify.Recorder, snapshots ...poller.Snapshot) {
+	var previous poller.Snapshot
+	for _, current := range snapshots {
+		recorder.OnSnapshot(context.Background(), previous, current)
+		previous = current
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "activity.json")
+	recorder, err := notify.OpenRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record(recorder,
+		snapshot(0, 10000, esostatus.StateOnline),
+		snapshot(30, 14000, esostatus.StateOnline),
+		snapshot(60, 9000, esostatus.StateMaintenance),
+		// Unknown states count neither as up nor as observed.
+		snapshot(90, 0, esostatus.StateUnknown),
+		snapshot(120, 12000, esostatus.StateOnline),
+		snapshot(150, 12000, esostatus.StateOffline),
+	)
+
+	// A restart keeps the activity of the period.
+	if recorder, err = notify.OpenRecorder(path); err != nil {
+		t.Fatal(err)
+	}
+	activity := recorder.Current(start.Add(3 * time.Hour))
+	recorder.Reset(start.Add(3 * time.Hour))
+
+	if activity.PeakPlayers != 14000 || !activity.PeakPlayersAt.Equal(start.Add(30*time.Minute)) {
+		t.Errorf("peak = %d at %v, want 14000 at 08:30", activity.PeakPlayers, activity.PeakPlayersAt)
+	}
+	region := activity.Regions["PC-EU"]
+	if region == nil || region.Name != "PC Europe" {
+		t.Fatalf("regions = %+v, want PC Europe", activity.Regions)
+	}
+	if region.Observed != 2*time.Hour || region.Up != 90*time.Minute || region.Uptime() != "75.0%" {
+		t.Errorf("observed %v, up %v, uptime %s, want 2h, 1h30m and 75.0%%", region.Observed, region.Up, region.Uptime())
+	}
+	if len(region.Outages) != 2 {
+		t.Fatalf("outages = %+v, want the maintenance and the ongoing outage", region.Outages)
+	}
+	maintenance := region.Outages[0]
+	if maintenance.State != esostatus.StateMaintenance || maintenance.Ongoing() || maintenance.Duration(start) != "1h 0m" {
+		t.Errorf("first outage = %+v, want an hour of maintenance", maintenance)
+	}
+	if ongoing := region.Outages[1]; !ongoing.Ongoing() || ongoing.Duration(start.Add(3*time.Hour)) != "30m" {
+		t.Errorf("second outage = %+v, want it ongoing for 30m", ongoing)
+	}
+
+	// The next period starts at the take and carries the ongoing outage over.
+	next := recorder.Current(start.Add(4 * time.Hour))
+	if !next.Start.Equal(start.Add(3*time.Hour)) || next.PeakPlayers != 0 {
+		t.Errorf("next period = %+v, want a fresh one from 11:00", next)
+	}
+	if outages := next.Regions["PC-EU"].Outages; len(outages) != 1 || !outages[0].Ongoing() {
+		t.Errorf("next outages = %+v, want the ongoing outage", outages)
+	}
+	if uptime := next.Regions["PC-EU"].Uptime(); uptime != "n/a" {
+		t.Errorf("uptime of an unobserved region = %s, want n/a", uptime)
+	}
+}
+
+func TestOutageStartsWhenAnnounced(t *testing.T) {
+	recorder, err := notify.OpenRecorder(filepath.Join(t.TempDir(), "activity.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	down := snapshot(60, 0, esostatus.StateOffline)
+	down.Status.Regions[0].Since = start.Add(45 * time.Minute)
+	record(recorder, snapshot(0, 0, esostatus.StateOnline), down)
+
+	outage := recorder.Current(start.Add(2 * time.Hour)).Regions["PC-EU"].Outages[0]
+	if !outage.Start.Equal(down.Status.Regions[0].Since) {
+		t.Errorf("outage starts at %v, want the time the status page gives", outage.Start)
+	}
+}
+
+func TestCurrentIsACopy(t *testing.T) {
+	recorder, err := notify.OpenRecorder(filepath.Join(t.TempDir(), "activity.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	record(recorder, snapshot(0, 0, esostatus.StateOnline), snapshot(30, 0, esostatus.StateOffline))
+
+	current := recorder.Current(start.Add(time.Hour))
+	current.Regions["PC-EU"].Outages[0].End = start.Add(time.Hour)
+	if again := recorder.Current(start.Add(time.Hour)); !again.Regions["PC-EU"].Outages[0].Ongoing() {
+		t.Error("changing the returned activity changed the recorded one")
+	}
+}
+
+func TestOpenRecorderCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "activity.json")
+	if err := os.WriteFile(path, []byte(`{"start":"2025-10-19T08:00:00Z","regions":{"PC-EU":{"na`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder, err := notify.OpenRecorder(path)
+	if err != nil {
+		t.Fatalf("OpenRecorder() error = %v, want a new period", err)
+	}
+	if activity := recorder.Current(time.Now()); activity.Start.IsZero() || len(activity.Regions) > 0 {
+		t.Errorf("activity = %+v, want a new period", activity)
+	}
+	if backups, _ := filepath.Glob(path + ".corrupt-*"); len(backups) != 1 {
+		t.Errorf("backups = %v, want the corrupt file kept", backups)
+	}
+}
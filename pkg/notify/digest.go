@@ -0,0 +1,162 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"log"
+	"os"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+)
+
+//go:embed templates
+var defaultTemplates embed.FS
+
+// rssTimeLayout is the layout of component.RSSItem.PubDate.
+const rssTimeLayout = "2006-01-02 15:04:05"
+
+// Templates render the bodies of a digest mail from DigestData.
+type Templates struct {
+	Text *texttemplate.Template
+	HTML *htmltemplate.Template
+}
+
+// LoadTemplates parses the digest templates at textPath and htmlPath. An
+// empty path uses the built-in template.
+func LoadTemplates(textPath, htmlPath string) (Templates, error) {
+	var templates Templates
+
+	text, err := readTemplate(textPath, "templates/digest.txt.tmpl")
+	if err != nil {
+		return templates, err
+	}
+	if templates.Text, err = texttemplate.New("text").Parse(text); err != nil {
+		return templates, fmt.Errorf("parsing text template: %w", err)
+	}
+
+	html, err := readTemplate(htmlPath, "templates/digest.html.tmpl")
+	if err != nil {
+		return templates, err
+	}
+	if templates.HTML, err = htmltemplate.New("html").Parse(html); err != nil {
+		return templates, fmt.Errorf("parsing html template: %w", err)
+	}
+
+	return templates, nil
+}
+
+// readTemplate reads the template at path, or the embedded fallback if path is empty.
+func readTemplate(path, fallback string) (string, error) {
+	var data []byte
+	var err error
+	if path == "" {
+		data, err = defaultTemplates.ReadFile(fallback)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading template: %w", err)
+	}
+	return string(data), nil
+}
+
+// NewsItem is an article published during the digest period.
+type NewsItem struct {
+	Title     string
+	Link      string
+	Published time.Time
+}
+
+// DigestData is what the digest templates are executed with.
+type DigestData struct {
+	// Period is "daily" or "weekly".
+	Period   string
+	Activity Activity
+	News     []NewsItem
+}
+
+// Digest mails a summary of the recorded activity on a schedule.
+type Digest struct {
+	Recorder  *Recorder
+	Mailer    Mailer
+	Schedule  Schedule
+	Templates Templates
+	// FetchNews returns the latest news, component.FetchRSSFeed if nil.
+	FetchNews func(ctx context.Context) (component.RSSFeedResponse, error)
+}
+
+// Run sends a digest at every scheduled time until ctx is done.
+func (d Digest) Run(ctx context.Context) {
+	for {
+		next := d.Schedule.Next(time.Now())
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := d.Send(ctx, time.Now()); err != nil {
+			log.Println("Error sending digest:", err)
+		}
+	}
+}
+
+// Send mails the activity recorded up to now and starts a new period. The
+// period goes on if the digest can't be sent, so the next one covers it.
+func (d Digest) Send(ctx context.Context, now time.Time) error {
+	activity := d.Recorder.Current(now)
+
+	news, err := d.news(ctx, activity.Start, now)
+	if err != nil {
+		// The digest is still worth sending without the news.
+		log.Println("Error fetching news for digest:", err)
+	}
+
+	data := DigestData{Period: d.Schedule.Period(), Activity: activity, News: news}
+
+	var text, html bytes.Buffer
+	if err = d.Templates.Text.Execute(&text, data); err != nil {
+		return fmt.Errorf("executing text template: %w", err)
+	}
+	if err = d.Templates.HTML.Execute(&html, data); err != nil {
+		return fmt.Errorf("executing html template: %w", err)
+	}
+
+	subject := "ESO " + data.Period + " digest for " + now.Local().Format("2006-01-02")
+	if err = d.Mailer.Send(subject, text.String(), html.String()); err != nil {
+		return err
+	}
+	d.Recorder.Reset(now)
+	return nil
+}
+
+// news returns the articles published between start and end.
+func (d Digest) news(ctx context.Context, start, end time.Time) ([]NewsItem, error) {
+	fetch := d.FetchNews
+	if fetch == nil {
+		fetch = component.FetchRSSFeed
+	}
+
+	feed, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var news []NewsItem
+	for _, item := range feed.Items {
+		published, err := time.Parse(rssTimeLayout, item.PubDate)
+		if err != nil || published.Before(start) || !published.Before(end) {
+			continue
+		}
+		news = append(news, NewsItem{Title: item.Title, Link: item.Link, Published: published})
+	}
+	return news, nil
+}
@@ -0,0 +1,72 @@
+package notify_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/notify"
+)
+
+// newDigest returns a daily digest of a recorded hour, mailed through addr.
+func newDigest(t *testing.T, addr string) notify.Digest {
+	t.Helper()
+	recorder, err := notify.OpenRecorder(filepath.Join(t.TempDir(), "activity.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	record(recorder, snapshot(0, 10000, esostatus.StateOnline), snapshot(60, 14000, esostatus.StateOnline))
+
+	schedule, err := notify.ParseSchedule("daily")
+	if err != nil {
+		t.Fatal(err)
+	}
+	templates, err := notify.LoadTemplates("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return notify.Digest{
+		Recorder:  recorder,
+		Mailer:    notify.Mailer{Addr: addr, From: "dashboard@example.com", To: []string{"a@example.com"}},
+		Schedule:  schedule,
+		Templates: templates,
+		FetchNews: func(context.Context) (component.RSSFeedResponse, error) {
+			return component.RSSFeedResponse{}, nil
+		},
+	}
+}
+
+func TestDigestSend(t *testing.T) {
+	addr, mails := smtpServer(t)
+	digest := newDigest(t, addr)
+	now := start.Add(2 * time.Hour)
+
+	if err := digest.Send(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+	if mail := <-mails; !strings.Contains(mail.data, "14000") {
+		t.Errorf("mail = %q, want the peak of the period", mail.data)
+	}
+	if next := digest.Recorder.Current(now); !next.Start.Equal(now) || next.PeakPlayers != 0 {
+		t.Errorf("period after sending = %+v, want a new one from the digest", next)
+	}
+}
+
+func TestDigestKeepsActivityWhenSendFails(t *testing.T) {
+	// Nothing listens on the discard port.
+	digest := newDigest(t, "127.0.0.1:9")
+	now := start.Add(2 * time.Hour)
+	periodStart := digest.Recorder.Current(now).Start
+
+	if err := digest.Send(context.Background(), now); err == nil {
+		t.Fatal("Send() without an SMTP server succeeded")
+	}
+	activity := digest.Recorder.Current(now)
+	if !activity.Start.Equal(periodStart) || activity.PeakPlayers != 14000 {
+		t.Errorf("period after failing = %+v, want the unsent one", activity)
+	}
+}
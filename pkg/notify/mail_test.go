@@ -0,0 +1,147 @@
+package notify_test
+
+import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/notify"
+)
+
+// received is a mail taken by smtpServer.
+type received struct {
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+// smtpServer accepts a single SMTP session on a local port and returns its
+// address and the mail, available once the session ended.
+func smtpServer(t *testing.T) (string, <-chan received) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	mails := make(chan received, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		mails <- smtpSession(textproto.NewConn(conn))
+	}()
+	return listener.Addr().String(), mails
+}
+
+// smtpSession speaks just enough SMTP for net/smtp.SendMail.
+func smtpSession(conn *textproto.Conn) received {
+	var mail received
+	_ = conn.PrintfLine("220 localhost ESMTP test")
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return mail
+		}
+		command, argument, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(command) {
+		case "EHLO":
+			_ = conn.PrintfLine("250-localhost\r\n250 AUTH PLAIN")
+		case "AUTH":
+			mail.auth = argument
+			_ = conn.PrintfLine("235 2.7.0 Authentication successful")
+		case "MAIL":
+			mail.from = argument
+			_ = conn.PrintfLine("250 OK")
+		case "RCPT":
+			mail.to = append(mail.to, argument)
+			_ = conn.PrintfLine("250 OK")
+		case "DATA":
+			_ = conn.PrintfLine("354 Go ahead")
+			data, _ := io.ReadAll(conn.DotReader())
+			mail.data = string(data)
+			_ = conn.PrintfLine("250 OK")
+		case "QUIT":
+			_ = conn.PrintfLine("221 Bye")
+			return mail
+		default:
+			_ = conn.PrintfLine("502 Not implemented")
+		}
+	}
+}
+
+func TestMailerSend(t *testing.T) {
+	addr, mails := smtpServer(t)
+	mailer := notify.Mailer{
+		Addr:     addr,
+		Username: "dashboard",
+		Password: "secret",
+		From:     "dashboard@example.com",
+		To:       []string{"a@example.com", "b@example.com"},
+	}
+	if err := mailer.Send("ESO daily digest – Überblick", "Peak: 14,000 players", "<p>Peak: <b>14,000</b> players</p>"); err != nil {
+		t.Fatal(err)
+	}
+	got := <-mails
+
+	if got.auth != "PLAIN "+base64.StdEncoding.EncodeToString([]byte("\x00dashboard\x00secret")) {
+		t.Errorf("AUTH %s, want PLAIN with the credentials", got.auth)
+	}
+	if got.from != "FROM:<dashboard@example.com>" || strings.Join(got.to, " ") != "TO:<a@example.com> TO:<b@example.com>" {
+		t.Errorf("envelope from %s to %v, want the configured addresses", got.from, got.to)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(got.data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "ESO daily digest – Überblick" {
+		t.Errorf("Subject = %q, %v, want the encoded subject", subject, err)
+	}
+	if to := msg.Header.Get("To"); to != "a@example.com, b@example.com" {
+		t.Errorf("To = %q, want both recipients", to)
+	}
+	if _, err = msg.Header.Date(); err != nil {
+		t.Errorf("Date: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %s, %v, want multipart/alternative", mediaType, err)
+	}
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for _, want := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", "Peak: 14,000 players"},
+		{"text/html; charset=utf-8", "<p>Peak: <b>14,000</b> players</p>"},
+	} {
+		part, err := parts.NextPart()
+		if err != nil {
+			t.Fatalf("reading %s part: %v", want.contentType, err)
+		}
+		// The multipart reader decodes the quoted-printable body.
+		body, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if part.Header.Get("Content-Type") != want.contentType || string(body) != want.body {
+			t.Errorf("part %s = %q, want %s %q", part.Header.Get("Content-Type"), body, want.contentType, want.body)
+		}
+	}
+}
+
+func TestMailerWithoutRecipients(t *testing.T) {
+	if err := (notify.Mailer{Addr: "127.0.0.1:25", From: "dashboard@example.com"}).Send("subject", "text", "html"); err == nil {
+		t.Error("Send() without recipients succeeded")
+	}
+}
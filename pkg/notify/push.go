@@ -0,0 +1,141 @@
+// Package notify tells users about changes collected by the poller.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/poller"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/webpush"
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+const (
+	// DefaultPushWorkers is the number of messages sent at the same time when
+	// Push.Workers is unset.
+	DefaultPushWorkers = 8
+	// pushQueueSize is the number of changes waiting to be sent before new
+	// ones are dropped.
+	pushQueueSize = 64
+)
+
+// Push sends a Web Push message to every subscription watching a region
+// whose state changed. The messages are sent by Run, so the poller never
+// waits for the push services.
+type Push struct {
+	Store  *webpush.Store
+	Sender webpush.Sender
+	// Workers is the number of messages sent at the same time,
+	// DefaultPushWorkers if zero.
+	Workers int
+
+	messages chan pushMessage
+}
+
+// pushMessage is the message about a change, sent to the subscriptions
+// watching its region.
+type pushMessage struct {
+	region  string
+	payload []byte
+}
+
+// pushDelivery is a message to a single subscription.
+type pushDelivery struct {
+	registration webpush.Registration
+	payload      []byte
+}
+
+// NewPush returns a Push sending through sender to the subscriptions of store.
+func NewPush(store *webpush.Store, sender webpush.Sender) *Push {
+	return &Push{Store: store, Sender: sender, messages: make(chan pushMessage, pushQueueSize)}
+}
+
+// OnSnapshot is a poller.Listener. It only queues the messages about the
+// changes, a full queue drops them.
+func (p *Push) OnSnapshot(_ context.Context, previous, current poller.Snapshot) {
+	for _, change := range poller.Changes(previous, current) {
+		payload, err := json.Marshal(app.Notification{
+			Title: change.Region.Name + " is " + change.Region.State.String(),
+			Body:  pushBody(change),
+			Path:  "/",
+			Tag:   "region-" + change.Region.Region,
+			Icon:  "/web/eso.png",
+		})
+		if err != nil {
+			log.Println("Error encoding push message:", err)
+			continue
+		}
+
+		select {
+		case p.messages <- pushMessage{region: change.Region.Region, payload: payload}:
+		default:
+			log.Println("Error queueing push message: queue full, dropping the change of", change.Region.Region)
+		}
+	}
+}
+
+// Run sends the queued messages with Workers sends at the same time until
+// ctx is done.
+func (p *Push) Run(ctx context.Context) {
+	workers := p.Workers
+	if workers <= 0 {
+		workers = DefaultPushWorkers
+	}
+
+	deliveries := make(chan pushDelivery)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for delivery := range deliveries {
+				p.send(ctx, delivery.registration, delivery.payload)
+			}
+		}()
+	}
+	defer wg.Wait()
+	defer close(deliveries)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message := <-p.messages:
+			for _, registration := range p.Store.List() {
+				if !registration.Watches(message.region) {
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case deliveries <- pushDelivery{registration: registration, payload: message.payload}:
+				}
+			}
+		}
+	}
+}
+
+// send delivers a message, dropping subscriptions the push service doesn't know anymore.
+func (p *Push) send(ctx context.Context, registration webpush.Registration, payload []byte) {
+	err := p.Sender.Send(ctx, registration.Subscription, payload)
+	switch {
+	case errors.Is(err, webpush.ErrSubscriptionGone):
+		if err = p.Store.Remove(registration.Subscription.Endpoint); err != nil {
+			log.Println("Error removing push subscription:", err)
+		}
+	case err != nil:
+		log.Println("Error sending push message:", err)
+	}
+}
+
+// pushBody describes a change, e.g. "Was Online. Maintenance in progress".
+func pushBody(change poller.Change) string {
+	body := "Was " + change.Previous.String() + "."
+	if change.Region.Message != "" {
+		body += " " + change.Region.Message
+	}
+	return body
+}
@@ -0,0 +1,149 @@
+package notify_test
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/notify"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/webpush"
+)
+
+// pushService answers the push messages once released, recording how many
+// were in flight at the same time.
+type pushService struct {
+	release chan struct{}
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	delivered   []string
+}
+
+// RoundTrip waits for the release and accepts the message, 410 for
+// endpoints ending in /gone.
+func (s *pushService) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	s.inFlight++
+	s.maxInFlight = max(s.maxInFlight, s.inFlight)
+	s.mu.Unlock()
+
+	<-s.release
+
+	s.mu.Lock()
+	s.inFlight--
+	s.delivered = append(s.delivered, req.URL.String())
+	s.mu.Unlock()
+
+	recorder := httptest.NewRecorder()
+	if strings.HasSuffix(req.URL.Path, "/gone") {
+		recorder.WriteHeader(http.StatusGone)
+	} else {
+		recorder.WriteHeader(http.StatusCreated)
+	}
+	resp := recorder.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// subscription returns a subscription to endpoint with fresh keys.
+func subscription(t *testing.T, endpoint string) webpush.Subscription {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s webpush.Subscription
+	s.Endpoint = endpoint
+	s.Keys.P256dh = base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes())
+	s.Keys.Auth = base64.RawURLEncoding.EncodeToString(make([]byte, 16))
+	return s
+}
+
+func TestPushDoesNotBlockThePoller(t *testing.T) {
+	store, err := webpush.OpenStore(filepath.Join(t.TempDir(), "push-subscriptions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoints := []string{"https://push.example/gone"}
+	for i := range 20 {
+		endpoints = append(endpoints, "https://push.example/"+strconv.Itoa(i))
+	}
+	for _, endpoint := range endpoints {
+		if err = store.Put(webpush.Registration{Subscription: subscription(t, endpoint), Regions: []string{"PC-EU"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = store.Put(webpush.Registration{Subscription: subscription(t, "https://push.example/na"), Regions: []string{"PC-NA"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := &pushService{release: make(chan struct{})}
+	push := notify.NewPush(store, webpush.Sender{Keys: keys, Subject: "mailto:admin@example.com", Client: &http.Client{Transport: service}})
+	push.Workers = 3
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		push.Run(ctx)
+		close(done)
+	}()
+
+	returned := make(chan struct{})
+	go func() {
+		push.OnSnapshot(ctx, snapshot(0, 0, esostatus.StateOnline), snapshot(3, 0, esostatus.StateMaintenance))
+		close(returned)
+	}()
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("OnSnapshot() waited for the push service")
+	}
+
+	close(service.release)
+	// Every PC-EU subscription gets the message, the gone one is replaced
+	// by the PC-NA one in the store.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		service.mu.Lock()
+		delivered := len(service.delivered)
+		service.mu.Unlock()
+		if delivered == len(endpoints) && len(store.List()) == len(endpoints) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("delivered %d messages and kept %d subscriptions, want %d and %d", delivered, len(store.List()), len(endpoints), len(endpoints))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if service.maxInFlight > push.Workers {
+		t.Errorf("%d messages were sent at the same time, want at most %d", service.maxInFlight, push.Workers)
+	}
+	for _, endpoint := range service.delivered {
+		if endpoint == "https://push.example/na" {
+			t.Error("sent the change to a subscription not watching the region")
+		}
+	}
+	for _, registration := range store.List() {
+		if registration.Subscription.Endpoint == "https://push.example/gone" {
+			t.Error("kept the subscription the push service doesn't know anymore")
+		}
+	}
+}
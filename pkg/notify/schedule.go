@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is when digests are sent, in local time.
+type Schedule struct {
+	// Weekly sends a digest once a week on Weekday instead of every day.
+	Weekly  bool
+	Weekday time.Weekday
+	Hour    int
+	Minute  int
+}
+
+// DefaultSchedule sends a digest every day at 08:00.
+var DefaultSchedule = Schedule{Weekday: time.Monday, Hour: 8}
+
+// ParseSchedule parses schedules such as "daily", "daily 18:30", "weekly" or
+// "weekly sunday 18:30". Omitted parts are taken from DefaultSchedule and an
+// empty string yields it.
+func ParseSchedule(s string) (Schedule, error) {
+	schedule := DefaultSchedule
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 {
+		return schedule, nil
+	}
+
+	switch fields[0] {
+	case "daily":
+	case "weekly":
+		schedule.Weekly = true
+	default:
+		return Schedule{}, fmt.Errorf("unknown digest schedule %q, want daily or weekly", fields[0])
+	}
+	fields = fields[1:]
+
+	if schedule.Weekly && len(fields) > 0 && !strings.Contains(fields[0], ":") {
+		weekday, ok := parseWeekday(fields[0])
+		if !ok {
+			return Schedule{}, fmt.Errorf("unknown weekday %q", fields[0])
+		}
+		schedule.Weekday = weekday
+		fields = fields[1:]
+	}
+
+	if len(fields) > 0 {
+		hour, minute, ok := strings.Cut(fields[0], ":")
+		h, errHour := strconv.Atoi(hour)
+		m, errMinute := strconv.Atoi(minute)
+		if !ok || errHour != nil || errMinute != nil || h < 0 || h > 23 || m < 0 || m > 59 {
+			return Schedule{}, fmt.Errorf("invalid digest time %q, want HH:MM", fields[0])
+		}
+		schedule.Hour, schedule.Minute = h, m
+		fields = fields[1:]
+	}
+
+	if len(fields) > 0 {
+		return Schedule{}, fmt.Errorf("unexpected %q in digest schedule", strings.Join(fields, " "))
+	}
+	return schedule, nil
+}
+
+// parseWeekday parses an English weekday name.
+func parseWeekday(s string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), s) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// Period returns the name of the period covered by a digest, "daily" or "weekly".
+func (s Schedule) Period() string {
+	if s.Weekly {
+		return "weekly"
+	}
+	return "daily"
+}
+
+// Next returns the first send time after t.
+func (s Schedule) Next(t time.Time) time.Time {
+	next := time.Date(t.Year(), t.Month(), t.Day(), s.Hour, s.Minute, 0, 0, t.Location())
+	if s.Weekly {
+		next = next.AddDate(0, 0, (int(s.Weekday)-int(next.Weekday())+7)%7)
+	}
+	for !next.After(t) {
+		if s.Weekly {
+			next = next.AddDate(0, 0, 7)
+		} else {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+	return next
+}
@@ -0,0 +1,64 @@
+package notify_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/notify"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := map[string]notify.Schedule{
+		"":                          notify.DefaultSchedule,
+		"daily":                     notify.DefaultSchedule,
+		"Daily 18:30":               {Weekday: time.Monday, Hour: 18, Minute: 30},
+		"weekly":                    {Weekly: true, Weekday: time.Monday, Hour: 8},
+		"weekly 07:05":              {Weekly: true, Weekday: time.Monday, Hour: 7, Minute: 5},
+		"weekly Sunday 18:00":       {Weekly: true, Weekday: time.Sunday, Hour: 18},
+		"  weekly   saturday  0:00": {Weekly: true, Weekday: time.Saturday},
+	}
+	for input, want := range tests {
+		got, err := notify.ParseSchedule(input)
+		if err != nil || got != want {
+			t.Errorf("ParseSchedule(%q) = %+v, %v, want %+v", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{
+		"hourly",
+		"daily sunday",
+		"weekly someday",
+		"daily 24:00",
+		"daily 12:60",
+		"daily noon",
+		"daily 18:00 please",
+	} {
+		if _, err := notify.ParseSchedule(input); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want an error", input)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	berlin := time.FixedZone("CEST", 2*60*60)
+	// Sunday 19 October 2025, 12:00.
+	now := time.Date(2025, time.October, 19, 12, 0, 0, 0, berlin)
+
+	tests := []struct {
+		schedule notify.Schedule
+		want     time.Time
+	}{
+		{notify.Schedule{Hour: 18}, time.Date(2025, time.October, 19, 18, 0, 0, 0, berlin)},
+		{notify.Schedule{Hour: 8}, time.Date(2025, time.October, 20, 8, 0, 0, 0, berlin)},
+		// A send time equal to now is already over.
+		{notify.Schedule{Hour: 12}, time.Date(2025, time.October, 20, 12, 0, 0, 0, berlin)},
+		{notify.Schedule{Weekly: true, Weekday: time.Sunday, Hour: 18}, time.Date(2025, time.October, 19, 18, 0, 0, 0, berlin)},
+		{notify.Schedule{Weekly: true, Weekday: time.Sunday, Hour: 8}, time.Date(2025, time.October, 26, 8, 0, 0, 0, berlin)},
+		{notify.Schedule{Weekly: true, Weekday: time.Wednesday, Hour: 8, Minute: 30}, time.Date(2025, time.October, 22, 8, 30, 0, 0, berlin)},
+	}
+	for _, tt := range tests {
+		if got := tt.schedule.Next(now); !got.Equal(tt.want) {
+			t.Errorf("%+v.Next(%v) = %v, want %v", tt.schedule, now, got, tt.want)
+		}
+	}
+}
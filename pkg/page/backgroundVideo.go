@@ -0,0 +1,39 @@
+package page
+
+import (
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// backgroundVideoID is the ID of the video element playing behind the dashboard.
+const backgroundVideoID = "bg-video"
+
+// getVideoPaused reads whether the user paused the background video. Until
+// they pick, the video stays paused for browsers asking for reduced motion.
+func getVideoPaused(ctx app.Context) bool {
+	var paused *bool
+	ctx.GetState("backgroundVideoPaused", &paused)
+	if paused != nil {
+		return *paused
+	}
+	return app.Window().Call("matchMedia", "(prefers-reduced-motion: reduce)").Get("matches").Bool()
+}
+
+// setVideoPaused stores the choice of the user in local storage.
+func setVideoPaused(ctx app.Context, paused bool) {
+	ctx.SetState("backgroundVideoPaused", paused).Persist()
+}
+
+// playBackgroundVideo starts or pauses the background video. The video never
+// autoplays, so pre-rendered pages don't move before the choice is known.
+func playBackgroundVideo(paused bool) {
+	video := app.Window().GetElementByID(backgroundVideoID)
+	if !video.Truthy() {
+		return
+	}
+	if paused {
+		video.Call("pause")
+		return
+	}
+	// Browsers may refuse to play, the returned promise is ignored.
+	video.Call("play")
+}
@@ -4,7 +4,11 @@ package page
 // TODO - Get Server Status from API, set as local storage and use it to update the UI every 3 minutes (Without site refresh?! https://go-app.dev/components)
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
 	"github.com/maxence-charriere/go-app/v10/pkg/app"
 )
 
@@ -13,17 +17,101 @@ import (
 // embedding app.Compo into a struct.
 type Dashboard struct {
 	app.Compo
-	RSSFeed component.RSSFeed
-	ServerStatus component.ServerStatus
-	CurrentPlayers component.CurrentPlayers
-	PeakPlayerCount component.PeakPlayerCount
-	AllPeakPlayerCount component.AllPeakPlayerCount
+	// Layout is the order, size and visibility of the cards.
+	Layout Layout
+	// Theme is the ID of the current theme.
+	Theme string
+	// Lang is the language of the dashboard texts.
+	Lang             string
+	ThemeSwitcher    component.ThemeSwitcher
+	LanguageSwitcher component.LanguageSwitcher
 	isAppInstallable bool
+	// editing shows the controls that change the layout.
+	editing bool
+	// dragging is the card being dragged in edit mode.
+	dragging string
+	// videoPaused stops the background video.
+	videoPaused bool
+	// lite replaces the background video by its poster.
+	lite bool
+	// offline reports whether the browser lost its network connection.
+	offline bool
+	// staleSince is when the oldest data shown instead of fresh data was
+	// taken, zero while all data is fresh.
+	staleSince time.Time
+	// releaseConnectionListeners removes the online and offline listeners.
+	releaseConnectionListeners func()
+	// widgets are the components of the configured widgets by ID, created
+	// once so they keep their state.
+	widgets map[string]app.Composer
+}
+
+// OnInit creates the components of the configured widgets.
+func (d *Dashboard) OnInit() {
+	d.widgets = map[string]app.Composer{}
+	for _, widget := range component.ConfiguredWidgets() {
+		d.widgets[widget.ID] = widget.New()
+	}
 }
 
-// OnMount Check if the app is installable and set the state according.
+// OnMount Check if the app is installable, set the state according, read the layout and follow the theme and language.
 func (d *Dashboard) OnMount(ctx app.Context) {
 	d.isAppInstallable = ctx.IsAppInstallable()
+	d.Layout = getLayout(ctx)
+	component.ObserveTheme(ctx, &d.Theme)
+	component.ObserveLanguage(ctx, &d.Lang, d.setDocumentLanguage)
+	d.setDocumentLanguage()
+	d.videoPaused = getVideoPaused(ctx)
+	component.ObserveLiteMode(ctx, &d.lite, func() {
+		// The video element is back after the next render.
+		ctx.Defer(func(app.Context) { playBackgroundVideo(d.videoPaused || d.lite) })
+	})
+	playBackgroundVideo(d.videoPaused || d.lite)
+	d.offline = !component.Online()
+	ctx.ObserveState(component.StaleDataState, &d.staleSince)
+	d.listenConnection(ctx)
+}
+
+// OnDismount stops following the connection.
+func (d *Dashboard) OnDismount() {
+	if d.releaseConnectionListeners != nil {
+		d.releaseConnectionListeners()
+	}
+}
+
+// listenConnection follows the online and offline events of the browser.
+// Refreshes are queued while offline, the components load their data again
+// once the connection comes back.
+func (d *Dashboard) listenConnection(ctx app.Context) {
+	onChange := app.FuncOf(func(app.Value, []app.Value) any {
+		ctx.Dispatch(func(ctx app.Context) {
+			d.offline = !component.Online()
+			if !d.offline {
+				component.Refresh(ctx)
+			}
+		})
+		return nil
+	})
+	window := app.Window()
+	window.Call("addEventListener", "online", onChange)
+	window.Call("addEventListener", "offline", onChange)
+	d.releaseConnectionListeners = func() {
+		window.Call("removeEventListener", "online", onChange)
+		window.Call("removeEventListener", "offline", onChange)
+		onChange.Release()
+	}
+}
+
+// OnPreRender renders the Dashboard in the language of the page on the server,
+// in lite mode when the request asks to save data.
+func (d *Dashboard) OnPreRender(ctx app.Context) {
+	d.Lang = component.DefaultLanguage(ctx)
+	d.lite = component.SavesData(ctx)
+}
+
+// setDocumentLanguage keeps the lang attribute of the page in sync for screen readers and hyphenation.
+func (d *Dashboard) setDocumentLanguage() {
+	app.Window().Get("document").Get("documentElement").Set("lang", d.Lang)
 }
 
 // OnAppInstallChange Check if the app is installable and set the state accordingly.
@@ -38,64 +126,188 @@ func (d *Dashboard) OnAppUpdate(ctx app.Context) {
 
 // Render The Render method is where the component appearance is defined.
 func (d *Dashboard) Render() app.UI {
-	return app.Div().ID("dashboard").Body(
-		app.Video().Style("width", "110vw").Style("height", "110vh").Style("object-fit", "cover").
-		Style("position", "fixed").Style("z-index", "-1").Style("top", "0").Style("left", "0").
-			ID("bg-video").Muted(true).Loop(true).AutoPlay(true).Src("/web/background-video.mp4"),
-		app.Div().Class("container mt-6").Body(
-			app.H1().Class("text-center p-2").Text("Elder Scrolls Online"),
-			app.Div().Style("width", "100%").Style("height", "100%").Class("row mt-4 d-flex align-items-stretch").Body(
-				// System Status Card
-				app.Div().Class("col-md-4 mt-4").Body(
-					app.Div().Class("card flex d-flex flex-column h-100").Body(
-						app.Div().Class("card-header text-center bg-primary text-white").Text("ESO Server Status"),
-						app.Div().Class(
-							"card-body flex-grow-1 text-center flex-row d-flex align-items-center justify-content-center m-2",
-							).Body(
-							app.Div().Style("width", "100%").Body(
-								app.Ul().Class("list-group").ID("serverStatusList").Body(
-									&d.ServerStatus,
-								),
-							),
-						),
-					),
-				),
-				// RSS Feed Card
-				app.Div().Class("col-md-8 mt-4").Body(
-					app.Div().Class("card d-flex flex-column h-100").Body(
-						app.Div().Class("card-header text-center bg-success text-white").Text("Latest ESO News"),
-						app.Div().ID("rss-feed").
-							Class("card-body flex-grow-1 m-4").Body(&d.RSSFeed),
-					),
-				),
-			),
-			// Additional Stats
-			app.Div().Style("width", "100%").Class("row mt-4").Body(
-				app.Div().Class("col-md-4").Body(
-					app.Div().Class("card text-center").Body(
-						app.Div().Class("card-header bg-info text-white").Text("Active Users"),
-						app.Div().Class("card-body").Body(
-							app.H5().Class("card-title").ID("activeUsers").Body(&d.CurrentPlayers),
-						),
-					),
-				),
-				app.Div().Class("col-md-4").Body(
-					app.Div().Class("card text-center").Body(
-						app.Div().Class("card-header bg-warning text-white").Text("24 Hour Peak"),
-						app.Div().Class("card-body").Body(
-							app.H5().Class("card-title").ID("24Peak").Body(&d.PeakPlayerCount),
-						),
-					),
-				),
-				app.Div().Class("col-md-4").Body(
-					app.Div().Class("card text-center").Body(
-						app.Div().Class("card-header bg-danger text-white").Text("All-Time Peak"),
-						app.Div().Class("card-body").Body(
-							app.H5().Class("card-title").ID("allPeak").Body(&d.AllPeakPlayerCount),
-						),
-					),
-				),
+	// The layout is read on mount, pre-rendered pages use the default one.
+	layout := d.Layout
+	if len(layout.Cards) == 0 {
+		layout = defaultLayout()
+	}
+
+	columns := make([]app.UI, 0, len(layout.Cards))
+	for _, placement := range layout.Cards {
+		widget, ok := component.LookupWidget(placement.Card)
+		compo, created := d.widgets[placement.Card]
+		if !ok || !created || (placement.Hidden && !d.editing) {
+			continue
+		}
+		columns = append(columns, d.renderCard(placement, widget, compo))
+	}
+
+	theme := component.LookupTheme(d.Theme)
+	colorMode := "light"
+	if theme.Dark {
+		colorMode = "dark"
+	}
+
+	return app.Div().ID("dashboard").Attr("data-theme", theme.ID).Attr("data-bs-theme", colorMode).Body(
+		app.Div().ID("bg-poster").Aria("hidden", true),
+		app.If(!d.lite, func() app.UI {
+			return app.Video().Style("width", "110vw").Style("height", "110vh").Style("object-fit", "cover").
+				Style("position", "fixed").Style("z-index", "-1").Style("top", "0").Style("left", "0").
+				ID(backgroundVideoID).Aria("hidden", true).Muted(true).Loop(true).Preload("none").Src("/web/background-video.mp4")
+		}).Else(func() app.UI {
+			// Lite mode keeps the place of the video, so switching it doesn't
+			// move the elements after it, which would mount the switchers twice.
+			return app.Div().Aria("hidden", true)
+		}),
+		app.A().Href("#dashboard-cards").Class("visually-hidden-focusable skip-link").Text(i18n.T(d.Lang, "Skip to the cards")).
+			OnClick(func(_ app.Context, e app.Event) {
+				e.PreventDefault()
+				app.Window().GetElementByID("dashboard-cards").Call("focus")
+			}),
+		app.Main().Class("container mt-6").Body(
+			app.H1().Class("text-center p-2").Text(i18n.T(d.Lang, "Elder Scrolls Online")),
+			d.renderToolbar(),
+			d.renderStaleBanner(),
+			app.Div().Style("width", "100%").Class("row d-flex align-items-stretch").ID("dashboard-cards").TabIndex(-1).Body(columns...),
+		),
+	)
+}
+
+// renderStaleBanner tells when the data shown was taken, when the dashboard
+// is a static export, offline or couldn't refresh it.
+func (d *Dashboard) renderStaleBanner() app.UI {
+	message := ""
+	switch {
+	case component.Static() && !d.staleSince.IsZero():
+		message = i18n.T(d.Lang, "Read-only copy — data from %s", i18n.FormatDateTime(d.Lang, d.staleSince))
+	case component.Static():
+		message = i18n.T(d.Lang, "Read-only copy")
+	case d.offline && !d.staleSince.IsZero():
+		message = i18n.T(d.Lang, "Offline — data from %s", i18n.FormatDateTime(d.Lang, d.staleSince))
+	case d.offline:
+		message = i18n.T(d.Lang, "Offline — the data refreshes once you're back online")
+	case !d.staleSince.IsZero():
+		message = i18n.T(d.Lang, "Couldn't refresh — data from %s", i18n.FormatDateTime(d.Lang, d.staleSince))
+	}
+
+	return app.Div().Role("status").Body(
+		app.If(message != "", func() app.UI {
+			return app.Div().Class("alert alert-warning text-center mt-3 mb-0 py-2").Text(message)
+		}),
+	)
+}
+
+// renderCard renders the card of a widget in its grid column. In edit mode the column can be
+// dragged onto another one to take its place.
+func (d *Dashboard) renderCard(placement CardLayout, widget component.Widget, compo app.Composer) app.UI {
+	body := app.Div().Class("card-body " + widget.BodyClass).Body(widget.Body(compo))
+	if widget.BodyID != "" {
+		body = body.ID(widget.BodyID)
+	}
+	if widget.Live {
+		body = body.Aria("live", "polite")
+	}
+	titleID := "card-" + widget.ID + "-title"
+
+	column := app.Div().Class("col-md-" + strconv.Itoa(placement.Width) + " mt-4")
+	if d.editing {
+		column = column.Class("dashboard-editing").Draggable(true).
+			OnDragStart(func(_ app.Context, e app.Event) {
+				d.dragging = placement.Card
+				// Firefox only starts dragging when data is set.
+				e.Get("dataTransfer").Call("setData", "text/plain", placement.Card)
+			}).
+			OnDragOver(func(_ app.Context, e app.Event) { e.PreventDefault() }).
+			OnDrop(func(ctx app.Context, e app.Event) {
+				e.PreventDefault()
+				d.updateLayout(ctx, d.Layout.move(d.dragging, placement.Card))
+				d.dragging = ""
+			})
+		if placement.Hidden {
+			column = column.Style("opacity", "0.5")
+		}
+	}
+
+	return column.Body(
+		app.Section().Class("card h-100 "+widget.CardClass).Aria("labelledby", titleID).Body(
+			app.Div().Class("card-header text-center text-white "+widget.HeaderClass).Body(
+				app.H2().Class("card-header-title").ID(titleID).Text(i18n.T(d.Lang, widget.Title)),
+				app.If(d.editing, func() app.UI { return d.renderCardControls(placement) }),
 			),
+			body,
 		),
 	)
-}
\ No newline at end of file
+}
+
+// renderCardControls renders the buttons that move, resize and hide a card,
+// so the layout can also be edited without dragging.
+func (d *Dashboard) renderCardControls(placement CardLayout) app.UI {
+	control := func(label, title string, change func(Layout) Layout) app.UI {
+		return app.Button().Class("btn btn-sm btn-outline-light py-0 px-1 ms-1").Title(i18n.T(d.Lang, title)).Aria("label", i18n.T(d.Lang, title)).Text(label).
+			OnClick(func(ctx app.Context, _ app.Event) { d.updateLayout(ctx, change(d.Layout)) })
+	}
+
+	hideLabel, hideTitle := i18n.T(d.Lang, "Hide"), "Hide card"
+	if placement.Hidden {
+		hideLabel, hideTitle = i18n.T(d.Lang, "Show"), "Show card"
+	}
+
+	return app.Div().Class("d-flex justify-content-center flex-wrap mt-1").Body(
+		control("←", "Move earlier", func(l Layout) Layout { return l.shift(placement.Card, -1) }),
+		control("→", "Move later", func(l Layout) Layout { return l.shift(placement.Card, 1) }),
+		control("−", "Narrower", func(l Layout) Layout { return l.resize(placement.Card, -1) }),
+		control("+", "Wider", func(l Layout) Layout { return l.resize(placement.Card, 1) }),
+		control(hideLabel, hideTitle, func(l Layout) Layout { return l.toggleHidden(placement.Card) }),
+	)
+}
+
+// renderToolbar renders the theme and language switchers, the lite mode,
+// install and background video controls and the buttons that enter and leave edit mode
+// and reset the layout.
+func (d *Dashboard) renderToolbar() app.UI {
+	editLabel := i18n.T(d.Lang, "Customize layout")
+	if d.editing {
+		editLabel = i18n.T(d.Lang, "Done")
+	}
+	videoLabel := i18n.T(d.Lang, "Pause background video")
+	if d.videoPaused {
+		videoLabel = i18n.T(d.Lang, "Play background video")
+	}
+
+	return app.Div().Style("width", "100%").Class("d-flex justify-content-between align-items-center").Body(
+		app.Div().Class("d-flex gap-2").Body(
+			&d.ThemeSwitcher,
+			&d.LanguageSwitcher,
+			app.Button().Class("btn btn-sm btn-outline-warning").Aria("pressed", d.lite).
+				Title(i18n.T(d.Lang, "Save data: no background video, smaller images and less frequent updates")).
+				Text(i18n.T(d.Lang, "Lite mode")).
+				OnClick(func(ctx app.Context, _ app.Event) { component.SetLiteMode(ctx, !d.lite) }),
+			app.If(d.isAppInstallable, func() app.UI {
+				return app.Button().Class("btn btn-sm btn-outline-warning").Text(i18n.T(d.Lang, "Install app")).
+					OnClick(func(ctx app.Context, _ app.Event) { ctx.ShowAppInstallPrompt() })
+			}),
+			app.If(!d.lite, func() app.UI {
+				return app.Button().Class("btn btn-sm btn-outline-warning").Text(videoLabel).
+					OnClick(func(ctx app.Context, _ app.Event) {
+						d.videoPaused = !d.videoPaused
+						setVideoPaused(ctx, d.videoPaused)
+						playBackgroundVideo(d.videoPaused)
+					})
+			}),
+		),
+		app.Div().Body(
+			app.If(d.editing, func() app.UI {
+				return app.Button().Class("btn btn-sm btn-outline-warning me-2").Text(i18n.T(d.Lang, "Reset layout")).
+					OnClick(func(ctx app.Context, _ app.Event) { d.updateLayout(ctx, defaultLayout()) })
+			}),
+			app.Button().Class("btn btn-sm btn-outline-warning").Text(editLabel).
+				OnClick(func(app.Context, app.Event) { d.editing = !d.editing }),
+		),
+	)
+}
+
+// updateLayout applies and stores a layout change.
+func (d *Dashboard) updateLayout(ctx app.Context, layout Layout) {
+	d.Layout = layout
+	setLayout(ctx, layout)
+}
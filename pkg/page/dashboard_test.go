@@ -0,0 +1,144 @@
+package page_test
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/a11y"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/i18n"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/page"
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// newsTitle is the headline of the stubbed news feed.
+const newsTitle = "Maintenance for the PC megaservers"
+
+// upstream answers the requests of the dashboard components with saved
+// pages, so the pre-rendered cards show data without network access.
+type upstream struct {
+	t *testing.T
+}
+
+// RoundTrip serves the stub of the requested source, 404 for unknown ones.
+func (u upstream) RoundTrip(req *http.Request) (*http.Response, error) {
+	target := req.URL.String()
+	if req.URL.Host == "api.allorigins.win" {
+		target = req.URL.Query().Get("url")
+	}
+
+	recorder := httptest.NewRecorder()
+	switch {
+	case strings.Contains(target, "GetNumberOfCurrentPlayers"):
+		_, _ = recorder.WriteString(`{"response": {"player_count": 12000, "result": 1}}`)
+	case strings.Contains(target, "steamcharts.com"):
+		u.serveFile(recorder, "steamcharts", "current.html")
+	case strings.Contains(target, "esoserverstatus.net"):
+		u.serveFile(recorder, "esostatus", "maintenance.html")
+	case strings.Contains(target, "rss2json.com"):
+		u.serveNews(recorder)
+	default:
+		recorder.WriteHeader(http.StatusNotFound)
+	}
+
+	resp := recorder.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// serveFile writes a saved page of the testdata of the package parsing it,
+// so the fixtures exist once.
+func (u upstream) serveFile(w http.ResponseWriter, pkg, name string) {
+	data, err := os.ReadFile(filepath.Join("..", pkg, "testdata", name))
+	if err != nil {
+		u.t.Error(err)
+	}
+	_, _ = w.Write(data)
+}
+
+// serveNews writes a feed announcing maintenance next week.
+func (u upstream) serveNews(w http.ResponseWriter) {
+	start := time.Now().UTC().AddDate(0, 0, 7).Truncate(time.Hour)
+	feed := map[string]any{"items": []map[string]any{{
+		"title":       newsTitle,
+		"link":        "https://eso-hub.com/en/news/maintenance",
+		"description": "From " + start.Format("2006-01-02 15:04") + " UTC to " + start.Add(4*time.Hour).Format("2006-01-02 15:04") + " UTC.",
+		"thumbnail":   "https://eso-hub.com/storage/news/maintenance.jpg",
+		"pubDate":     time.Now().UTC().Format("2006-01-02 15:04:05"),
+		"categories":  []string{"Maintenance"},
+	}}}
+	if err := json.NewEncoder(w).Encode(feed); err != nil {
+		u.t.Error(err)
+	}
+}
+
+// preRender returns the dashboard the server sends on a first visit in lang
+// with the request headers of header.
+func preRender(t *testing.T, lang string, header http.Header) string {
+	t.Helper()
+	handler := &app.Handler{Title: "ESO Dashboard", Lang: lang}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	maps.Copy(req.Header, header)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, component.WithSaveData(req))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("pre-rendering %s: status %d", lang, recorder.Code)
+	}
+	return recorder.Body.String()
+}
+
+// stubUpstream routes the dashboard to the stubbed sources for the test.
+func stubUpstream(t *testing.T) {
+	transport := http.DefaultTransport
+	http.DefaultTransport = upstream{t: t}
+	t.Cleanup(func() { http.DefaultTransport = transport })
+	app.Route("/", func() app.Composer { return &page.Dashboard{} })
+}
+
+func TestDashboardAccessibility(t *testing.T) {
+	stubUpstream(t)
+
+	for _, language := range i18n.Languages {
+		t.Run(language.Code, func(t *testing.T) {
+			html := preRender(t, language.Code, nil)
+			// The stubs must have been used, or only the loading and error
+			// states would be checked.
+			if !strings.Contains(html, newsTitle) || !strings.Contains(html, "PC-EU") {
+				t.Fatal("the pre-rendered page doesn't show the stubbed news and status")
+			}
+
+			violations, err := a11y.Check(strings.NewReader(html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, violation := range violations {
+				t.Error(violation)
+			}
+		})
+	}
+}
+
+func TestDashboardSavesData(t *testing.T) {
+	stubUpstream(t)
+
+	if html := preRender(t, i18n.Default, nil); !strings.Contains(html, "background-video.mp4") {
+		t.Error("the pre-rendered page doesn't play the background video")
+	}
+	for _, value := range []string{"on", " On "} {
+		html := preRender(t, i18n.Default, http.Header{"Save-Data": {value}})
+		if strings.Contains(html, "background-video.mp4") {
+			t.Errorf("Save-Data: %q pre-rendered the background video, want the lite page", value)
+		}
+	}
+	if html := preRender(t, i18n.Default, http.Header{"Save-Data": {"off"}}); !strings.Contains(html, "background-video.mp4") {
+		t.Error("Save-Data: off pre-rendered the lite page")
+	}
+}
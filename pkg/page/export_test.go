@@ -0,0 +1,9 @@
+package page
+
+// The layout operations, exposed to the tests.
+var (
+	NormalizeLayout = Layout.normalize
+	MoveCard        = Layout.move
+	ShiftCard       = Layout.shift
+	ResizeCard      = Layout.resize
+)
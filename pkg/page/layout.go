@@ -0,0 +1,134 @@
+package page
+
+import (
+	"slices"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/component"
+	"github.com/maxence-charriere/go-app/v10/pkg/app"
+)
+
+// cardSizes are the Bootstrap column widths a card can take, smallest first.
+var cardSizes = []int{3, 4, 6, 8, 12}
+
+// CardLayout places one card: its position is its index in Layout.Cards.
+type CardLayout struct {
+	// Card is the ID of the widget shown, e.g. "serverStatus".
+	Card string `json:"card"`
+	// Width is the number of Bootstrap grid columns the card spans on medium screens and up.
+	Width  int  `json:"width"`
+	Hidden bool `json:"hidden,omitempty"`
+}
+
+// Layout is the order, size and visibility of the dashboard cards. It is kept
+// in local storage per browser.
+type Layout struct {
+	Cards []CardLayout `json:"cards"`
+}
+
+// defaultLayout places the configured widgets in order at their default width.
+func defaultLayout() Layout {
+	widgets := component.ConfiguredWidgets()
+	layout := Layout{Cards: make([]CardLayout, 0, len(widgets))}
+	for _, widget := range widgets {
+		width := widget.Width
+		if !slices.Contains(cardSizes, width) {
+			width = 12
+		}
+		layout.Cards = append(layout.Cards, CardLayout{Card: widget.ID, Width: width})
+	}
+	return layout
+}
+
+// getLayout reads the layout from local storage.
+func getLayout(ctx app.Context) Layout {
+	layout := Layout{}
+	ctx.GetState("dashboardLayout", &layout)
+	return layout.normalize()
+}
+
+// setLayout stores the layout in local storage.
+func setLayout(ctx app.Context, layout Layout) {
+	ctx.SetState("dashboardLayout", layout).Persist()
+}
+
+// normalize drops cards that aren't configured and duplicates, fixes invalid
+// widths and appends widgets configured since the layout was saved.
+func (l Layout) normalize() Layout {
+	defaults := defaultLayout()
+
+	normalized := Layout{Cards: make([]CardLayout, 0, len(defaults.Cards))}
+	seen := map[string]bool{}
+	for _, card := range l.Cards {
+		i := defaults.index(card.Card)
+		if i < 0 || seen[card.Card] {
+			continue
+		}
+		seen[card.Card] = true
+		if !slices.Contains(cardSizes, card.Width) {
+			card.Width = defaults.Cards[i].Width
+		}
+		normalized.Cards = append(normalized.Cards, card)
+	}
+	for _, card := range defaults.Cards {
+		if !seen[card.Card] {
+			normalized.Cards = append(normalized.Cards, card)
+		}
+	}
+	return normalized
+}
+
+// index returns the position of the card, -1 if it isn't in the layout.
+func (l Layout) index(card string) int {
+	return slices.IndexFunc(l.Cards, func(c CardLayout) bool { return c.Card == card })
+}
+
+// move places card at the position of target.
+func (l Layout) move(card, target string) Layout {
+	from, to := l.index(card), l.index(target)
+	if from < 0 || to < 0 || from == to {
+		return l
+	}
+
+	cards := slices.Clone(l.Cards)
+	moved := cards[from]
+	cards = slices.Delete(cards, from, from+1)
+	cards = slices.Insert(cards, to, moved)
+	return Layout{Cards: cards}
+}
+
+// shift moves card by offset positions, staying within the layout.
+func (l Layout) shift(card string, offset int) Layout {
+	from := l.index(card)
+	to := from + offset
+	if from < 0 || to < 0 || to >= len(l.Cards) {
+		return l
+	}
+	return l.move(card, l.Cards[to].Card)
+}
+
+// resize makes card step sizes wider, or narrower for negative steps.
+func (l Layout) resize(card string, steps int) Layout {
+	i := l.index(card)
+	if i < 0 {
+		return l
+	}
+
+	size := slices.Index(cardSizes, l.Cards[i].Width) + steps
+	size = max(0, min(size, len(cardSizes)-1))
+
+	cards := slices.Clone(l.Cards)
+	cards[i].Width = cardSizes[size]
+	return Layout{Cards: cards}
+}
+
+// toggleHidden hides or shows card.
+func (l Layout) toggleHidden(card string) Layout {
+	i := l.index(card)
+	if i < 0 {
+		return l
+	}
+
+	cards := slices.Clone(l.Cards)
+	cards[i].Hidden = !cards[i].Hidden
+	return Layout{Cards: cards}
+}
@@ -0,0 +1,156 @@
+package page_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/page"
+)
+
+// cards is a layout of three cards, in the order "a", "b", "c".
+var cards = page.Layout{Cards: []page.CardLayout{
+	{Card: "a", Width: 4},
+	{Card: "b", Width: 8},
+	{Card: "c", Width: 12},
+}}
+
+// describe writes a layout as "card:width" fields, hidden cards marked with
+// a trailing "-".
+func describe(layout page.Layout) string {
+	fields := make([]string, 0, len(layout.Cards))
+	for _, card := range layout.Cards {
+		field := fmt.Sprintf("%s:%d", card.Card, card.Width)
+		if card.Hidden {
+			field += "-"
+		}
+		fields = append(fields, field)
+	}
+	return strings.Join(fields, " ")
+}
+
+func TestMoveCard(t *testing.T) {
+	tests := []struct {
+		card, target string
+		want         string
+	}{
+		{card: "a", target: "c", want: "b:8 c:12 a:4"},
+		{card: "c", target: "a", want: "c:12 a:4 b:8"},
+		{card: "b", target: "c", want: "a:4 c:12 b:8"},
+		{card: "b", target: "b", want: "a:4 b:8 c:12"},
+		{card: "unknown", target: "a", want: "a:4 b:8 c:12"},
+		{card: "a", target: "unknown", want: "a:4 b:8 c:12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.card+" to "+tt.target, func(t *testing.T) {
+			if got := describe(page.MoveCard(cards, tt.card, tt.target)); got != tt.want {
+				t.Errorf("move() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+	if got := describe(cards); got != "a:4 b:8 c:12" {
+		t.Errorf("the moved layout changed to %s", got)
+	}
+}
+
+func TestShiftCard(t *testing.T) {
+	tests := []struct {
+		card   string
+		offset int
+		want   string
+	}{
+		{card: "a", offset: 1, want: "b:8 a:4 c:12"},
+		{card: "c", offset: -1, want: "a:4 c:12 b:8"},
+		{card: "a", offset: 2, want: "b:8 c:12 a:4"},
+		// The first and the last card stay at the edges.
+		{card: "a", offset: -1, want: "a:4 b:8 c:12"},
+		{card: "c", offset: 1, want: "a:4 b:8 c:12"},
+		{card: "b", offset: 5, want: "a:4 b:8 c:12"},
+		{card: "unknown", offset: 1, want: "a:4 b:8 c:12"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s by %d", tt.card, tt.offset), func(t *testing.T) {
+			if got := describe(page.ShiftCard(cards, tt.card, tt.offset)); got != tt.want {
+				t.Errorf("shift() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResizeCard(t *testing.T) {
+	tests := []struct {
+		card  string
+		steps int
+		want  string
+	}{
+		{card: "a", steps: 1, want: "a:6 b:8 c:12"},
+		{card: "b", steps: -2, want: "a:4 b:4 c:12"},
+		// The widths stop at the smallest and the full width.
+		{card: "a", steps: -5, want: "a:3 b:8 c:12"},
+		{card: "c", steps: 1, want: "a:4 b:8 c:12"},
+		{card: "unknown", steps: 1, want: "a:4 b:8 c:12"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s by %d", tt.card, tt.steps), func(t *testing.T) {
+			if got := describe(page.ResizeCard(cards, tt.card, tt.steps)); got != tt.want {
+				t.Errorf("resize() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// A stored width that isn't a card size starts from the smallest one.
+	odd := page.Layout{Cards: []page.CardLayout{{Card: "a", Width: 5}}}
+	if got := describe(page.ResizeCard(odd, "a", 1)); got != "a:3" {
+		t.Errorf("resize() of an invalid width = %s, want a:3", got)
+	}
+}
+
+func TestNormalizeLayout(t *testing.T) {
+	t.Setenv(constant.WidgetsEnv, "serverStatus,news,maintenance")
+	tests := []struct {
+		name   string
+		stored page.Layout
+		want   string
+	}{
+		{
+			name: "empty",
+			want: "serverStatus:4 news:8 maintenance:12",
+		},
+		{
+			name: "reordered and resized",
+			stored: page.Layout{Cards: []page.CardLayout{
+				{Card: "maintenance", Width: 6},
+				{Card: "news", Width: 12, Hidden: true},
+				{Card: "serverStatus", Width: 3},
+			}},
+			want: "maintenance:6 news:12- serverStatus:3",
+		},
+		{
+			name: "unknown widgets and duplicates",
+			stored: page.Layout{Cards: []page.CardLayout{
+				{Card: "removedWidget", Width: 4},
+				{Card: "news", Width: 4},
+				{Card: "population", Width: 12},
+				{Card: "news", Width: 12},
+			}},
+			want: "news:4 serverStatus:4 maintenance:12",
+		},
+		{
+			name: "invalid widths",
+			stored: page.Layout{Cards: []page.CardLayout{
+				{Card: "serverStatus", Width: 0},
+				{Card: "news", Width: 13},
+				{Card: "maintenance", Width: -6},
+			}},
+			want: "serverStatus:4 news:8 maintenance:12",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describe(page.NormalizeLayout(tt.stored)); got != tt.want {
+				t.Errorf("normalize() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Package poller periodically collects the dashboard data on the server and
+// hands every new snapshot to the registered listeners, e.g. notifiers.
+package poller
+
+import (
+	"context"
+	"log"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+)
+
+// Snapshot is the data collected by one poll.
+type Snapshot struct {
+	Time   time.Time        `json:"time"`
+	Status esostatus.Report `json:"status"`
+	// Players is the current player count of the default game, 0 if it couldn't be fetched.
+	Players int `json:"players"`
+	// Known is the last known state of every region, carried over from
+	// earlier polls while the state of a region is unknown.
+	Known map[string]esostatus.State `json:"known,omitempty"`
+}
+
+// Change is a region whose state differs from its last known one.
+type Change struct {
+	Region   esostatus.RegionStatus `json:"region"`
+	Previous esostatus.State        `json:"previous"`
+}
+
+// Listener is called after every successful poll with the previous snapshot,
+// zero on the first poll, and the current one.
+type Listener func(ctx context.Context, previous, current Snapshot)
+
+// Poller fetches a snapshot every Interval.
+type Poller struct {
+	Interval     time.Duration
+	FetchStatus  func(ctx context.Context) (esostatus.Report, error)
+	FetchPlayers func(ctx context.Context) (int, error)
+
+	mu        sync.Mutex
+	last      Snapshot
+	listeners []Listener
+}
+
+// New returns a poller collecting the status and player count with the given
+// fetchers. fetchPlayers may be nil to leave the player count out.
+func New(
+	interval time.Duration,
+	fetchStatus func(ctx context.Context) (esostatus.Report, error),
+	fetchPlayers func(ctx context.Context) (int, error),
+) *Poller {
+	return &Poller{Interval: interval, FetchStatus: fetchStatus, FetchPlayers: fetchPlayers}
+}
+
+// Subscribe registers a listener. It must be called before Run.
+func (p *Poller) Subscribe(listener Listener) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.listeners = append(p.listeners, listener)
+}
+
+// Latest returns the last snapshot, zero before the first successful poll.
+func (p *Poller) Latest() Snapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.last
+}
+
+// Poll collects a snapshot and notifies the listeners. A failing player count
+// is logged and doesn't fail the poll.
+func (p *Poller) Poll(ctx context.Context) error {
+	status, err := p.FetchStatus(ctx)
+	if err != nil {
+		return err
+	}
+	current := Snapshot{Time: time.Now(), Status: status}
+
+	if p.FetchPlayers != nil {
+		if current.Players, err = p.FetchPlayers(ctx); err != nil {
+			log.Println("Error polling player count:", err)
+		}
+	}
+
+	p.mu.Lock()
+	previous := p.last
+	current.Known = knownStates(previous.Known, status)
+	p.last = current
+	listeners := append([]Listener(nil), p.listeners...)
+	p.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(ctx, previous, current)
+	}
+	return nil
+}
+
+// Run polls right away and then every Interval until ctx is done.
+func (p *Poller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Poll(ctx); err != nil {
+			log.Println("Error polling dashboard data:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// knownStates returns the known states updated with the regions of report
+// whose state is known.
+func knownStates(known map[string]esostatus.State, report esostatus.Report) map[string]esostatus.State {
+	known = maps.Clone(known)
+	if known == nil {
+		known = map[string]esostatus.State{}
+	}
+	for _, status := range report.Regions {
+		if status.State != esostatus.StateUnknown {
+			known[status.Region] = status.State
+		}
+	}
+	return known
+}
+
+// Changes returns the regions whose known state differs from the last known
+// one of the previous snapshot, so a region going from online to offline
+// through an unknown state still changes. Snapshots without Known states
+// are compared by their status.
+func Changes(previous, current Snapshot) []Change {
+	var changes []Change
+	for _, status := range current.Status.Regions {
+		before, ok := previous.Known[status.Region]
+		if !ok {
+			before = previous.Status.Region(status.Region).State
+		}
+		if before == esostatus.StateUnknown || status.State == esostatus.StateUnknown || before == status.State {
+			continue
+		}
+		changes = append(changes, Change{Region: status, Previous: before})
+	}
+	return changes
+}
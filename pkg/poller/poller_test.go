@@ -0,0 +1,109 @@
+package poller_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/esostatus"
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/poller"
+)
+
+// report returns a status report with the region in the state.
+func report(region string, state esostatus.State) esostatus.Report {
+	return esostatus.Report{Regions: []esostatus.RegionStatus{{Region: region, State: state}}}
+}
+
+func TestPoll(t *testing.T) {
+	states := []esostatus.State{esostatus.StateOnline, esostatus.StateOffline}
+	polls := 0
+	p := poller.New(time.Minute,
+		func(context.Context) (esostatus.Report, error) {
+			return report("PC-EU", states[polls]), nil
+		},
+		func(context.Context) (int, error) {
+			polls++
+			if polls == 2 {
+				return 0, errors.New("steam charts down")
+			}
+			return 12000, nil
+		},
+	)
+
+	var changes [][]poller.Change
+	p.Subscribe(func(_ context.Context, previous, current poller.Snapshot) {
+		changes = append(changes, poller.Changes(previous, current))
+	})
+
+	for range states {
+		if err := p.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(changes) != 2 || len(changes[0]) != 0 {
+		t.Fatalf("changes = %+v, want none on the first poll", changes)
+	}
+	if len(changes[1]) != 1 || changes[1][0].Previous != esostatus.StateOnline || changes[1][0].Region.State != esostatus.StateOffline {
+		t.Errorf("changes = %+v, want PC-EU going offline", changes[1])
+	}
+	// A failing player count doesn't fail the poll.
+	if latest := p.Latest(); latest.Players != 0 || latest.Status.Region("PC-EU").State != esostatus.StateOffline {
+		t.Errorf("Latest() = %+v, want the second poll without players", latest)
+	}
+}
+
+func TestPollStatusError(t *testing.T) {
+	p := poller.New(time.Minute, func(context.Context) (esostatus.Report, error) {
+		return esostatus.Report{}, errors.New("every provider failed")
+	}, nil)
+	p.Subscribe(func(context.Context, poller.Snapshot, poller.Snapshot) {
+		t.Error("listener called for a failed poll")
+	})
+
+	if err := p.Poll(context.Background()); err == nil {
+		t.Error("Poll() succeeded, want the status error")
+	}
+	if !p.Latest().Time.IsZero() {
+		t.Error("Latest() changed after a failed poll")
+	}
+}
+
+func TestChangesIgnoresUnknown(t *testing.T) {
+	previous := poller.Snapshot{Status: report("PC-EU", esostatus.StateUnknown)}
+	current := poller.Snapshot{Status: report("PC-EU", esostatus.StateOnline)}
+	if changes := poller.Changes(previous, current); len(changes) > 0 {
+		t.Errorf("Changes() = %+v, want none from an unknown state", changes)
+	}
+	if changes := poller.Changes(current, previous); len(changes) > 0 {
+		t.Errorf("Changes() = %+v, want none to an unknown state", changes)
+	}
+}
+
+func TestChangesAcrossUnknown(t *testing.T) {
+	states := []esostatus.State{esostatus.StateOnline, esostatus.StateUnknown, esostatus.StateUnknown, esostatus.StateOffline, esostatus.StateOffline}
+	polls := 0
+	p := poller.New(time.Minute, func(context.Context) (esostatus.Report, error) {
+		polls++
+		return report("PC-EU", states[polls-1]), nil
+	}, nil)
+
+	var changes []poller.Change
+	p.Subscribe(func(_ context.Context, previous, current poller.Snapshot) {
+		changes = append(changes, poller.Changes(previous, current)...)
+	})
+	for range states {
+		if err := p.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Online, unknown twice and offline is one change, reported once.
+	if len(changes) != 1 || changes[0].Previous != esostatus.StateOnline || changes[0].Region.State != esostatus.StateOffline {
+		t.Errorf("changes = %+v, want PC-EU going from online to offline", changes)
+	}
+	if known := p.Latest().Known["PC-EU"]; known != esostatus.StateOffline {
+		t.Errorf("known state = %s, want offline", known)
+	}
+}
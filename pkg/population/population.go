@@ -0,0 +1,146 @@
+// Package population collects player population estimates from sources other
+// than Steam, e.g. console tracker sites, so the dashboard isn't Steam-only.
+package population
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+)
+
+// Confidence tells how reliable an estimate is.
+type Confidence string
+
+const (
+	ConfidenceHigh   Confidence = "high"
+	ConfidenceMedium Confidence = "medium"
+	ConfidenceLow    Confidence = "low"
+)
+
+// ErrPathNotFound is returned when a JSON path doesn't match the document.
+var ErrPathNotFound = errors.New("json path not found")
+
+// Estimate is the number of players on one platform according to a source.
+type Estimate struct {
+	Platform   string     `json:"platform"`
+	Players    int        `json:"players"`
+	Source     string     `json:"source"`
+	Confidence Confidence `json:"confidence"`
+}
+
+// Provider supplies a population estimate for a platform.
+type Provider interface {
+	Estimate(ctx context.Context) (Estimate, error)
+}
+
+// JSONProvider reads an estimate from a JSON document at URL. Path selects the
+// number with dot separated object keys and array indexes, e.g. "data.0.players".
+type JSONProvider struct {
+	Platform   string     `json:"platform"`
+	Source     string     `json:"source"`
+	URL        string     `json:"url"`
+	Path       string     `json:"path"`
+	Confidence Confidence `json:"confidence"`
+}
+
+// ParseProviders parses a JSON array of JSONProvider configurations. An empty
+// config yields no providers.
+func ParseProviders(config string) ([]JSONProvider, error) {
+	if strings.TrimSpace(config) == "" {
+		return nil, nil
+	}
+
+	var providers []JSONProvider
+	if err := json.Unmarshal([]byte(config), &providers); err != nil {
+		return nil, fmt.Errorf("parsing population providers: %w", err)
+	}
+	for i, provider := range providers {
+		if provider.Platform == "" || provider.URL == "" {
+			return nil, fmt.Errorf("parsing population providers: entry %d needs a platform and url", i)
+		}
+		if provider.Source == "" {
+			providers[i].Source = provider.URL
+		}
+		if provider.Confidence == "" {
+			providers[i].Confidence = ConfidenceLow
+		}
+	}
+	return providers, nil
+}
+
+// Estimate fetches the document and extracts the player count.
+func (p JSONProvider) Estimate(ctx context.Context) (Estimate, error) {
+	client := &http.Client{Timeout: constant.FetchTimeout}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL, nil)
+	if err != nil {
+		return Estimate{}, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Estimate{}, fmt.Errorf("fetching %s population: %w", p.Platform, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Estimate{}, fmt.Errorf("fetching %s population: unexpected status %s", p.Platform, resp.Status)
+	}
+
+	var document any
+	if err = json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return Estimate{}, fmt.Errorf("decoding %s population: %w", p.Platform, err)
+	}
+
+	players, err := ExtractInt(document, p.Path)
+	if err != nil {
+		return Estimate{}, fmt.Errorf("extracting %s population: %w", p.Platform, err)
+	}
+
+	return Estimate{Platform: p.Platform, Players: players, Source: p.Source, Confidence: p.Confidence}, nil
+}
+
+// ExtractInt walks the decoded JSON document along path and returns the
+// number found there. Numeric strings such as "12,345" are accepted too.
+func ExtractInt(document any, path string) (int, error) {
+	value := document
+	if path != "" {
+		for _, key := range strings.Split(path, ".") {
+			switch node := value.(type) {
+			case map[string]any:
+				next, ok := node[key]
+				if !ok {
+					return 0, fmt.Errorf("%w: %q", ErrPathNotFound, path)
+				}
+				value = next
+			case []any:
+				index, err := strconv.Atoi(key)
+				if err != nil || index < 0 || index >= len(node) {
+					return 0, fmt.Errorf("%w: %q", ErrPathNotFound, path)
+				}
+				value = node[index]
+			default:
+				return 0, fmt.Errorf("%w: %q", ErrPathNotFound, path)
+			}
+		}
+	}
+
+	switch number := value.(type) {
+	case float64:
+		return int(math.Round(number)), nil
+	case string:
+		players, err := strconv.Atoi(strings.ReplaceAll(strings.TrimSpace(number), ",", ""))
+		if err != nil {
+			return 0, fmt.Errorf("value at %q isn't a number: %w", path, err)
+		}
+		return players, nil
+	default:
+		return 0, fmt.Errorf("value at %q isn't a number", path)
+	}
+}
@@ -0,0 +1,142 @@
+package population_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/population"
+)
+
+func TestExtractInt(t *testing.T) {
+	const document = `{
+		"players": 1200,
+		"data": [{"platform": "xbox", "players": "12,345"}, {"players": 456.6}],
+		"stats": {"ps": {"online": {"now": " 789 "}}},
+		"labels": {"count": "many", "active": true, "missing": null, "nested": {"players": 1}},
+		"empty": []
+	}`
+	var decoded any
+	if err := json.Unmarshal([]byte(document), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path     string
+		want     int
+		notFound bool
+		wantErr  bool
+	}{
+		{path: "players", want: 1200},
+		{path: "data.0.players", want: 12345},
+		{path: "data.1.players", want: 457},
+		{path: "stats.ps.online.now", want: 789},
+		{path: "labels.count", wantErr: true},
+		{path: "labels.active", wantErr: true},
+		{path: "labels.missing", wantErr: true},
+		{path: "labels.nested", wantErr: true},
+		{path: "data", wantErr: true},
+		{path: "", wantErr: true},
+		{path: "stats.xbox", notFound: true},
+		{path: "data.2.players", notFound: true},
+		{path: "data.-1.players", notFound: true},
+		{path: "data.first.players", notFound: true},
+		{path: "empty.0", notFound: true},
+		{path: "players.count", notFound: true},
+		{path: "stats..ps", notFound: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := population.ExtractInt(decoded, tt.path)
+			switch {
+			case tt.notFound:
+				if !errors.Is(err, population.ErrPathNotFound) {
+					t.Errorf("ExtractInt() error = %v, want ErrPathNotFound", err)
+				}
+			case tt.wantErr:
+				if err == nil || errors.Is(err, population.ErrPathNotFound) {
+					t.Errorf("ExtractInt() = %d, %v, want an error for the value", got, err)
+				}
+			case err != nil || got != tt.want:
+				t.Errorf("ExtractInt() = %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+
+	// The root is the number when the path is empty.
+	if got, err := population.ExtractInt(float64(42), ""); err != nil || got != 42 {
+		t.Errorf("ExtractInt() of the root = %d, %v, want 42", got, err)
+	}
+}
+
+func TestParseProviders(t *testing.T) {
+	providers, err := population.ParseProviders(`[
+		{"platform": "Xbox", "url": "https://example.com/xbox.json", "path": "players"},
+		{"platform": "PlayStation", "source": "Tracker", "url": "https://example.com/ps.json", "confidence": "medium"}
+	]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []population.JSONProvider{
+		{Platform: "Xbox", Source: "https://example.com/xbox.json", URL: "https://example.com/xbox.json", Path: "players", Confidence: population.ConfidenceLow},
+		{Platform: "PlayStation", Source: "Tracker", URL: "https://example.com/ps.json", Confidence: population.ConfidenceMedium},
+	}
+	if len(providers) != len(want) || providers[0] != want[0] || providers[1] != want[1] {
+		t.Errorf("ParseProviders() = %+v, want %+v", providers, want)
+	}
+
+	for _, config := range []string{"", "  \n"} {
+		if providers, err := population.ParseProviders(config); err != nil || providers != nil {
+			t.Errorf("ParseProviders(%q) = %+v, %v, want no providers", config, providers, err)
+		}
+	}
+
+	for _, config := range []string{
+		`[{"platform": "Xbox", "url": "https://example.com"}`,
+		`{"platform": "Xbox", "url": "https://example.com"}`,
+		`[{"platform": "Xbox", "url": 1}]`,
+		`[{"platform": "Xbox"}]`,
+		`[{"url": "https://example.com"}]`,
+		`xbox=https://example.com`,
+	} {
+		if providers, err := population.ParseProviders(config); err == nil {
+			t.Errorf("ParseProviders(%s) = %+v, want an error", config, providers)
+		}
+	}
+}
+
+func TestJSONProviderEstimate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/players.json":
+			_, _ = w.Write([]byte(`{"data": [{"players": "12,345"}]}`))
+		case "/broken.json":
+			_, _ = w.Write([]byte(`{"data": [`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := population.JSONProvider{Platform: "Xbox", Source: "Tracker", URL: server.URL + "/players.json", Path: "data.0.players", Confidence: population.ConfidenceMedium}
+	estimate, err := provider.Estimate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (population.Estimate{Platform: "Xbox", Players: 12345, Source: "Tracker", Confidence: population.ConfidenceMedium}); estimate != want {
+		t.Errorf("Estimate() = %+v, want %+v", estimate, want)
+	}
+
+	for _, broken := range []population.JSONProvider{
+		{Platform: "Xbox", URL: server.URL + "/broken.json", Path: "data.0.players"},
+		{Platform: "Xbox", URL: server.URL + "/missing.json"},
+		{Platform: "Xbox", URL: server.URL + "/players.json", Path: "data.1.players"},
+	} {
+		if _, err := broken.Estimate(context.Background()); err == nil {
+			t.Errorf("Estimate() of %s succeeded", broken.URL)
+		}
+	}
+}
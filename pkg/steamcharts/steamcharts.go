@@ -0,0 +1,260 @@
+// Package steamcharts scrapes the player statistics SteamCharts publishes for
+// a Steam app: the live heading numbers and the monthly history table.
+package steamcharts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ErrLayoutChanged is returned when the page no longer has the expected structure.
+var ErrLayoutChanged = errors.New("steamcharts page layout changed")
+
+// Heading labels identifying the numbers in #app-heading.
+const (
+	labelCurrent     = "playing"
+	labelPeak        = "24-hour peak"
+	labelAllTimePeak = "all-time peak"
+)
+
+// Monthly table column headers.
+const (
+	columnMonth       = "month"
+	columnAvg         = "avg. players"
+	columnGain        = "gain"
+	columnGainPercent = "% gain"
+	columnPeak        = "peak players"
+)
+
+// monthLayout is the time layout of the month column, e.g. "September 2025".
+const monthLayout = "January 2006"
+
+// rollingLabel is the month column of the rolling row, which isn't a month
+// and may leave cells empty.
+const rollingLabel = "last 30 days"
+
+// Page is the parsed content of an app page.
+type Page struct {
+	Current     int     `json:"current"`
+	Peak        int     `json:"peak"`
+	AllTimePeak int     `json:"allTimePeak"`
+	Months      []Month `json:"months"`
+}
+
+// Month is one row of the monthly history table.
+type Month struct {
+	// Label is the month as shown on the page, e.g. "Last 30 Days".
+	Label string `json:"label"`
+	// Month is the first day of the month, zero for the rolling "Last 30 Days" row.
+	Month       time.Time `json:"month"`
+	Avg         float64   `json:"avg"`
+	Gain        float64   `json:"gain"`
+	GainPercent float64   `json:"gainPercent"`
+	Peak        int       `json:"peak"`
+}
+
+// AppURL returns the SteamCharts page for the Steam app ID.
+func AppURL(appID int) string {
+	return "https://steamcharts.com/app/" + strconv.Itoa(appID)
+}
+
+// Fetch downloads and parses the page at url.
+func Fetch(ctx context.Context, url string) (Page, error) {
+	client := &http.Client{Timeout: constant.FetchTimeout}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Page{}, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Page{}, fmt.Errorf("fetching steamcharts: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Page{}, fmt.Errorf("fetching steamcharts: unexpected status %s", resp.Status)
+	}
+
+	return Parse(resp.Body)
+}
+
+// Parse extracts the heading numbers and monthly table from an app page.
+func Parse(r io.Reader) (Page, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return Page{}, fmt.Errorf("parsing steamcharts: %w", err)
+	}
+
+	page, err := parseHeading(doc)
+	if err != nil {
+		return Page{}, err
+	}
+
+	page.Months, err = parseMonths(doc)
+	if err != nil {
+		return Page{}, err
+	}
+
+	return page, nil
+}
+
+// parseHeading reads the #app-heading numbers by their label rather than position.
+func parseHeading(doc *goquery.Document) (Page, error) {
+	stats := map[string]int{}
+	var parseErr error
+	doc.Find("#app-heading .app-stat").Each(func(_ int, s *goquery.Selection) {
+		num := s.Find(".num")
+		if num.Length() == 0 || parseErr != nil {
+			return
+		}
+
+		label := strings.TrimPrefix(strings.TrimSpace(s.Text()), strings.TrimSpace(num.Text()))
+		label = strings.ToLower(strings.TrimSpace(label))
+		for _, known := range []string{labelCurrent, labelPeak, labelAllTimePeak} {
+			if !strings.HasPrefix(label, known) {
+				continue
+			}
+			value, err := ParseInt(num.Text())
+			if err != nil {
+				parseErr = fmt.Errorf("%w: heading %q: %w", ErrLayoutChanged, known, err)
+				return
+			}
+			stats[known] = value
+		}
+	})
+	if parseErr != nil {
+		return Page{}, parseErr
+	}
+
+	for _, known := range []string{labelCurrent, labelPeak, labelAllTimePeak} {
+		if _, ok := stats[known]; !ok {
+			return Page{}, fmt.Errorf("%w: heading %q not found", ErrLayoutChanged, known)
+		}
+	}
+
+	return Page{
+		Current:     stats[labelCurrent],
+		Peak:        stats[labelPeak],
+		AllTimePeak: stats[labelAllTimePeak],
+	}, nil
+}
+
+// parseMonths reads the monthly history table, locating columns by header name.
+func parseMonths(doc *goquery.Document) ([]Month, error) {
+	table := doc.Find("table.common-table").First()
+	if table.Length() == 0 {
+		return nil, fmt.Errorf("%w: monthly table not found", ErrLayoutChanged)
+	}
+
+	columns := map[string]int{}
+	table.Find("thead th").Each(func(i int, s *goquery.Selection) {
+		columns[strings.ToLower(strings.TrimSpace(s.Text()))] = i
+	})
+	for _, name := range []string{columnMonth, columnAvg, columnGain, columnGainPercent, columnPeak} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: monthly table column %q not found", ErrLayoutChanged, name)
+		}
+	}
+
+	var months []Month
+	var rowErr error
+	table.Find("tbody tr").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		cells := s.Find("td").Map(func(_ int, c *goquery.Selection) string {
+			return strings.TrimSpace(c.Text())
+		})
+		if len(cells) < len(columns) {
+			rowErr = fmt.Errorf("%w: monthly row has %d cells, want %d", ErrLayoutChanged, len(cells), len(columns))
+			return false
+		}
+
+		month, err := parseMonth(cells, columns)
+		if err != nil {
+			rowErr = fmt.Errorf("%w: monthly row %q: %w", ErrLayoutChanged, cells[columns[columnMonth]], err)
+			return false
+		}
+		months = append(months, month)
+		return true
+	})
+	if rowErr != nil {
+		return nil, rowErr
+	}
+	if len(months) == 0 {
+		return nil, fmt.Errorf("%w: monthly table is empty", ErrLayoutChanged)
+	}
+
+	return months, nil
+}
+
+// parseMonth converts the cells of a monthly table row. Labels that aren't a
+// month and empty cells are errors, except in the rolling "Last 30 Days" row,
+// so markup changes are noticed instead of read as zero.
+func parseMonth(cells []string, columns map[string]int) (Month, error) {
+	month := Month{Label: cells[columns[columnMonth]]}
+	rolling := strings.EqualFold(month.Label, rollingLabel)
+	if !rolling {
+		date, err := time.Parse(monthLayout, month.Label)
+		if err != nil {
+			return Month{}, fmt.Errorf("parsing month %q: %w", month.Label, err)
+		}
+		month.Month = date
+	}
+
+	number := func(column string) (float64, error) {
+		cell := strings.TrimSuffix(cells[columns[column]], "%")
+		if rolling && cell == "" {
+			return 0, nil
+		}
+		return ParseFloat(cell)
+	}
+	var err error
+	if month.Avg, err = number(columnAvg); err != nil {
+		return Month{}, err
+	}
+	if month.Gain, err = number(columnGain); err != nil {
+		return Month{}, err
+	}
+	if month.GainPercent, err = number(columnGainPercent); err != nil {
+		return Month{}, err
+	}
+	if cell := cells[columns[columnPeak]]; !rolling || cell != "" {
+		if month.Peak, err = ParseInt(cell); err != nil {
+			return Month{}, err
+		}
+	}
+
+	return month, nil
+}
+
+// ParseInt parses a number such as "12,345", stripping thousands separators.
+func ParseInt(s string) (int, error) {
+	value, err := strconv.Atoi(strings.ReplaceAll(strings.TrimSpace(s), ",", ""))
+	if err != nil {
+		return 0, fmt.Errorf("parsing number %q: %w", s, err)
+	}
+	return value, nil
+}
+
+// ParseFloat parses a signed decimal such as "+1,234.5". The "-" SteamCharts
+// shows for the first month without a previous one parses as zero, an empty
+// string is an error.
+func ParseFloat(s string) (float64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	if s == "-" {
+		return 0, nil
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing number %q: %w", s, err)
+	}
+	return value, nil
+}
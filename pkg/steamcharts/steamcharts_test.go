@@ -0,0 +1,207 @@
+package steamcharts_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/steamcharts"
+)
+
+// parseFixture parses a saved page of testdata.
+func parseFixture(t *testing.T, name string) (steamcharts.Page, error) {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	return steamcharts.Parse(f)
+}
+
+// month returns the first day of a month in UTC.
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	september := steamcharts.Month{
+		Label:       "September 2025",
+		Month:       month(2025, time.September),
+		Avg:         14654.14,
+		Gain:        812.40,
+		GainPercent: 5.87,
+		Peak:        24581,
+	}
+
+	tests := []struct {
+		fixture string
+		want    steamcharts.Page
+	}{
+		{
+			fixture: "current.html",
+			want: steamcharts.Page{
+				Current:     11915,
+				Peak:        16982,
+				AllTimePeak: 65804,
+				Months: []steamcharts.Month{
+					{Label: "Last 30 Days", Avg: 13408.43, Gain: -1245.71, GainPercent: -8.5, Peak: 21319},
+					september,
+					{Label: "April 2014", Month: month(2014, time.April), Avg: 13841.74, Peak: 20384},
+				},
+			},
+		},
+		{
+			// Labels are matched by name, in any order and case, and unknown
+			// stats are ignored. Table columns are found by their header.
+			fixture: "reordered.html",
+			want: steamcharts.Page{
+				Current:     11915,
+				Peak:        16982,
+				AllTimePeak: 65804,
+				Months:      []steamcharts.Month{september},
+			},
+		},
+		{
+			fixture: "thousands.html",
+			want: steamcharts.Page{
+				Current:     1234567,
+				Peak:        1612404,
+				AllTimePeak: 1862531,
+				Months: []steamcharts.Month{{
+					Label:       "September 2025",
+					Month:       month(2025, time.September),
+					Avg:         1023456.78,
+					Gain:        -12345.6,
+					GainPercent: -1.19,
+					Peak:        1612404,
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			page, err := parseFixture(t, tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(page, tt.want) {
+				t.Errorf("Parse() = %+v\nwant %+v", page, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLayoutChanged(t *testing.T) {
+	tests := []struct {
+		fixture string
+		// reason is part of the error, telling what changed.
+		reason string
+	}{
+		{fixture: "broken-heading.html", reason: `heading "all-time peak" not found`},
+		{fixture: "broken-table.html", reason: `column "avg. players" not found`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			_, err := parseFixture(t, tt.fixture)
+			if !errors.Is(err, steamcharts.ErrLayoutChanged) {
+				t.Fatalf("Parse() error = %v, want ErrLayoutChanged", err)
+			}
+			if !strings.Contains(err.Error(), tt.reason) {
+				t.Errorf("Parse() error = %v, want it to mention %s", err, tt.reason)
+			}
+		})
+	}
+}
+
+func TestParseLayoutChangedInline(t *testing.T) {
+	heading := `<div id="app-heading">
+		<div class="app-stat"><span class="num">1</span> playing</div>
+		<div class="app-stat"><span class="num">2</span> 24-hour peak</div>
+		<div class="app-stat"><span class="num">3</span> all-time peak</div>
+	</div>`
+	header := `<thead><tr><th>Month</th><th>Avg. Players</th><th>Gain</th><th>% Gain</th><th>Peak Players</th></tr></thead>`
+
+	tests := map[string]string{
+		"empty page":         ``,
+		"number not numeric": strings.Replace(heading, ">2<", ">n/a<", 1),
+		"no table":           heading,
+		"empty table":        heading + `<table class="common-table">` + header + `<tbody></tbody></table>`,
+		"missing cells": heading + `<table class="common-table">` + header +
+			`<tbody><tr><td>September 2025</td><td>1</td></tr></tbody></table>`,
+		"invalid cell": heading + `<table class="common-table">` + header +
+			`<tbody><tr><td>September 2025</td><td>many</td><td>1</td><td>1%</td><td>1</td></tr></tbody></table>`,
+		"invalid month": heading + `<table class="common-table">` + header +
+			`<tbody><tr><td>Sept. 2025</td><td>1</td><td>1</td><td>1%</td><td>1</td></tr></tbody></table>`,
+		"empty month": heading + `<table class="common-table">` + header +
+			`<tbody><tr><td></td><td>1</td><td>1</td><td>1%</td><td>1</td></tr></tbody></table>`,
+		"empty average": heading + `<table class="common-table">` + header +
+			`<tbody><tr><td>September 2025</td><td></td><td>1</td><td>1%</td><td>1</td></tr></tbody></table>`,
+		"empty gain": heading + `<table class="common-table">` + header +
+			`<tbody><tr><td>September 2025</td><td>1</td><td>1</td><td>%</td><td>1</td></tr></tbody></table>`,
+		"empty peak": heading + `<table class="common-table">` + header +
+			`<tbody><tr><td>September 2025</td><td>1</td><td>1</td><td>1%</td><td> </td></tr></tbody></table>`,
+	}
+
+	for name, html := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := steamcharts.Parse(strings.NewReader(html)); !errors.Is(err, steamcharts.ErrLayoutChanged) {
+				t.Errorf("Parse() error = %v, want ErrLayoutChanged", err)
+			}
+		})
+	}
+}
+
+func TestParseRollingRow(t *testing.T) {
+	html := `<div id="app-heading">
+		<div class="app-stat"><span class="num">1</span> playing</div>
+		<div class="app-stat"><span class="num">2</span> 24-hour peak</div>
+		<div class="app-stat"><span class="num">3</span> all-time peak</div>
+	</div>
+	<table class="common-table">
+		<thead><tr><th>Month</th><th>Avg. Players</th><th>Gain</th><th>% Gain</th><th>Peak Players</th></tr></thead>
+		<tbody><tr><td>Last 30 Days</td><td>13408.43</td><td></td><td></td><td></td></tr></tbody>
+	</table>`
+
+	page, err := steamcharts.Parse(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The rolling row may leave cells empty, it isn't imported.
+	want := []steamcharts.Month{{Label: "Last 30 Days", Avg: 13408.43}}
+	if !reflect.DeepEqual(page.Months, want) {
+		t.Errorf("months = %+v, want %+v", page.Months, want)
+	}
+}
+
+func TestParseInt(t *testing.T) {
+	tests := map[string]int{"0": 0, "12345": 12345, " 12,345 ": 12345, "1,862,531": 1862531}
+	for input, want := range tests {
+		if got, err := steamcharts.ParseInt(input); err != nil || got != want {
+			t.Errorf("ParseInt(%q) = %d, %v, want %d", input, got, err, want)
+		}
+	}
+	if _, err := steamcharts.ParseInt("12.5k"); err == nil {
+		t.Error("ParseInt(12.5k) succeeded, want an error")
+	}
+}
+
+func TestParseFloat(t *testing.T) {
+	tests := map[string]float64{"-": 0, " - ": 0, "+812.40": 812.4, "-1,245.71": -1245.71, "1,023,456.78": 1023456.78}
+	for input, want := range tests {
+		if got, err := steamcharts.ParseFloat(input); err != nil || got != want {
+			t.Errorf("ParseFloat(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"n/a", "", " "} {
+		if _, err := steamcharts.ParseFloat(input); err == nil {
+			t.Errorf("ParseFloat(%q) succeeded, want an error", input)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package tui
+
+import "os"
+
+// Size returns 0, 0 where the terminal size can't be read, the caller falls
+// back to a default size.
+func Size(*os.File) (int, int) {
+	return 0, 0
+}
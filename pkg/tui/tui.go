@@ -0,0 +1,139 @@
+// Package tui draws full-screen views in ANSI terminals. It only knows what
+// the dashboard needs: an alternate screen redrawn line by line, colours and
+// the width of text with escape sequences in it.
+package tui
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Colour is an ANSI SGR parameter.
+type Colour string
+
+const (
+	Reset   Colour = "0"
+	Bold    Colour = "1"
+	Dim     Colour = "2"
+	Red     Colour = "31"
+	Green   Colour = "32"
+	Yellow  Colour = "33"
+	Cyan    Colour = "36"
+	OnRed   Colour = "30;41"
+	OnGreen Colour = "30;42"
+	OnAmber Colour = "30;43"
+	OnGrey  Colour = "30;47"
+)
+
+const (
+	escape         = "\x1b["
+	enterAltScreen = escape + "?1049h" + escape + "?25l"
+	leaveAltScreen = escape + "?25h" + escape + "?1049l"
+	home           = escape + "H"
+	clearLine      = escape + "K"
+	clearBelow     = escape + "J"
+)
+
+// Paint wraps text in a colour, resetting it afterwards.
+func Paint(text string, colour Colour) string {
+	return escape + string(colour) + "m" + text + escape + string(Reset) + "m"
+}
+
+// Width returns the number of cells text takes, ignoring escape sequences.
+func Width(text string) int {
+	width := 0
+	for i := 0; i < len(text); {
+		if n := escapeLength(text[i:]); n > 0 {
+			i += n
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		width++
+	}
+	return width
+}
+
+// Truncate cuts text to width cells, keeping its escape sequences so colours
+// are still reset.
+func Truncate(text string, width int) string {
+	var out strings.Builder
+	cells := 0
+	for i := 0; i < len(text); {
+		if n := escapeLength(text[i:]); n > 0 {
+			out.WriteString(text[i : i+n])
+			i += n
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		if cells < width {
+			out.WriteString(text[i : i+size])
+		}
+		i += size
+		cells++
+	}
+	return out.String()
+}
+
+// Pad fills text with spaces up to width cells.
+func Pad(text string, width int) string {
+	if n := width - Width(text); n > 0 {
+		return text + strings.Repeat(" ", n)
+	}
+	return text
+}
+
+// escapeLength returns the length of the CSI sequence text starts with, 0 if
+// it doesn't start with one.
+func escapeLength(text string) int {
+	if !strings.HasPrefix(text, escape) {
+		return 0
+	}
+	for i := len(escape); i < len(text); i++ {
+		if text[i] >= 0x40 && text[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(text)
+}
+
+// Screen is the alternate screen of a terminal. Frames are drawn over the
+// previous one, so the view doesn't flicker.
+type Screen struct {
+	w io.Writer
+}
+
+// Open switches the terminal to the alternate screen and hides the cursor.
+func Open(w io.Writer) (*Screen, error) {
+	if _, err := io.WriteString(w, enterAltScreen); err != nil {
+		return nil, err
+	}
+	return &Screen{w: w}, nil
+}
+
+// Close restores the screen the terminal showed before Open.
+func (s *Screen) Close() error {
+	_, err := io.WriteString(s.w, leaveAltScreen)
+	return err
+}
+
+// Draw replaces the screen content by lines, cut to width and height.
+func (s *Screen) Draw(lines []string, width, height int) error {
+	var frame bytes.Buffer
+	frame.WriteString(home)
+	for i, line := range lines {
+		if i == height {
+			break
+		}
+		if i > 0 {
+			frame.WriteString("\r\n")
+		}
+		frame.WriteString(Truncate(line, width))
+		frame.WriteString(clearLine)
+	}
+	frame.WriteString(clearBelow)
+	_, err := s.w.Write(frame.Bytes())
+	return err
+}
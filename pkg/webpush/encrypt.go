@@ -0,0 +1,183 @@
+package webpush
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RFC 8291 / RFC 8188 sizes.
+const (
+	saltSize      = 16
+	authSize      = 16
+	keySize       = 16
+	nonceSize     = 12
+	gcmTagSize    = 16
+	recordSize    = 4096
+	paddingMarker = 0x02
+	// publicKeySize is the size of an uncompressed P-256 public key, the key
+	// ID of the header.
+	publicKeySize = 65
+	// headerSize is the size of the header before the record: the salt, the
+	// record size, the key ID length and the key ID.
+	headerSize = saltSize + 4 + 1 + publicKeySize
+)
+
+// MaxPayloadSize is the largest payload that fits in a single record with
+// the header, push services reject bodies over 4096 bytes.
+const MaxPayloadSize = recordSize - headerSize - 1 - gcmTagSize
+
+// ErrPayloadTooLarge is returned when a payload doesn't fit in a single record.
+var ErrPayloadTooLarge = errors.New("push payload too large")
+
+// Subscription is a browser PushSubscription as serialized by toJSON().
+type Subscription struct {
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+}
+
+// Encrypt encrypts payload for the subscription with the aes128gcm content
+// encoding described in RFC 8291.
+func Encrypt(subscription Subscription, payload []byte) ([]byte, error) {
+	serverKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating ephemeral key: %w", err)
+	}
+	salt := make([]byte, saltSize)
+	if _, err = rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generating salt: %w", err)
+	}
+	return encrypt(subscription, payload, serverKey, salt)
+}
+
+// encrypt encrypts payload with the given ephemeral key and salt, which must
+// never be used twice.
+func encrypt(subscription Subscription, payload []byte, serverKey *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	if len(payload) > MaxPayloadSize {
+		return nil, ErrPayloadTooLarge
+	}
+
+	userAgentPublic, err := decodeKey(subscription.Keys.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("decoding p256dh key: %w", err)
+	}
+	authSecret, err := decodeKey(subscription.Keys.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("decoding auth secret: %w", err)
+	}
+	if len(authSecret) != authSize {
+		return nil, fmt.Errorf("auth secret has %d bytes, want %d", len(authSecret), authSize)
+	}
+
+	userAgentKey, err := ecdh.P256().NewPublicKey(userAgentPublic)
+	if err != nil {
+		return nil, fmt.Errorf("parsing p256dh key: %w", err)
+	}
+	sharedSecret, err := serverKey.ECDH(userAgentKey)
+	if err != nil {
+		return nil, fmt.Errorf("computing shared secret: %w", err)
+	}
+
+	serverPublic := serverKey.PublicKey().Bytes()
+	gcm, nonce, err := contentCipher(sharedSecret, authSecret, userAgentPublic, serverPublic, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	// A single, last record: the payload followed by the 0x02 delimiter.
+	plaintext := append(append([]byte(nil), payload...), paddingMarker)
+
+	header := make([]byte, 0, headerSize)
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(serverPublic)))
+	header = append(header, serverPublic...)
+
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// Decrypt is the user agent side of Encrypt: it decrypts a single record
+// aes128gcm body with the subscription's private key and auth secret. The
+// server doesn't need it; it lets push service stand-ins read what they get.
+func Decrypt(key *ecdh.PrivateKey, authSecret, body []byte) ([]byte, error) {
+	if len(body) < saltSize+4+1 {
+		return nil, errors.New("push body too short")
+	}
+	salt := body[:saltSize]
+	idLen := int(body[saltSize+4])
+	if len(body) < saltSize+4+1+idLen {
+		return nil, errors.New("push body too short")
+	}
+	serverPublic := body[saltSize+4+1 : saltSize+4+1+idLen]
+	ciphertext := body[saltSize+4+1+idLen:]
+
+	serverKey, err := ecdh.P256().NewPublicKey(serverPublic)
+	if err != nil {
+		return nil, fmt.Errorf("parsing server key: %w", err)
+	}
+	sharedSecret, err := key.ECDH(serverKey)
+	if err != nil {
+		return nil, fmt.Errorf("computing shared secret: %w", err)
+	}
+
+	gcm, nonce, err := contentCipher(sharedSecret, authSecret, key.PublicKey().Bytes(), serverPublic, salt)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting record: %w", err)
+	}
+
+	// Strip the padding up to the delimiter.
+	end := bytes.LastIndexByte(plaintext, paddingMarker)
+	if end < 0 {
+		return nil, errors.New("record delimiter missing")
+	}
+	return plaintext[:end], nil
+}
+
+// contentCipher derives the AES-GCM cipher and nonce of a message from the
+// ECDH shared secret, as described in RFC 8291 section 3.4.
+func contentCipher(sharedSecret, authSecret, userAgentPublic, serverPublic, salt []byte) (cipher.AEAD, []byte, error) {
+	keyInfo := "WebPush: info\x00" + string(userAgentPublic) + string(serverPublic)
+	ikm, err := hkdf.Key(sha256.New, sharedSecret, authSecret, keyInfo, sha256.Size)
+	if err != nil {
+		return nil, nil, fmt.Errorf("deriving input key: %w", err)
+	}
+	contentKey, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", keySize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("deriving content key: %w", err)
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", nonceSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("deriving nonce: %w", err)
+	}
+
+	block, err := aes.NewCipher(contentKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating gcm: %w", err)
+	}
+	return gcm, nonce, nil
+}
+
+// decodeKey decodes a base64url key, with or without padding.
+func decodeKey(key string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(key, "="))
+}
@@ -0,0 +1,143 @@
+package webpush_test
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/webpush"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	s := newSubscriber(t, "https://push.example.com/abc")
+	for _, payload := range [][]byte{
+		nil,
+		[]byte(`{"title":"PC-EU is online"}`),
+		bytes.Repeat([]byte("x"), webpush.MaxPayloadSize),
+	} {
+		body, err := webpush.Encrypt(s.subscription, payload)
+		if err != nil {
+			t.Fatalf("Encrypt(%d bytes): %v", len(payload), err)
+		}
+		got, err := webpush.Decrypt(s.key, s.authSecret, body)
+		if err != nil {
+			t.Fatalf("Decrypt(%d bytes): %v", len(payload), err)
+		}
+		if !bytes.Equal(got, payload) {
+			t.Errorf("Decrypt() = %q, want %q", got, payload)
+		}
+		if len(body) > 4096 {
+			t.Errorf("Encrypt(%d bytes) body has %d bytes, push services take 4096 at most", len(payload), len(body))
+		}
+	}
+}
+
+// rfc8291 is the example of RFC 8291 Appendix A.
+var rfc8291 = struct {
+	plaintext, serverPrivate, userAgentPrivate, userAgentPublic, authSecret, salt, body string
+}{
+	plaintext:        "When I grow up, I want to be a watermelon",
+	serverPrivate:    "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw",
+	userAgentPrivate: "q1dXpw3UpT5VOmu_cf_v6ih07Aems3njxI-JWgLcM94",
+	userAgentPublic:  "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4",
+	authSecret:       "BTBZMqHH6r4Tts7J_aSIgg",
+	salt:             "DGv6ra1nlYgDCS1FRnbzlw",
+	body: "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_" +
+		"yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN",
+}
+
+// decodeVector decodes a base64url value of the example.
+func decodeVector(t *testing.T, value string) []byte {
+	t.Helper()
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestEncryptKnownAnswer(t *testing.T) {
+	serverKey, err := ecdh.P256().NewPrivateKey(decodeVector(t, rfc8291.serverPrivate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var subscription webpush.Subscription
+	subscription.Keys.P256dh = rfc8291.userAgentPublic
+	subscription.Keys.Auth = rfc8291.authSecret
+
+	body, err := webpush.EncryptWith(subscription, []byte(rfc8291.plaintext), serverKey, decodeVector(t, rfc8291.salt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := base64.RawURLEncoding.EncodeToString(body); got != rfc8291.body {
+		t.Errorf("Encrypt() = %s\nwant %s", got, rfc8291.body)
+	}
+}
+
+func TestDecryptKnownAnswer(t *testing.T) {
+	userAgentKey, err := ecdh.P256().NewPrivateKey(decodeVector(t, rfc8291.userAgentPrivate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := webpush.Decrypt(userAgentKey, decodeVector(t, rfc8291.authSecret), decodeVector(t, rfc8291.body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != rfc8291.plaintext {
+		t.Errorf("Decrypt() = %q, want %q", plaintext, rfc8291.plaintext)
+	}
+}
+
+func TestEncryptIsRandomized(t *testing.T) {
+	s := newSubscriber(t, "https://push.example.com/abc")
+	first, err := webpush.Encrypt(s.subscription, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := webpush.Encrypt(s.subscription, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(first, second) {
+		t.Error("Encrypt() returned the same body twice, want a fresh salt and key")
+	}
+}
+
+func TestDecryptWithOtherSubscription(t *testing.T) {
+	s := newSubscriber(t, "https://push.example.com/abc")
+	other := newSubscriber(t, "https://push.example.com/def")
+	body, err := webpush.Encrypt(s.subscription, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = webpush.Decrypt(other.key, other.authSecret, body); err == nil {
+		t.Error("Decrypt() with another key succeeded")
+	}
+	if _, err = webpush.Decrypt(s.key, other.authSecret, body); err == nil {
+		t.Error("Decrypt() with another auth secret succeeded")
+	}
+	if _, err = webpush.Decrypt(s.key, s.authSecret, body[:20]); err == nil {
+		t.Error("Decrypt() of a truncated body succeeded")
+	}
+}
+
+func TestEncryptInvalid(t *testing.T) {
+	s := newSubscriber(t, "https://push.example.com/abc")
+	if _, err := webpush.Encrypt(s.subscription, make([]byte, webpush.MaxPayloadSize+1)); !errors.Is(err, webpush.ErrPayloadTooLarge) {
+		t.Errorf("Encrypt() of %d bytes error = %v, want ErrPayloadTooLarge", webpush.MaxPayloadSize+1, err)
+	}
+
+	badKey := s.subscription
+	badKey.Keys.P256dh = "not a key"
+	if _, err := webpush.Encrypt(badKey, []byte("hello")); err == nil {
+		t.Error("Encrypt() with an invalid p256dh key succeeded")
+	}
+
+	shortAuth := s.subscription
+	shortAuth.Keys.Auth = "c2hvcnQ"
+	if _, err := webpush.Encrypt(shortAuth, []byte("hello")); err == nil {
+		t.Error("Encrypt() with a short auth secret succeeded")
+	}
+}
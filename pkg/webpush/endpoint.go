@@ -0,0 +1,67 @@
+package webpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+)
+
+var (
+	// ErrInsecureEndpoint is returned for subscription endpoints that aren't https URLs.
+	ErrInsecureEndpoint = errors.New("push endpoint must be an https URL")
+	// ErrLocalEndpoint is returned for subscription endpoints on loopback,
+	// private or link-local addresses, which a push service never uses.
+	ErrLocalEndpoint = errors.New("push endpoint resolves to a local address")
+)
+
+// CheckEndpoint makes sure the endpoint is a push service the server may
+// post to: an https URL whose host only resolves to public addresses. With
+// allowLocal, http URLs and local addresses are accepted too, for local
+// stand-ins such as cmd/push-stub.
+func CheckEndpoint(ctx context.Context, endpoint string, allowLocal bool) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("invalid push endpoint %q", endpoint)
+	}
+	if u.Scheme != "https" && (!allowLocal || u.Scheme != "http") {
+		return ErrInsecureEndpoint
+	}
+	if allowLocal {
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("resolving push endpoint: %w", err)
+	}
+	for _, addr := range addrs {
+		if isLocal(addr) {
+			return ErrLocalEndpoint
+		}
+	}
+	return nil
+}
+
+// isLocal reports whether addr can't be the address of a public push service.
+func isLocal(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsUnspecified()
+}
+
+// publicOnly is a dialer control refusing connections to local addresses, so
+// an endpoint whose name later resolves to one isn't reached either.
+func publicOnly(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("parsing dialed address: %w", err)
+	}
+	if isLocal(addrPort.Addr()) {
+		return ErrLocalEndpoint
+	}
+	return nil
+}
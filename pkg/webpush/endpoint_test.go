@@ -0,0 +1,45 @@
+package webpush_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/webpush"
+)
+
+func TestCheckEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint   string
+		allowLocal bool
+		want       error
+	}{
+		{endpoint: "https://203.0.113.10/push/abc"},
+		{endpoint: "https://[2001:db8::1]/push"},
+		{endpoint: "http://203.0.113.10/push", want: webpush.ErrInsecureEndpoint},
+		{endpoint: "wss://203.0.113.10/push", want: webpush.ErrInsecureEndpoint},
+		{endpoint: "https://127.0.0.1:8090/push", want: webpush.ErrLocalEndpoint},
+		{endpoint: "https://10.1.2.3/push", want: webpush.ErrLocalEndpoint},
+		{endpoint: "https://172.16.0.1/push", want: webpush.ErrLocalEndpoint},
+		{endpoint: "https://192.168.0.1/push", want: webpush.ErrLocalEndpoint},
+		{endpoint: "https://169.254.169.254/push", want: webpush.ErrLocalEndpoint},
+		{endpoint: "https://0.0.0.0/push", want: webpush.ErrLocalEndpoint},
+		{endpoint: "https://[::1]/push", want: webpush.ErrLocalEndpoint},
+		{endpoint: "https://[fc00::1]/push", want: webpush.ErrLocalEndpoint},
+		{endpoint: "https://[::ffff:10.0.0.1]/push", want: webpush.ErrLocalEndpoint},
+		{endpoint: "http://127.0.0.1:8090/push", allowLocal: true},
+		{endpoint: "ftp://127.0.0.1/push", allowLocal: true, want: webpush.ErrInsecureEndpoint},
+	}
+	for _, tt := range tests {
+		err := webpush.CheckEndpoint(context.Background(), tt.endpoint, tt.allowLocal)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("CheckEndpoint(%q, %v) = %v, want %v", tt.endpoint, tt.allowLocal, err, tt.want)
+		}
+	}
+
+	for _, endpoint := range []string{"", "https://", "::not a url"} {
+		if err := webpush.CheckEndpoint(context.Background(), endpoint, true); err == nil {
+			t.Errorf("CheckEndpoint(%q) succeeded", endpoint)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+package webpush
+
+// EncryptWith exposes encrypt to the tests, which need a known ephemeral key
+// and salt.
+var EncryptWith = encrypt
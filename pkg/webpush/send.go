@@ -0,0 +1,89 @@
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/constant"
+)
+
+// defaultTTL is how long push services keep undelivered messages.
+const defaultTTL = 6 * time.Hour
+
+// ErrSubscriptionGone is returned when the push service no longer knows the
+// subscription, which should then be removed.
+var ErrSubscriptionGone = errors.New("push subscription expired or unsubscribed")
+
+// Sender delivers encrypted messages to push services.
+type Sender struct {
+	Keys VAPIDKeys
+	// Subject is the contact of the application server, a mailto: or https: URL.
+	Subject string
+	// Client posts the messages. When nil, a client refusing to connect to
+	// local addresses is used, unless AllowLocal is set.
+	Client     *http.Client
+	TTL        time.Duration
+	AllowLocal bool
+}
+
+// Send encrypts payload and posts it to the push service of the subscription.
+func (s Sender) Send(ctx context.Context, subscription Subscription, payload []byte) error {
+	body, err := Encrypt(subscription, payload)
+	if err != nil {
+		return err
+	}
+	authorization, err := s.Keys.authorization(subscription.Endpoint, s.Subject, time.Now())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	ttl := s.TTL
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(ttl.Seconds())))
+	req.Header.Set("Authorization", authorization)
+
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return fmt.Errorf("sending push message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrSubscriptionGone
+	case resp.StatusCode >= http.StatusMultipleChoices:
+		return fmt.Errorf("sending push message: unexpected status %s", resp.Status)
+	default:
+		return nil
+	}
+}
+
+// client returns the configured client or the default one.
+func (s Sender) client() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+	if s.AllowLocal {
+		return &http.Client{Timeout: constant.FetchTimeout}
+	}
+	dialer := &net.Dialer{Timeout: constant.FetchTimeout, Control: publicOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be the dialed address instead of the push service.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: constant.FetchTimeout, Transport: transport}
+}
@@ -0,0 +1,204 @@
+package webpush_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/webpush"
+)
+
+// subject is the contact sent by the tests.
+const subject = "mailto:admin@example.com"
+
+// pushService records the last message it received and answers with status.
+type pushService struct {
+	status  int
+	request *http.Request
+	body    []byte
+}
+
+// start serves the push service until the test ends and returns its URL.
+func (p *pushService) start(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		p.request, p.body = r, body
+		w.WriteHeader(p.status)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// verifyVAPID checks the Authorization header is a token for the endpoint
+// signed by keys.
+func verifyVAPID(t *testing.T, authorization, endpoint string, keys webpush.VAPIDKeys) {
+	t.Helper()
+	token, public, ok := strings.Cut(strings.TrimPrefix(authorization, "vapid t="), ", k=")
+	if !ok || public != keys.PublicKey() {
+		t.Fatalf("Authorization = %q, want the token and key %s", authorization, keys.PublicKey())
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("token %q isn't a JWT", token)
+	}
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var claims struct {
+		Aud string `json:"aud"`
+		Exp int64  `json:"exp"`
+		Sub string `json:"sub"`
+	}
+	if err = json.Unmarshal(claimsJSON, &claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims.Aud != endpoint || claims.Sub != subject || time.Until(time.Unix(claims.Exp, 0)) > 24*time.Hour {
+		t.Errorf("claims = %+v, want aud %s, sub %s and expiry within 24h", claims, endpoint, subject)
+	}
+
+	point, err := base64.RawURLEncoding.DecodeString(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(signature) != 64 {
+		t.Fatalf("signature %q isn't a 64 byte ES256 signature", parts[2])
+	}
+	key := ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(point[1:33]),
+		Y:     new(big.Int).SetBytes(point[33:]),
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !ecdsa.Verify(&key, digest[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])) {
+		t.Error("token signature doesn't verify with the public key")
+	}
+}
+
+func TestSend(t *testing.T) {
+	service := &pushService{status: http.StatusCreated}
+	url := service.start(t)
+	s := newSubscriber(t, url+"/push/abc")
+	keys, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender := webpush.Sender{Keys: keys, Subject: subject, TTL: time.Hour, AllowLocal: true}
+	if err = sender.Send(context.Background(), s.subscription, []byte("PC-EU is online")); err != nil {
+		t.Fatal(err)
+	}
+
+	if service.request.URL.Path != "/push/abc" {
+		t.Errorf("posted to %s, want /push/abc", service.request.URL.Path)
+	}
+	for header, want := range map[string]string{
+		"Content-Encoding": "aes128gcm",
+		"Content-Type":     "application/octet-stream",
+		"TTL":              "3600",
+	} {
+		if got := service.request.Header.Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+	verifyVAPID(t, service.request.Header.Get("Authorization"), url, keys)
+
+	message, err := webpush.Decrypt(s.key, s.authSecret, service.body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(message) != "PC-EU is online" {
+		t.Errorf("received %q, want the sent payload", message)
+	}
+}
+
+func TestSendErrors(t *testing.T) {
+	keys, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := webpush.Sender{Keys: keys, Subject: subject, AllowLocal: true}
+
+	for _, status := range []int{http.StatusNotFound, http.StatusGone} {
+		s := newSubscriber(t, (&pushService{status: status}).start(t))
+		if err = sender.Send(context.Background(), s.subscription, nil); !errors.Is(err, webpush.ErrSubscriptionGone) {
+			t.Errorf("status %d: error = %v, want ErrSubscriptionGone", status, err)
+		}
+	}
+
+	s := newSubscriber(t, (&pushService{status: http.StatusTooManyRequests}).start(t))
+	if err = sender.Send(context.Background(), s.subscription, nil); err == nil || errors.Is(err, webpush.ErrSubscriptionGone) {
+		t.Errorf("status 429: error = %v, want a failed send", err)
+	}
+}
+
+func TestSendRefusesLocalAddresses(t *testing.T) {
+	service := &pushService{status: http.StatusCreated}
+	s := newSubscriber(t, service.start(t))
+	keys, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = webpush.Sender{Keys: keys, Subject: subject}.Send(context.Background(), s.subscription, nil)
+	if !errors.Is(err, webpush.ErrLocalEndpoint) {
+		t.Errorf("error = %v, want ErrLocalEndpoint", err)
+	}
+	if service.request != nil {
+		t.Error("the local push service was reached")
+	}
+}
+
+func TestLoadOrCreateVAPIDKeys(t *testing.T) {
+	path := t.TempDir() + "/vapid.json"
+	created, err := webpush.LoadOrCreateVAPIDKeys(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := webpush.LoadOrCreateVAPIDKeys(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.PublicKey() != created.PublicKey() || len(created.PublicKey()) != 87 {
+		t.Errorf("loaded key %q, want the created one %q", loaded.PublicKey(), created.PublicKey())
+	}
+}
+
+func TestLoadOrCreateVAPIDKeysCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vapid.json")
+	if err := os.WriteFile(path, []byte(`{"publicKey":"BCVx`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	created, err := webpush.LoadOrCreateVAPIDKeys(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateVAPIDKeys() error = %v, want new keys", err)
+	}
+	loaded, err := webpush.LoadOrCreateVAPIDKeys(path)
+	if err != nil || loaded.PublicKey() != created.PublicKey() {
+		t.Errorf("loaded key %q, %v, want the created one %q", loaded.PublicKey(), err, created.PublicKey())
+	}
+	backups, err := filepath.Glob(path + ".corrupt-*")
+	if err != nil || len(backups) != 1 {
+		t.Errorf("backups = %v, %v, want the corrupt file kept", backups, err)
+	}
+}
@@ -0,0 +1,104 @@
+package webpush
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/jsonfile"
+)
+
+// Registration is a subscription together with the regions it wants to hear about.
+type Registration struct {
+	Subscription Subscription `json:"subscription"`
+	// Regions are the IDs of the watched regions, all regions when empty.
+	Regions []string `json:"regions"`
+}
+
+// Watches reports whether the registration wants updates for the region.
+func (r Registration) Watches(region string) bool {
+	return len(r.Regions) == 0 || slices.Contains(r.Regions, region)
+}
+
+// MaxRegistrations is how many registrations a store keeps at most by default.
+const MaxRegistrations = 10000
+
+// ErrStoreFull is returned when a new registration would exceed the limit of the store.
+var ErrStoreFull = errors.New("too many push subscriptions")
+
+// Store keeps the registrations in a JSON file.
+type Store struct {
+	// Limit is how many registrations are kept at most, MaxRegistrations if zero.
+	Limit int
+
+	path          string
+	mu            sync.Mutex
+	registrations []Registration
+}
+
+// OpenStore loads the registrations stored at path. A missing file is an
+// empty store, so is a file that can't be decoded, which is kept as a backup.
+func OpenStore(path string) (*Store, error) {
+	registrations, err := jsonfile.Load[[]Registration](path)
+	if err != nil {
+		return nil, fmt.Errorf("reading push subscriptions: %w", err)
+	}
+	return &Store{path: path, registrations: registrations}, nil
+}
+
+// List returns a copy of the registrations.
+func (s *Store) List() []Registration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.registrations)
+}
+
+// Put adds the registration or replaces the one with the same endpoint. New
+// registrations are refused with ErrStoreFull once the limit is reached.
+func (s *Store) Put(registration Registration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.registrations, func(r Registration) bool {
+		return r.Subscription.Endpoint == registration.Subscription.Endpoint
+	})
+	switch {
+	case i >= 0:
+		s.registrations[i] = registration
+	case len(s.registrations) >= s.limit():
+		return ErrStoreFull
+	default:
+		s.registrations = append(s.registrations, registration)
+	}
+	return s.save()
+}
+
+// limit returns the configured limit or the default one.
+func (s *Store) limit() int {
+	if s.Limit > 0 {
+		return s.Limit
+	}
+	return MaxRegistrations
+}
+
+// Remove deletes the registration of the endpoint.
+func (s *Store) Remove(endpoint string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.registrations = slices.DeleteFunc(s.registrations, func(r Registration) bool {
+		return r.Subscription.Endpoint == endpoint
+	})
+	return s.save()
+}
+
+// save writes the registrations to disk. The caller must hold the lock.
+func (s *Store) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("creating push subscription directory: %w", err)
+	}
+	return jsonfile.Write(s.path, s.registrations)
+}
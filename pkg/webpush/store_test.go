@@ -0,0 +1,110 @@
+package webpush_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/webpush"
+)
+
+// registration returns a registration of endpoint watching the regions.
+func registration(endpoint string, regions ...string) webpush.Registration {
+	r := webpush.Registration{Regions: regions}
+	r.Subscription.Endpoint = endpoint
+	return r
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "push", "subscriptions.json")
+	store, err := webpush.OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range []webpush.Registration{
+		registration("https://push.example.com/a"),
+		registration("https://push.example.com/b", "PC-EU"),
+		registration("https://push.example.com/a", "PC-NA"),
+	} {
+		if err = store.Put(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = store.Remove("https://push.example.com/b"); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := webpush.OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []webpush.Registration{registration("https://push.example.com/a", "PC-NA")}
+	if got := reopened.List(); !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %+v, want %+v", got, want)
+	}
+}
+
+func TestStoreLimit(t *testing.T) {
+	store, err := webpush.OpenStore(filepath.Join(t.TempDir(), "subscriptions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Limit = 2
+
+	for _, endpoint := range []string{"https://push.example.com/a", "https://push.example.com/b"} {
+		if err = store.Put(registration(endpoint)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = store.Put(registration("https://push.example.com/c")); !errors.Is(err, webpush.ErrStoreFull) {
+		t.Errorf("Put() beyond the limit error = %v, want ErrStoreFull", err)
+	}
+	if err = store.Put(registration("https://push.example.com/a", "PC-EU")); err != nil {
+		t.Errorf("Put() of a known endpoint error = %v, want it updated", err)
+	}
+	if n := len(store.List()); n != 2 {
+		t.Errorf("store keeps %d registrations, want 2", n)
+	}
+}
+
+func TestOpenStoreCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "subscriptions.json")
+	if err := os.WriteFile(path, []byte(`[{"subscription":{"endpoint":"https://push.exa`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := webpush.OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v, want an empty store", err)
+	}
+	if err = store.Put(registration("https://push.example.com/a")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The new store is written next to the backup, no temporary file is left.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if len(names) != 2 || names[0] != "subscriptions.json" || !strings.HasPrefix(names[1], "subscriptions.json.corrupt-") {
+		t.Errorf("directory holds %v, want the store and the backup of the corrupt file", names)
+	}
+}
+
+func TestWatches(t *testing.T) {
+	if !registration("https://push.example.com/a").Watches("PC-EU") {
+		t.Error("a registration without regions doesn't watch PC-EU")
+	}
+	r := registration("https://push.example.com/a", "PC-NA")
+	if r.Watches("PC-EU") || !r.Watches("PC-NA") {
+		t.Errorf("%+v watches the wrong regions", r)
+	}
+}
@@ -0,0 +1,138 @@
+// Package webpush sends Web Push messages: VAPID authentication (RFC 8292),
+// payload encryption (RFC 8291) and a file backed subscription store.
+package webpush
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/jsonfile"
+)
+
+// vapidTokenLifetime is how long a VAPID token is valid, at most 24 hours per RFC 8292.
+const vapidTokenLifetime = 12 * time.Hour
+
+// coordinateSize is the byte size of a P-256 coordinate.
+const coordinateSize = 32
+
+// VAPIDKeys identify the application server to push services.
+type VAPIDKeys struct {
+	private *ecdsa.PrivateKey
+}
+
+// storedVAPIDKeys is the on-disk format of VAPIDKeys.
+type storedVAPIDKeys struct {
+	PublicKey  string `json:"publicKey"`
+	PrivateKey string `json:"privateKey"`
+}
+
+// GenerateVAPIDKeys creates a new P-256 key pair.
+func GenerateVAPIDKeys() (VAPIDKeys, error) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return VAPIDKeys{}, fmt.Errorf("generating vapid keys: %w", err)
+	}
+	return VAPIDKeys{private: private}, nil
+}
+
+// LoadOrCreateVAPIDKeys reads the keys stored at path, creating and storing a
+// new pair when the file doesn't exist yet. A file that can't be decoded is
+// kept as a backup and replaced by a new pair, the subscriptions made with
+// the old one have to subscribe again.
+func LoadOrCreateVAPIDKeys(path string) (VAPIDKeys, error) {
+	stored, err := jsonfile.Load[storedVAPIDKeys](path)
+	if err != nil {
+		return VAPIDKeys{}, fmt.Errorf("reading vapid keys: %w", err)
+	}
+	if stored.PrivateKey == "" {
+		keys, generateErr := GenerateVAPIDKeys()
+		if generateErr != nil {
+			return VAPIDKeys{}, generateErr
+		}
+		return keys, keys.save(path)
+	}
+
+	der, err := base64.RawURLEncoding.DecodeString(stored.PrivateKey)
+	if err != nil {
+		return VAPIDKeys{}, fmt.Errorf("decoding vapid private key: %w", err)
+	}
+	private, err := x509.ParseECPrivateKey(der)
+	if err != nil {
+		return VAPIDKeys{}, fmt.Errorf("parsing vapid private key: %w", err)
+	}
+	return VAPIDKeys{private: private}, nil
+}
+
+// save writes the keys to path, readable by the owner only.
+func (k VAPIDKeys) save(path string) error {
+	der, err := x509.MarshalECPrivateKey(k.private)
+	if err != nil {
+		return fmt.Errorf("encoding vapid private key: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating vapid key directory: %w", err)
+	}
+	return jsonfile.Write(path, storedVAPIDKeys{
+		PublicKey:  k.PublicKey(),
+		PrivateKey: base64.RawURLEncoding.EncodeToString(der),
+	})
+}
+
+// PublicKey returns the uncompressed public key, base64url encoded, as
+// expected by PushManager.subscribe.
+func (k VAPIDKeys) PublicKey() string {
+	public, err := k.private.PublicKey.ECDH()
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(public.Bytes())
+}
+
+// authorization returns the Authorization header for a push to endpoint.
+func (k VAPIDKeys) authorization(endpoint, subject string, now time.Time) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("parsing endpoint: %w", err)
+	}
+
+	header, err := json.Marshal(map[string]string{"typ": "JWT", "alg": "ES256"})
+	if err != nil {
+		return "", fmt.Errorf("encoding jwt header: %w", err)
+	}
+	claims, err := json.Marshal(map[string]any{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": now.Add(vapidTokenLifetime).Unix(),
+		"sub": subject,
+	})
+	if err != nil {
+		return "", fmt.Errorf("encoding jwt claims: %w", err)
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, k.private, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("signing jwt: %w", err)
+	}
+
+	// ES256 signatures are the fixed size concatenation of r and s.
+	signature := append(padCoordinate(r), padCoordinate(s)...)
+	token := unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+	return "vapid t=" + token + ", k=" + k.PublicKey(), nil
+}
+
+// padCoordinate returns n as a big endian number of coordinateSize bytes.
+func padCoordinate(n *big.Int) []byte {
+	return n.FillBytes(make([]byte, coordinateSize))
+}
@@ -0,0 +1,36 @@
+package webpush_test
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/DanieltheDeveloper/go-eso-dashboard.git/pkg/webpush"
+)
+
+// subscriber is a browser side subscription with its private key.
+type subscriber struct {
+	key          *ecdh.PrivateKey
+	authSecret   []byte
+	subscription webpush.Subscription
+}
+
+// newSubscriber creates a subscription to endpoint like a browser would.
+func newSubscriber(t *testing.T, endpoint string) subscriber {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authSecret := make([]byte, 16)
+	if _, err = rand.Read(authSecret); err != nil {
+		t.Fatal(err)
+	}
+
+	s := subscriber{key: key, authSecret: authSecret}
+	s.subscription.Endpoint = endpoint
+	s.subscription.Keys.P256dh = base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes())
+	s.subscription.Keys.Auth = base64.RawURLEncoding.EncodeToString(authSecret)
+	return s
+}